@@ -19,6 +19,7 @@ package v1
 import (
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -83,6 +84,91 @@ type ConfigurationSpec struct {
 	KubeOVNPinger ResourceSpec `json:"kubeOvnPinger,omitempty"`
 	// +kubebuilder:default:={requests:{cpu:"200m",memory:"200Mi"},limits:{cpu:"200m",memory:"200Mi"}}
 	KubeOVNMonitor ResourceSpec `json:"kubeOvnMonitor,omitempty"`
+	// +kubebuilder:default:={}
+	Placement PlacementSpec `json:"placement,omitempty"`
+	// Overrides are patches applied to rendered objects before they are applied to the cluster
+	Overrides []ObjectOverride `json:"overrides,omitempty"`
+	// PruneMode controls how previously applied objects which are no longer rendered are handled.
+	// Delete removes them from the cluster, ReportOnly lists them in status.orphanedObjects
+	// +kubebuilder:default:="Delete"
+	// +kubebuilder:validation:Enum=Delete;ReportOnly
+	PruneMode string `json:"pruneMode,omitempty"`
+	// Version is the kube-ovn version to deploy. If not set the currently deployed version is retained,
+	// and new installs use the version the operator was started with. Minor versions can only be upgraded one at a time
+	// +kubebuilder:validation:Pattern=`^v?[0-9]+\.[0-9]+\.[0-9]+.*$`
+	Version string `json:"version,omitempty"`
+	// RevisionHistoryLimit is the number of revisions of rendered objects retained for rollback
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=1
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+	// RollbackTo is a revision number listed in status.currentRevision or in the revision history. While set, the
+	// objects stored in the revision are applied once instead of the objects rendered from the spec, and changes
+	// to the spec are only applied once it is cleared
+	// +kubebuilder:validation:Minimum=1
+	RollbackTo *int64 `json:"rollbackTo,omitempty"`
+	// Airgap configures the operator for clusters without access to external registries or networks
+	Airgap AirgapSpec `json:"airgap,omitempty"`
+	// CertManager configures cert-manager to issue the ovn tls material and the kube-ovn-webhook serving certificate
+	CertManager CertManagerSpec `json:"certManager,omitempty"`
+	// Paused stops the operator from applying rendered objects and cleaning up ovn databases when nodes
+	// are removed. This allows manual changes to managed objects during incident response
+	Paused bool `json:"paused,omitempty"`
+	// ApplyMode controls if rendered objects are applied to the cluster. Plan runs a server side dry run
+	// apply for all objects and records the resulting changes in status.plan without modifying the cluster
+	// +kubebuilder:default:="Apply"
+	// +kubebuilder:validation:Enum=Apply;Plan
+	ApplyMode string `json:"applyMode,omitempty"`
+	// Maintenance configures maintenance tasks run by the operator against the ovn databases
+	Maintenance MaintenanceSpec `json:"maintenance,omitempty"`
+}
+
+type MaintenanceSpec struct {
+	// ChassisGC deletes southbound chassis records which no longer belong to a node
+	ChassisGC ChassisGCSpec `json:"chassisGC,omitempty"`
+	// Compaction compacts the northbound and southbound databases once they exceed size thresholds
+	Compaction CompactionSpec `json:"compaction,omitempty"`
+	// AutoRecoverRaftMembers rebuilds raft members which are disconnected from the leader or fail to start
+	// because of a corrupt database
+	AutoRecoverRaftMembers RaftMemberRecoverySpec `json:"autoRecoverRaftMembers,omitempty"`
+}
+
+// ChassisGCSpec deletes stale chassis reported in status.chassis.staleChassis once they have been stale
+// for longer than the grace period
+type ChassisGCSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Interval between garbage collection runs, defaults to 1h
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// GracePeriod is how long the hostname of a chassis has to be without a matching node before the chassis
+	// is deleted, defaults to 24h
+	GracePeriod metav1.Duration `json:"gracePeriod,omitempty"`
+	// DryRun reports the chassis which would be deleted in status.chassis.collectedChassis without deleting them
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// CompactionSpec runs ovsdb-server/compact on all members of a database once the file size or raft log length
+// of the leader reported in status.ovnDatabases exceeds a threshold. Compaction of both databases can also be
+// requested at any time by annotating the configuration with kubeovn.io/compact-databases. This is independent
+// of ENABLE_COMPACT, which only controls the automatic compaction of ovsdb-server itself
+type CompactionSpec struct {
+	// FileSizeThreshold is the size of the database file above which the database is compacted
+	FileSizeThreshold *resource.Quantity `json:"fileSizeThreshold,omitempty"`
+	// LogLengthThreshold is the number of raft log entries above which the database is compacted
+	LogLengthThreshold int64 `json:"logLengthThreshold,omitempty"`
+	// MinInterval is the minimum time between compactions triggered by thresholds, defaults to 1h
+	MinInterval metav1.Duration `json:"minInterval,omitempty"`
+}
+
+// RaftMemberRecoverySpec runs the kube-ovn rebuild member procedure for master nodes reported in
+// status.raftRecovery. The member is kicked from the cluster by the leader, the database files on the node are
+// moved aside by a privileged job, and ovn-central on the node is restarted to join the cluster again
+type RaftMemberRecoverySpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// RequireApproval waits for the configuration to be annotated with kubeovn.io/approve-raft-recovery set
+	// to a comma separated list of node names before members on those nodes are recovered
+	RequireApproval bool `json:"requireApproval,omitempty"`
+	// GracePeriod is how long a member has to be disconnected from the leader before it is recovered, defaults
+	// to 10m. Members with a corrupt database are recovered without waiting
+	GracePeriod metav1.Duration `json:"gracePeriod,omitempty"`
 }
 
 type GlobalSpec struct {
@@ -99,12 +185,35 @@ type RegistrySpec struct {
 type ImageDetails struct {
 	KubeOVNImage    KubeOVNImageSpec    `json:"kubeovn,omitempty"`
 	NATGatewayImage NATGatewayImageSpec `json:"natgateway,omitempty"`
+	// Components overrides the image used by individual components
+	Components ComponentImages `json:"components,omitempty"`
+}
+
+// ComponentImages allows the image of each component to be overridden or pinned to a digest
+type ComponentImages struct {
+	OVNCentral        ComponentImage `json:"ovnCentral,omitempty"`
+	OVSOVN            ComponentImage `json:"ovsOVN,omitempty"`
+	KubeOVNController ComponentImage `json:"kubeOvnController,omitempty"`
+	KubeOVNCNI        ComponentImage `json:"kubeOvnCNI,omitempty"`
+	KubeOVNPinger     ComponentImage `json:"kubeOvnPinger,omitempty"`
+	KubeOVNMonitor    ComponentImage `json:"kubeOvnMonitor,omitempty"`
+	NATGateway        ComponentImage `json:"natGateway,omitempty"`
+}
+
+// ComponentImage is resolved against the registry address. Repository and tag default to
+// the kube-ovn image, or the nat gateway image for the natGateway component
+type ComponentImage struct {
+	Repository string `json:"repository,omitempty"`
+	Tag        string `json:"tag,omitempty"`
+	// Digest pins the image and takes precedence over the tag
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Digest string `json:"digest,omitempty"`
 }
 
 type KubeOVNImageSpec struct {
 	// +kubebuilder:default:="kube-ovn"
 	Repository string `json:"repository,omitempty"`
-	Tag        string `json:"tag,omitempty"` // defaults to version passed from command line
+	Tag        string `json:"tag,omitempty"` // replaced by the version being rolled out, and must match spec.version when both are set
 	// +kubebuilder:default:="kube-ovn-dpdk"
 	DpdkRepository string `json:"dpdkRepository,omitempty"`
 	// +kubebuilder:default:="vpc-nat-gateway"
@@ -114,7 +223,7 @@ type KubeOVNImageSpec struct {
 type NATGatewayImageSpec struct {
 	// +kubebuilder:default:="vpc-nat-gateway"
 	Repository string `json:"repository,omitempty"`
-	Tag        string `json:"tag,omitempty"` // defaults to version passed from command line
+	Tag        string `json:"tag,omitempty"` // defaults to the kube-ovn image tag
 }
 
 type NetworkingSpec struct {
@@ -317,6 +426,83 @@ type ResourceSpec struct {
 	Limits CPUMemSpec `json:"limits,omitempty"`
 }
 
+// PlacementSpec allows users to override scheduling constraints for individual kubeovn components
+type PlacementSpec struct {
+	OVNCentral        ComponentPlacement `json:"ovnCentral,omitempty"`
+	KubeOVNController ComponentPlacement `json:"kubeOvnController,omitempty"`
+	OVSOVN            ComponentPlacement `json:"ovsOVN,omitempty"`
+	KubeOVNCNI        ComponentPlacement `json:"kubeOvnCNI,omitempty"`
+	KubeOVNPinger     ComponentPlacement `json:"kubeOvnPinger,omitempty"`
+	KubeOVNMonitor    ComponentPlacement `json:"kubeOvnMonitor,omitempty"`
+	OVNICController   ComponentPlacement `json:"ovnICController,omitempty"`
+}
+
+// ComponentPlacement is merged into the scheduling defaults of the component templates.
+// nodeSelector entries are added to the default selector, tolerations are appended to the default
+// tolerations, and each of nodeAffinity, podAffinity and podAntiAffinity replaces the matching default block
+type ComponentPlacement struct {
+	NodeSelector      map[string]string   `json:"nodeSelector,omitempty"`
+	Tolerations       []corev1.Toleration `json:"tolerations,omitempty"`
+	Affinity          *corev1.Affinity    `json:"affinity,omitempty"`
+	PriorityClassName string              `json:"priorityClassName,omitempty"`
+}
+
+// AirgapSpec rewrites all rendered images onto the registry address, attaches the registry image pull secrets
+// to all service accounts and disables the external connectivity checks run by kube-ovn-pinger
+type AirgapSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// PathMappings rewrite image references with a matching prefix. Images which do not match a mapping and
+	// are not already using the registry address are moved to the registry address, retaining only the image name
+	PathMappings []ImagePathMapping `json:"pathMappings,omitempty"`
+}
+
+// CertManagerSpec replaces the operator generated kube-ovn-tls secret and the shared webhook certificate with
+// cert-manager Certificates. The cert-manager CRDs need to be installed before enabling this
+type CertManagerSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// IssuerRef references an existing issuer. If not set a self signed CA issuer is created in the kube-ovn namespace
+	IssuerRef *CertManagerIssuerReference `json:"issuerRef,omitempty"`
+	// Duration of the issued certificates, defaults to the cert-manager default of 90 days
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// RenewBefore is the time before expiry at which cert-manager renews the certificates
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// CertManagerIssuerReference references a cert-manager Issuer or ClusterIssuer
+type CertManagerIssuerReference struct {
+	Name string `json:"name"`
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default:=Issuer
+	Kind string `json:"kind,omitempty"`
+	// +kubebuilder:default:=cert-manager.io
+	Group string `json:"group,omitempty"`
+}
+
+// ImagePathMapping replaces the Source prefix of an image reference with Target
+type ImagePathMapping struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// ObjectOverride patches a rendered object identified by its GroupVersionKind, name and namespace
+type ObjectOverride struct {
+	Target OverrideTarget `json:"target"`
+	// +kubebuilder:default:="StrategicMerge"
+	// +kubebuilder:validation:Enum=StrategicMerge;JSON6902
+	PatchType string `json:"patchType,omitempty"`
+	// Patch is the yaml or json patch content. StrategicMerge patches are partial objects, and are applied as json
+	// merge patches to kinds without a builtin schema, such as cert-manager objects. JSON6902 patches are a list of
+	// operations
+	Patch string `json:"patch"`
+}
+
+type OverrideTarget struct {
+	GroupVersionKind `json:",inline"`
+	Name             string `json:"name"`
+	// Namespace is optional and only needs to be set to disambiguate namespaced objects
+	Namespace string `json:"namespace,omitempty"`
+}
+
 type CPUMemSpec struct {
 	CPU              resource.Quantity `json:"cpu,omitempty"`
 	Memory           resource.Quantity `json:"memory,omitempty"`
@@ -329,11 +515,163 @@ type ConfigurationStatus struct {
 	Conditions            []metav1.Condition `json:"conditions,omitempty"`
 	Status                string             `json:"status,omitempty"`
 	ManagedObjects        []ObjectReference  `json:"managedObjects,omitempty"`
+	// OrphanedObjects are objects no longer rendered by the configuration which have not been pruned
+	OrphanedObjects []ObjectReference `json:"orphanedObjects,omitempty"`
+	// CurrentVersion is the kube-ovn version which has been successfully rolled out
+	CurrentVersion string `json:"currentVersion,omitempty"`
+	// TargetVersion is the kube-ovn version being rolled out
+	TargetVersion string `json:"targetVersion,omitempty"`
+	// Images is the resolved image for each component
+	Images map[string]string `json:"images,omitempty"`
+	// CurrentRevision is the revision of rendered objects which was last applied
+	CurrentRevision int64 `json:"currentRevision,omitempty"`
+	// RolledBackRevision is the revision applied for spec.rollbackTo, which is not applied again until
+	// spec.rollbackTo is changed
+	RolledBackRevision int64 `json:"rolledBackRevision,omitempty"`
+	// Phase is the rollout phase currently being applied, or Complete once all phases are healthy
+	Phase string `json:"phase,omitempty"`
+	// PhaseMessage describes what the current phase is waiting on
+	PhaseMessage string `json:"phaseMessage,omitempty"`
+	// Plan lists the changes which would be made to each rendered object when spec.applyMode is Plan
+	Plan []ObjectPlan `json:"plan,omitempty"`
+	// OVNDatabases is the raft cluster status of the ovn northbound and southbound databases reported by the leader. An entry is cleared while the database has no leader
+	OVNDatabases OVNDatabasesStatus `json:"ovnDatabases,omitempty"`
+	// Chassis lists inconsistencies between the southbound chassis records and the cluster nodes
+	Chassis ChassisAuditStatus `json:"chassis,omitempty"`
+	// RaftRecovery are the master nodes with broken raft members and the progress of their recovery
+	RaftRecovery []RaftMemberRecoveryStatus `json:"raftRecovery,omitempty"`
+	// MatchingNodes are the master nodes and their internal address when status.matchingNodeAddresses was
+	// last updated
+	MatchingNodes []MatchingNode `json:"matchingNodes,omitempty"`
+	// AddressChanges are master nodes whose internal address changed. status.matchingNodeAddresses is only
+	// updated once the previous address of each node has been removed from the raft clusters
+	AddressChanges []NodeAddressChange `json:"addressChanges,omitempty"`
+}
+
+type MatchingNode struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// NodeAddressChange tracks the removal of the previous address of a master node from the raft clusters
+type NodeAddressChange struct {
+	Node            string `json:"node"`
+	PreviousAddress string `json:"previousAddress"`
+	Address         string `json:"address"`
+	// Phase is Detected until the previous address is kicked, and Rebuilding while the database files on the
+	// node are moved aside
+	Phase   string `json:"phase"`
+	Message string `json:"message,omitempty"`
+	// JobName is the job moving the database files aside
+	JobName            string      `json:"jobName,omitempty"`
+	DetectedTime       metav1.Time `json:"detectedTime"`
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// ChassisAuditStatus is the result of comparing southbound chassis records with the nodes and ovs-ovn pods
+type ChassisAuditStatus struct {
+	// StaleChassis are chassis whose hostname does not match any node
+	StaleChassis []ChassisReference `json:"staleChassis,omitempty"`
+	// NodesWithoutChassis are nodes running an ovs-ovn pod without a matching chassis
+	NodesWithoutChassis []string `json:"nodesWithoutChassis,omitempty"`
+	// DuplicateChassis are chassis sharing their hostname with another chassis
+	DuplicateChassis []ChassisReference `json:"duplicateChassis,omitempty"`
+	LastAuditTime    metav1.Time        `json:"lastAuditTime,omitempty"`
+	// CollectedChassis are the chassis deleted by the last garbage collection run, or the chassis which would
+	// have been deleted when spec.maintenance.chassisGC.dryRun is set
+	CollectedChassis   []ChassisReference `json:"collectedChassis,omitempty"`
+	LastCollectionTime metav1.Time        `json:"lastCollectionTime,omitempty"`
 }
 
+type ChassisReference struct {
+	Name     string `json:"name"`
+	Hostname string `json:"hostname,omitempty"`
+	// DetectedTime is when the chassis was first reported by the audit
+	DetectedTime metav1.Time `json:"detectedTime,omitempty"`
+}
+
+type OVNDatabasesStatus struct {
+	Northbound *OVNDatabaseStatus `json:"northbound,omitempty"`
+	Southbound *OVNDatabaseStatus `json:"southbound,omitempty"`
+}
+
+// OVNDatabaseStatus is the parsed output of ovs-appctl cluster/status for a database
+type OVNDatabaseStatus struct {
+	ServerID string `json:"serverID,omitempty"`
+	Address  string `json:"address,omitempty"`
+	// Role is one of leader, follower or candidate
+	Role string `json:"role,omitempty"`
+	Term int64  `json:"term,omitempty"`
+	// Leader is the server id of the leader, self if the server is the leader or unknown during elections
+	Leader string `json:"leader,omitempty"`
+	// ElectionTimer is the raft election timer in milliseconds
+	ElectionTimer int64 `json:"electionTimer,omitempty"`
+	// LogStartIndex and LogEndIndex are the first and last index of the raft log
+	LogStartIndex int64                     `json:"logStartIndex,omitempty"`
+	LogEndIndex   int64                     `json:"logEndIndex,omitempty"`
+	Members       []OVNDatabaseMemberStatus `json:"members,omitempty"`
+	// MissingAddresses are entries in status.matchingNodeAddresses which are not a member of the raft cluster
+	MissingAddresses []string `json:"missingAddresses,omitempty"`
+	// UnexpectedAddresses are raft members whose address is not in status.matchingNodeAddresses
+	UnexpectedAddresses []string `json:"unexpectedAddresses,omitempty"`
+	// LogLength is the number of entries in the raft log, from LogStartIndex to LogEndIndex
+	LogLength int64 `json:"logLength,omitempty"`
+	// FileSize is the size of the database file on the leader in bytes
+	FileSize int64 `json:"fileSize,omitempty"`
+	// Memory is the output of ovs-appctl memory/show on the leader, such as cells, monitors and raft-log
+	Memory             map[string]int64 `json:"memory,omitempty"`
+	LastCompactionTime *metav1.Time     `json:"lastCompactionTime,omitempty"`
+	LastUpdateTime     metav1.Time      `json:"lastUpdateTime,omitempty"`
+}
+
+// RaftMemberRecoveryStatus tracks the recovery of the raft members on a master node
+type RaftMemberRecoveryStatus struct {
+	Node    string `json:"node"`
+	Address string `json:"address"`
+	// Databases are the databases with a broken member on the node
+	Databases []string `json:"databases"`
+	// Reason is Disconnected or CorruptDatabase
+	Reason string `json:"reason"`
+	// Phase is one of Detected, AwaitingApproval, Recovering, Rejoining or Failed
+	Phase   string `json:"phase"`
+	Message string `json:"message,omitempty"`
+	// JobName is the job moving the database files aside
+	JobName            string      `json:"jobName,omitempty"`
+	DetectedTime       metav1.Time `json:"detectedTime"`
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// OVNDatabaseMemberStatus is a member of the raft cluster as seen by the reporting server
+type OVNDatabaseMemberStatus struct {
+	ServerID string `json:"serverID"`
+	Address  string `json:"address"`
+	// Connection is Self for the reporting server, Connected when both inbound and outbound connections
+	// exist, Inbound or Outbound if only one exists, and Disconnected otherwise
+	Connection string `json:"connection"`
+}
+
+// ObjectReference identifies an object applied by the operator
 type ObjectReference struct {
-	GVK  GroupVersionKind `json:"gvk,omitempty"`
-	Name string           `json:"name,omitempty"`
+	GVK       GroupVersionKind `json:"gvk,omitempty"`
+	Namespace string           `json:"namespace,omitempty"`
+	Name      string           `json:"name,omitempty"`
+	// ResourceVersion is the resource version returned by the apiserver when the object was applied
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	// Hash is a sha256 hash of the rendered object content
+	Hash string `json:"hash,omitempty"`
+	// Unmanaged is set when the object is annotated with kubeovn.io/unmanaged=true and is no longer being applied
+	Unmanaged bool `json:"unmanaged,omitempty"`
+}
+
+// ObjectPlan summarises the change server side apply would make to a rendered object
+type ObjectPlan struct {
+	GVK       GroupVersionKind `json:"gvk,omitempty"`
+	Namespace string           `json:"namespace,omitempty"`
+	Name      string           `json:"name,omitempty"`
+	// Action is one of Create, Change, Unchanged, Unmanaged or Delete
+	Action string `json:"action"`
+	// ChangedFields are the paths of fields which would be modified by the apply
+	ChangedFields []string `json:"changedFields,omitempty"`
 }
 
 type GroupVersionKind struct {
@@ -386,6 +724,37 @@ const (
 	OVNCentralContainerName          = "ovn-central"
 	NBCheckScript                    = `ovs-appctl -t /var/run/ovn/ovnnb_db.ctl cluster/status OVN_Northbound`
 	SBCheckScript                    = `ovs-appctl -t /var/run/ovn/ovnsb_db.ctl cluster/status OVN_Southbound`
+	NorthdCheckScript                = `ovn-appctl -t ovn-northd status`
+	OVNCentralLabel                  = "app=ovn-central"
+	OVSOVNLabel                      = "app=ovs"
+	ChassisListScript                = `ovn-sbctl --format=csv --no-headings --data=bare --columns=name,hostname list chassis`
+	NBMemoryScript                   = `ovs-appctl -t /var/run/ovn/ovnnb_db.ctl memory/show`
+	SBMemoryScript                   = `ovs-appctl -t /var/run/ovn/ovnsb_db.ctl memory/show`
+	NBFileSizeScript                 = `stat -c %s /etc/ovn/ovnnb_db.db`
+	SBFileSizeScript                 = `stat -c %s /etc/ovn/ovnsb_db.db`
+	NBCompactScript                  = `ovs-appctl -t /var/run/ovn/ovnnb_db.ctl ovsdb-server/compact OVN_Northbound`
+	SBCompactScript                  = `ovs-appctl -t /var/run/ovn/ovnsb_db.ctl ovsdb-server/compact OVN_Southbound`
+	// RaftRecoveryApprovalAnnotation approves recovery of the raft members on a comma separated list of nodes
+	RaftRecoveryApprovalAnnotation    = "kubeovn.io/approve-raft-recovery"
+	RaftRecoveryDisconnectedReason    = "Disconnected"
+	RaftRecoveryCorruptDatabaseReason = "CorruptDatabase"
+	RaftRecoveryPhaseDetected         = "Detected"
+	RaftRecoveryPhaseAwaitingApproval = "AwaitingApproval"
+	RaftRecoveryPhaseRecovering       = "Recovering"
+	RaftRecoveryPhaseRejoining        = "Rejoining"
+	RaftRecoveryPhaseFailed           = "Failed"
+	NodeAddressChangePhaseDetected    = "Detected"
+	NodeAddressChangePhaseRebuilding  = "Rebuilding"
+	// CompactAnnotation requests compaction of both databases, the annotation is removed once compaction has run
+	CompactAnnotation                = "kubeovn.io/compact-databases"
+	ChassisConsistent                = "ovnChassisConsistent"
+	ChassisConsistentReason          = "ChassisConsistent"
+	ChassisInconsistentReason        = "ChassisInconsistent"
+	OVNNorthdHealth                  = "ovnNorthdHealth"
+	NorthdHealthyReason              = "NorthdHealthy"
+	NorthdDegradedReason             = "Degraded"
+	NorthdStatusActive               = "active"
+	NorthdStatusStandby              = "standby"
 	NodesFoundReason                 = "NodesFound"
 	NodesNotFoundReason              = "NodesNotFound"
 	ConditionUnknown                 = "ConditionUnknown"
@@ -394,7 +763,58 @@ const (
 	LeaderFound                      = "LeaderFound"
 	LeaderNotFound                   = "LeaderNotFound"
 	DBHealth                         = "DBHealth"
+	OVNNBRaftMembers                 = "ovnNBRaftMembers"
+	OVNSBRaftMembers                 = "ovnSBRaftMembers"
+	RaftMembersMatchReason           = "RaftMembersMatch"
+	RaftMembersMismatchReason        = "RaftMembersMismatch"
+	RaftConnectionSelf               = "Self"
+	RaftConnectionConnected          = "Connected"
+	RaftConnectionInbound            = "Inbound"
+	RaftConnectionOutbound           = "Outbound"
+	RaftConnectionDisconnected       = "Disconnected"
 	KubeOVNOperatorWebhookCertSecret = "webhook-certs" //nolint:gosec
+	StrategicMergePatchType          = "StrategicMerge"
+	JSON6902PatchType                = "JSON6902"
+	PruneModeDelete                  = "Delete"
+	PruneModeReportOnly              = "ReportOnly"
+	PausedCondition                  = "Paused"
+	PausedReason                     = "Paused"
+	ResumedReason                    = "Resumed"
+	UnmanagedAnnotation              = "kubeovn.io/unmanaged"
+	// CertManagerCertificateAnnotation is added by cert-manager to secrets it issues
+	CertManagerCertificateAnnotation = "cert-manager.io/certificate-name"
+	ApplyModeApply                   = "Apply"
+	ApplyModePlan                    = "Plan"
+	PlannedCondition                 = "Planned"
+	PlanGeneratedReason              = "PlanGenerated"
+	PlanDisabledReason               = "PlanDisabled"
+	PlanActionCreate                 = "Create"
+	PlanActionChange                 = "Change"
+	PlanActionUnchanged              = "Unchanged"
+	PlanActionUnmanaged              = "Unmanaged"
+	PlanActionDelete                 = "Delete"
+	UpgradingCondition               = "Upgrading"
+	UpgradingReason                  = "Upgrading"
+	UpgradeBlockedReason             = "UpgradeBlocked"
+	UpgradeCompleteReason            = "UpgradeComplete"
+	RolledBackCondition              = "RolledBack"
+	RolledBackReason                 = "RolledBack"
+	RevisionNotFoundReason           = "RevisionNotFound"
+	RollbackClearedReason            = "RollbackCleared"
+	RevisionConfigurationLabel       = "kubeovn.io/configuration"
+	RevisionSpecHashAnnotation       = "kubeovn.io/spec-hash"
+	RevisionManifestHashAnnotation   = "kubeovn.io/manifest-hash"
+	RevisionVersionAnnotation        = "kubeovn.io/version"
+	OVNTLSSecret                     = "kube-ovn-tls"    //nolint:gosec
+	OVNCASecret                      = "kube-ovn-pki-ca" //nolint:gosec
+	TLSCertificateExpiryCondition    = "TLSCertificateExpiry"
+	CertificateValidReason           = "CertificateValid"
+	CertificateRotationPendingReason = "CertificateRotationPending"
+	CertificateInvalidReason         = "CertificateInvalid"
+	BuiltInPKIDisabledReason         = "BuiltInPKIDisabled"
+	// TLSChecksumAnnotation is added to pod templates of components using the ovn tls material, so that
+	// rotated certificates result in a rolling restart
+	TLSChecksumAnnotation = "kubeovn.io/tls-checksum"
 )
 
 var (
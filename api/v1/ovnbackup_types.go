@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OVNBackupSpec defines where the snapshots of the ovn databases are stored
+type OVNBackupSpec struct {
+	Target BackupTarget `json:"target"`
+}
+
+// BackupTarget is the storage for backups. Exactly one of the targets needs to be set
+type BackupTarget struct {
+	// PersistentVolumeClaim stores the backups on a volume mounted by a short lived pod
+	PersistentVolumeClaim *PVCBackupTarget `json:"persistentVolumeClaim,omitempty"`
+	// Object stores the backups split into chunks across Secrets or ConfigMaps in the operator namespace
+	Object *ObjectBackupTarget `json:"object,omitempty"`
+	// S3 stores the backups in a bucket of an S3 compatible endpoint
+	S3 *S3BackupTarget `json:"s3,omitempty"`
+}
+
+type PVCBackupTarget struct {
+	ClaimName string `json:"claimName"`
+	// Path is the directory on the volume backups are written to
+	// +kubebuilder:default:="/"
+	Path string `json:"path,omitempty"`
+}
+
+type ObjectBackupTarget struct {
+	// +kubebuilder:default:="Secret"
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	Kind string `json:"kind,omitempty"`
+}
+
+type S3BackupTarget struct {
+	// Endpoint is the url of the S3 endpoint, for example https://minio.example.com:9000
+	Endpoint string `json:"endpoint"`
+	Bucket   string `json:"bucket"`
+	// Prefix is prepended to object keys
+	Prefix string `json:"prefix,omitempty"`
+	// +kubebuilder:default:="us-east-1"
+	Region string `json:"region,omitempty"`
+	// CredentialsSecret is a secret in the operator namespace containing the accessKeyID and secretAccessKey keys
+	CredentialsSecret string `json:"credentialsSecret"`
+	// InsecureSkipTLSVerify disables verification of the endpoint certificate
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+}
+
+// OVNBackupStatus defines the observed state of OVNBackup.
+type OVNBackupStatus struct {
+	// Phase is one of Pending, Running, Completed or Failed
+	Phase          string       `json:"phase,omitempty"`
+	Message        string       `json:"message,omitempty"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Artifacts are the stored snapshots of each database
+	Artifacts []BackupArtifact `json:"artifacts,omitempty"`
+}
+
+// BackupArtifact is a gzip compressed snapshot of a database generated by ovsdb-client backup
+type BackupArtifact struct {
+	// Database is OVN_Northbound or OVN_Southbound
+	Database string `json:"database"`
+	// Location is the file path, object name prefix or object key the snapshot is stored at
+	Location string `json:"location"`
+	// Size is the compressed size in bytes
+	Size int64 `json:"size"`
+	// Checksum is the sha256 checksum of the compressed snapshot
+	Checksum string `json:"checksum"`
+	// Leader is the ovn-central pod the snapshot was taken from
+	Leader string `json:"leader,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Completed",type=date,JSONPath=`.status.completionTime`
+
+// OVNBackup is a snapshot of the ovn northbound and southbound databases taken from the raft leaders
+type OVNBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OVNBackupSpec   `json:"spec,omitempty"`
+	Status OVNBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OVNBackupList contains a list of OVNBackup.
+type OVNBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OVNBackup `json:"items"`
+}
+
+// OVNBackupScheduleSpec defines how often backups are taken and how many are retained
+type OVNBackupScheduleSpec struct {
+	// Interval between backups
+	Interval metav1.Duration `json:"interval"`
+	// Retention is the number of completed backups retained. Older backups and their artifacts are deleted
+	// +kubebuilder:default:=7
+	// +kubebuilder:validation:Minimum=1
+	Retention int32 `json:"retention,omitempty"`
+	// Suspend stops new backups from being created
+	Suspend bool         `json:"suspend,omitempty"`
+	Target  BackupTarget `json:"target"`
+}
+
+// OVNBackupScheduleStatus defines the observed state of OVNBackupSchedule.
+type OVNBackupScheduleStatus struct {
+	LastScheduleTime         *metav1.Time `json:"lastScheduleTime,omitempty"`
+	LastSuccessfulBackupTime *metav1.Time `json:"lastSuccessfulBackupTime,omitempty"`
+	LastSuccessfulBackupName string       `json:"lastSuccessfulBackupName,omitempty"`
+	// RetainedBackups are the completed backups currently retained, newest first
+	RetainedBackups []string `json:"retainedBackups,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Interval",type=string,JSONPath=`.spec.interval`
+// +kubebuilder:printcolumn:name="Last Success",type=date,JSONPath=`.status.lastSuccessfulBackupTime`
+
+// OVNBackupSchedule creates OVNBackups at a fixed interval
+type OVNBackupSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OVNBackupScheduleSpec   `json:"spec,omitempty"`
+	Status OVNBackupScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OVNBackupScheduleList contains a list of OVNBackupSchedule.
+type OVNBackupScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OVNBackupSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OVNBackup{}, &OVNBackupList{}, &OVNBackupSchedule{}, &OVNBackupScheduleList{})
+}
+
+const (
+	BackupPhasePending   = "Pending"
+	BackupPhaseRunning   = "Running"
+	BackupPhaseCompleted = "Completed"
+	BackupPhaseFailed    = "Failed"
+	BackupFinalizer      = "finalizer.kubeovn.io/backup"
+	// BackupScheduleLabel is added to backups created by a schedule
+	BackupScheduleLabel   = "kubeovn.io/backup-schedule"
+	ObjectTargetSecret    = "Secret"
+	ObjectTargetConfigMap = "ConfigMap"
+	NBDatabase            = "OVN_Northbound"
+	SBDatabase            = "OVN_Southbound"
+	NBBackupScript        = `ovsdb-client backup unix:/var/run/ovn/ovnnb_db.sock OVN_Northbound`
+	SBBackupScript        = `ovsdb-client backup unix:/var/run/ovn/ovnsb_db.sock OVN_Southbound`
+)
@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OVNRestoreSpec defines the backup the ovn databases are restored from
+type OVNRestoreSpec struct {
+	// BackupName is a completed OVNBackup in the operator namespace
+	BackupName string `json:"backupName"`
+}
+
+// OVNRestoreStatus defines the observed state of OVNRestore.
+type OVNRestoreStatus struct {
+	// Phase is one of Pending, Running, Completed or Failed
+	Phase          string       `json:"phase,omitempty"`
+	Message        string       `json:"message,omitempty"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Steps reports the progress of each step of the restore, in the order they are run
+	Steps []RestoreStep `json:"steps,omitempty"`
+	// BootstrapNode is the master node the raft cluster is recreated on. The remaining master nodes
+	// join the cluster when ovn-central is started
+	BootstrapNode string `json:"bootstrapNode,omitempty"`
+	// Replicas is the number of ovn-central replicas before ovn-central was scaled down
+	Replicas *int32 `json:"replicas,omitempty"`
+	// PausedConfiguration is set when the configuration was paused by the restore, and is resumed
+	// once ovn-central has been scaled back up
+	PausedConfiguration bool `json:"pausedConfiguration,omitempty"`
+}
+
+type RestoreStep struct {
+	Name string `json:"name"`
+	// Status is one of Pending, Running, Completed or Failed
+	Status         string       `json:"status"`
+	Message        string       `json:"message,omitempty"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Backup",type=string,JSONPath=`.spec.backupName`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Completed",type=date,JSONPath=`.status.completionTime`
+
+// OVNRestore replaces the ovn northbound and southbound databases on all master nodes with the snapshots
+// of an OVNBackup. ovn-central is stopped while the databases are replaced, so the restore is disruptive
+type OVNRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OVNRestoreSpec   `json:"spec,omitempty"`
+	Status OVNRestoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OVNRestoreList contains a list of OVNRestore.
+type OVNRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OVNRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OVNRestore{}, &OVNRestoreList{})
+}
+
+const (
+	RestorePhasePending   = "Pending"
+	RestorePhaseRunning   = "Running"
+	RestorePhaseCompleted = "Completed"
+	RestorePhaseFailed    = "Failed"
+	// RestoreStepValidate fetches the snapshots and verifies their checksums before ovn-central is stopped
+	RestoreStepValidate = "Validate"
+	// RestoreStepScaleDown pauses the configuration and scales ovn-central to zero replicas
+	RestoreStepScaleDown = "ScaleDown"
+	// RestoreStepReplaceDatabases moves the existing database files aside on all master nodes, and writes
+	// the standalone snapshots to the bootstrap node
+	RestoreStepReplaceDatabases = "ReplaceDatabases"
+	// RestoreStepConvertToCluster converts the standalone snapshots into single member raft databases
+	RestoreStepConvertToCluster = "ConvertToCluster"
+	// RestoreStepScaleUp restores the ovn-central replicas and resumes the configuration
+	RestoreStepScaleUp = "ScaleUp"
+	// RestoreStepVerifyLeaders waits for both databases to elect a leader with all master nodes as members
+	RestoreStepVerifyLeaders = "VerifyLeaders"
+	OVNCentralDeploymentName = "ovn-central"
+	NBRaftPort               = 6643
+	SBRaftPort               = 6644
+)
@@ -21,10 +21,76 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AirgapSpec) DeepCopyInto(out *AirgapSpec) {
+	*out = *in
+	if in.PathMappings != nil {
+		in, out := &in.PathMappings, &out.PathMappings
+		*out = make([]ImagePathMapping, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AirgapSpec.
+func (in *AirgapSpec) DeepCopy() *AirgapSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AirgapSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupArtifact) DeepCopyInto(out *BackupArtifact) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupArtifact.
+func (in *BackupArtifact) DeepCopy() *BackupArtifact {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupArtifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupTarget) DeepCopyInto(out *BackupTarget) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(PVCBackupTarget)
+		**out = **in
+	}
+	if in.Object != nil {
+		in, out := &in.Object, &out.Object
+		*out = new(ObjectBackupTarget)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3BackupTarget)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupTarget.
+func (in *BackupTarget) DeepCopy() *BackupTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CNIConfSpec) DeepCopyInto(out *CNIConfSpec) {
 	*out = *in
@@ -68,6 +134,219 @@ func (in *CPUMemSpec) DeepCopy() *CPUMemSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerReference) DeepCopyInto(out *CertManagerIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerReference.
+func (in *CertManagerIssuerReference) DeepCopy() *CertManagerIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerSpec) DeepCopyInto(out *CertManagerSpec) {
+	*out = *in
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(CertManagerIssuerReference)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerSpec.
+func (in *CertManagerSpec) DeepCopy() *CertManagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChassisAuditStatus) DeepCopyInto(out *ChassisAuditStatus) {
+	*out = *in
+	if in.StaleChassis != nil {
+		in, out := &in.StaleChassis, &out.StaleChassis
+		*out = make([]ChassisReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodesWithoutChassis != nil {
+		in, out := &in.NodesWithoutChassis, &out.NodesWithoutChassis
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DuplicateChassis != nil {
+		in, out := &in.DuplicateChassis, &out.DuplicateChassis
+		*out = make([]ChassisReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.LastAuditTime.DeepCopyInto(&out.LastAuditTime)
+	if in.CollectedChassis != nil {
+		in, out := &in.CollectedChassis, &out.CollectedChassis
+		*out = make([]ChassisReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.LastCollectionTime.DeepCopyInto(&out.LastCollectionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChassisAuditStatus.
+func (in *ChassisAuditStatus) DeepCopy() *ChassisAuditStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChassisAuditStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChassisGCSpec) DeepCopyInto(out *ChassisGCSpec) {
+	*out = *in
+	out.Interval = in.Interval
+	out.GracePeriod = in.GracePeriod
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChassisGCSpec.
+func (in *ChassisGCSpec) DeepCopy() *ChassisGCSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChassisGCSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChassisReference) DeepCopyInto(out *ChassisReference) {
+	*out = *in
+	in.DetectedTime.DeepCopyInto(&out.DetectedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChassisReference.
+func (in *ChassisReference) DeepCopy() *ChassisReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ChassisReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompactionSpec) DeepCopyInto(out *CompactionSpec) {
+	*out = *in
+	if in.FileSizeThreshold != nil {
+		in, out := &in.FileSizeThreshold, &out.FileSizeThreshold
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	out.MinInterval = in.MinInterval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompactionSpec.
+func (in *CompactionSpec) DeepCopy() *CompactionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CompactionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentImage) DeepCopyInto(out *ComponentImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentImage.
+func (in *ComponentImage) DeepCopy() *ComponentImage {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentImages) DeepCopyInto(out *ComponentImages) {
+	*out = *in
+	out.OVNCentral = in.OVNCentral
+	out.OVSOVN = in.OVSOVN
+	out.KubeOVNController = in.KubeOVNController
+	out.KubeOVNCNI = in.KubeOVNCNI
+	out.KubeOVNPinger = in.KubeOVNPinger
+	out.KubeOVNMonitor = in.KubeOVNMonitor
+	out.NATGateway = in.NATGateway
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentImages.
+func (in *ComponentImages) DeepCopy() *ComponentImages {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentPlacement) DeepCopyInto(out *ComponentPlacement) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentPlacement.
+func (in *ComponentPlacement) DeepCopy() *ComponentPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 	*out = *in
@@ -286,6 +565,25 @@ func (in *ConfigurationSpec) DeepCopyInto(out *ConfigurationSpec) {
 	in.KubeOVNCNI.DeepCopyInto(&out.KubeOVNCNI)
 	in.KubeOVNPinger.DeepCopyInto(&out.KubeOVNPinger)
 	in.KubeOVNMonitor.DeepCopyInto(&out.KubeOVNMonitor)
+	in.Placement.DeepCopyInto(&out.Placement)
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]ObjectOverride, len(*in))
+		copy(*out, *in)
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(int64)
+		**out = **in
+	}
+	in.Airgap.DeepCopyInto(&out.Airgap)
+	in.CertManager.DeepCopyInto(&out.CertManager)
+	in.Maintenance.DeepCopyInto(&out.Maintenance)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationSpec.
@@ -318,6 +616,46 @@ func (in *ConfigurationStatus) DeepCopyInto(out *ConfigurationStatus) {
 		*out = make([]ObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.OrphanedObjects != nil {
+		in, out := &in.OrphanedObjects, &out.OrphanedObjects
+		*out = make([]ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = make([]ObjectPlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.OVNDatabases.DeepCopyInto(&out.OVNDatabases)
+	in.Chassis.DeepCopyInto(&out.Chassis)
+	if in.RaftRecovery != nil {
+		in, out := &in.RaftRecovery, &out.RaftRecovery
+		*out = make([]RaftMemberRecoveryStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MatchingNodes != nil {
+		in, out := &in.MatchingNodes, &out.MatchingNodes
+		*out = make([]MatchingNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.AddressChanges != nil {
+		in, out := &in.AddressChanges, &out.AddressChanges
+		*out = make([]NodeAddressChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationStatus.
@@ -387,6 +725,7 @@ func (in *ImageDetails) DeepCopyInto(out *ImageDetails) {
 	*out = *in
 	out.KubeOVNImage = in.KubeOVNImage
 	out.NATGatewayImage = in.NATGatewayImage
+	out.Components = in.Components
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageDetails.
@@ -399,6 +738,21 @@ func (in *ImageDetails) DeepCopy() *ImageDetails {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePathMapping) DeepCopyInto(out *ImagePathMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePathMapping.
+func (in *ImagePathMapping) DeepCopy() *ImagePathMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePathMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeOVNImageSpec) DeepCopyInto(out *KubeOVNImageSpec) {
 	*out = *in
@@ -444,6 +798,39 @@ func (in *LogConfigSpec) DeepCopy() *LogConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceSpec) DeepCopyInto(out *MaintenanceSpec) {
+	*out = *in
+	out.ChassisGC = in.ChassisGC
+	in.Compaction.DeepCopyInto(&out.Compaction)
+	out.AutoRecoverRaftMembers = in.AutoRecoverRaftMembers
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceSpec.
+func (in *MaintenanceSpec) DeepCopy() *MaintenanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatchingNode) DeepCopyInto(out *MatchingNode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchingNode.
+func (in *MatchingNode) DeepCopy() *MatchingNode {
+	if in == nil {
+		return nil
+	}
+	out := new(MatchingNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NATGatewayImageSpec) DeepCopyInto(out *NATGatewayImageSpec) {
 	*out = *in
@@ -521,51 +908,609 @@ func (in *NetworkingSpec) DeepCopy() *NetworkingSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
+func (in *NodeAddressChange) DeepCopyInto(out *NodeAddressChange) {
 	*out = *in
-	out.GVK = in.GVK
+	in.DetectedTime.DeepCopyInto(&out.DetectedTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectReference.
-func (in *ObjectReference) DeepCopy() *ObjectReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAddressChange.
+func (in *NodeAddressChange) DeepCopy() *NodeAddressChange {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectReference)
+	out := new(NodeAddressChange)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PerformanceSpec) DeepCopyInto(out *PerformanceSpec) {
+func (in *OVNBackup) DeepCopyInto(out *OVNBackup) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerformanceSpec.
-func (in *PerformanceSpec) DeepCopy() *PerformanceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNBackup.
+func (in *OVNBackup) DeepCopy() *OVNBackup {
 	if in == nil {
 		return nil
 	}
-	out := new(PerformanceSpec)
+	out := new(OVNBackup)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RegistrySpec) DeepCopyInto(out *RegistrySpec) {
-	*out = *in
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OVNBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
+	return nil
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistrySpec.
-func (in *RegistrySpec) DeepCopy() *RegistrySpec {
-	if in == nil {
-		return nil
-	}
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNBackupList) DeepCopyInto(out *OVNBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OVNBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNBackupList.
+func (in *OVNBackupList) DeepCopy() *OVNBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OVNBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNBackupSchedule) DeepCopyInto(out *OVNBackupSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNBackupSchedule.
+func (in *OVNBackupSchedule) DeepCopy() *OVNBackupSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNBackupSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OVNBackupSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNBackupScheduleList) DeepCopyInto(out *OVNBackupScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OVNBackupSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNBackupScheduleList.
+func (in *OVNBackupScheduleList) DeepCopy() *OVNBackupScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNBackupScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OVNBackupScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNBackupScheduleSpec) DeepCopyInto(out *OVNBackupScheduleSpec) {
+	*out = *in
+	out.Interval = in.Interval
+	in.Target.DeepCopyInto(&out.Target)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNBackupScheduleSpec.
+func (in *OVNBackupScheduleSpec) DeepCopy() *OVNBackupScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNBackupScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNBackupScheduleStatus) DeepCopyInto(out *OVNBackupScheduleStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulBackupTime != nil {
+		in, out := &in.LastSuccessfulBackupTime, &out.LastSuccessfulBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RetainedBackups != nil {
+		in, out := &in.RetainedBackups, &out.RetainedBackups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNBackupScheduleStatus.
+func (in *OVNBackupScheduleStatus) DeepCopy() *OVNBackupScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNBackupScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNBackupSpec) DeepCopyInto(out *OVNBackupSpec) {
+	*out = *in
+	in.Target.DeepCopyInto(&out.Target)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNBackupSpec.
+func (in *OVNBackupSpec) DeepCopy() *OVNBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNBackupStatus) DeepCopyInto(out *OVNBackupStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make([]BackupArtifact, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNBackupStatus.
+func (in *OVNBackupStatus) DeepCopy() *OVNBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDatabaseMemberStatus) DeepCopyInto(out *OVNDatabaseMemberStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDatabaseMemberStatus.
+func (in *OVNDatabaseMemberStatus) DeepCopy() *OVNDatabaseMemberStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDatabaseMemberStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDatabaseStatus) DeepCopyInto(out *OVNDatabaseStatus) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]OVNDatabaseMemberStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.MissingAddresses != nil {
+		in, out := &in.MissingAddresses, &out.MissingAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnexpectedAddresses != nil {
+		in, out := &in.UnexpectedAddresses, &out.UnexpectedAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LastCompactionTime != nil {
+		in, out := &in.LastCompactionTime, &out.LastCompactionTime
+		*out = (*in).DeepCopy()
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDatabaseStatus.
+func (in *OVNDatabaseStatus) DeepCopy() *OVNDatabaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDatabaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNDatabasesStatus) DeepCopyInto(out *OVNDatabasesStatus) {
+	*out = *in
+	if in.Northbound != nil {
+		in, out := &in.Northbound, &out.Northbound
+		*out = new(OVNDatabaseStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Southbound != nil {
+		in, out := &in.Southbound, &out.Southbound
+		*out = new(OVNDatabaseStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNDatabasesStatus.
+func (in *OVNDatabasesStatus) DeepCopy() *OVNDatabasesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNDatabasesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNRestore) DeepCopyInto(out *OVNRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNRestore.
+func (in *OVNRestore) DeepCopy() *OVNRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OVNRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNRestoreList) DeepCopyInto(out *OVNRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OVNRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNRestoreList.
+func (in *OVNRestoreList) DeepCopy() *OVNRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OVNRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNRestoreSpec) DeepCopyInto(out *OVNRestoreSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNRestoreSpec.
+func (in *OVNRestoreSpec) DeepCopy() *OVNRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OVNRestoreStatus) DeepCopyInto(out *OVNRestoreStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]RestoreStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNRestoreStatus.
+func (in *OVNRestoreStatus) DeepCopy() *OVNRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OVNRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectBackupTarget) DeepCopyInto(out *ObjectBackupTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectBackupTarget.
+func (in *ObjectBackupTarget) DeepCopy() *ObjectBackupTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectBackupTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectOverride) DeepCopyInto(out *ObjectOverride) {
+	*out = *in
+	out.Target = in.Target
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectOverride.
+func (in *ObjectOverride) DeepCopy() *ObjectOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectPlan) DeepCopyInto(out *ObjectPlan) {
+	*out = *in
+	out.GVK = in.GVK
+	if in.ChangedFields != nil {
+		in, out := &in.ChangedFields, &out.ChangedFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectPlan.
+func (in *ObjectPlan) DeepCopy() *ObjectPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
+	*out = *in
+	out.GVK = in.GVK
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectReference.
+func (in *ObjectReference) DeepCopy() *ObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OverrideTarget) DeepCopyInto(out *OverrideTarget) {
+	*out = *in
+	out.GroupVersionKind = in.GroupVersionKind
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverrideTarget.
+func (in *OverrideTarget) DeepCopy() *OverrideTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(OverrideTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCBackupTarget) DeepCopyInto(out *PVCBackupTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCBackupTarget.
+func (in *PVCBackupTarget) DeepCopy() *PVCBackupTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCBackupTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PerformanceSpec) DeepCopyInto(out *PerformanceSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerformanceSpec.
+func (in *PerformanceSpec) DeepCopy() *PerformanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PerformanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementSpec) DeepCopyInto(out *PlacementSpec) {
+	*out = *in
+	in.OVNCentral.DeepCopyInto(&out.OVNCentral)
+	in.KubeOVNController.DeepCopyInto(&out.KubeOVNController)
+	in.OVSOVN.DeepCopyInto(&out.OVSOVN)
+	in.KubeOVNCNI.DeepCopyInto(&out.KubeOVNCNI)
+	in.KubeOVNPinger.DeepCopyInto(&out.KubeOVNPinger)
+	in.KubeOVNMonitor.DeepCopyInto(&out.KubeOVNMonitor)
+	in.OVNICController.DeepCopyInto(&out.OVNICController)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementSpec.
+func (in *PlacementSpec) DeepCopy() *PlacementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RaftMemberRecoverySpec) DeepCopyInto(out *RaftMemberRecoverySpec) {
+	*out = *in
+	out.GracePeriod = in.GracePeriod
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RaftMemberRecoverySpec.
+func (in *RaftMemberRecoverySpec) DeepCopy() *RaftMemberRecoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RaftMemberRecoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RaftMemberRecoveryStatus) DeepCopyInto(out *RaftMemberRecoveryStatus) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.DetectedTime.DeepCopyInto(&out.DetectedTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RaftMemberRecoveryStatus.
+func (in *RaftMemberRecoveryStatus) DeepCopy() *RaftMemberRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RaftMemberRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistrySpec) DeepCopyInto(out *RegistrySpec) {
+	*out = *in
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistrySpec.
+func (in *RegistrySpec) DeepCopy() *RegistrySpec {
+	if in == nil {
+		return nil
+	}
 	out := new(RegistrySpec)
 	in.DeepCopyInto(out)
 	return out
@@ -588,6 +1533,44 @@ func (in *ResourceSpec) DeepCopy() *ResourceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreStep) DeepCopyInto(out *RestoreStep) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreStep.
+func (in *RestoreStep) DeepCopy() *RestoreStep {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3BackupTarget) DeepCopyInto(out *S3BackupTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BackupTarget.
+func (in *S3BackupTarget) DeepCopy() *S3BackupTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(S3BackupTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *U2OFeatures) DeepCopyInto(out *U2OFeatures) {
 	*out = *in
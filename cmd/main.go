@@ -21,6 +21,7 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"time"
 
 	"go.uber.org/zap/zapcore"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -36,6 +37,7 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -45,7 +47,9 @@ import (
 	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
 	"github.com/harvester/kubeovn-operator/internal/bootstrap"
 	"github.com/harvester/kubeovn-operator/internal/controller"
+	"github.com/harvester/kubeovn-operator/internal/executor"
 	webhookkubeovnv1 "github.com/harvester/kubeovn-operator/internal/webhook/v1"
+	"github.com/harvester/kubeovn-operator/internal/webhookcert"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -57,6 +61,8 @@ var (
 
 const (
 	DefaultVersion = "v1.16.2"
+	// webhookCertWaitTimeout allows for the kubelet to sync the secret volume after the certificate is issued
+	webhookCertWaitTimeout = 3 * time.Minute
 )
 
 func init() {
@@ -71,7 +77,7 @@ func init() {
 func main() {
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
-	var webhookCertPath, webhookCertName, webhookCertKey string
+	var webhookCertPath, webhookCertName, webhookCertKey, webhookServiceName string
 	var enableLeaderElection bool
 	var probeAddr string
 	var secureMetrics bool
@@ -91,6 +97,8 @@ func main() {
 	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
 	flag.StringVar(&webhookCertName, "webhook-cert-name", "tls.crt", "The name of the webhook certificate file.")
 	flag.StringVar(&webhookCertKey, "webhook-cert-key", "tls.key", "The name of the webhook key file.")
+	flag.StringVar(&webhookServiceName, "webhook-service-name", "kubeovn-operator-webhook-service",
+		"The name of the service for the webhook server, used when the operator issues the webhook certificate.")
 	flag.StringVar(&metricsCertPath, "metrics-cert-path", "",
 		"The directory that contains the metrics server certificate.")
 	flag.StringVar(&metricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file.")
@@ -98,7 +106,7 @@ func main() {
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
 	flag.StringVar(&namespace, "namespace", "", "The namespace passed via downward API to identify where to deploy and watch generated resources")
-	flag.StringVar(&version, "version", DefaultVersion, "Version passed to kubeovn image tag in generated resources")
+	flag.StringVar(&version, "version", DefaultVersion, "Default kubeovn version used for new installs when spec.version is not set")
 	flag.BoolVar(&debug, "debug", false, "Enable debug logging")
 	flag.IntVar(&healthCheckInterval, "healthCheckInterval", 300, "Healthcheck interval for check OVN DB health")
 
@@ -138,11 +146,38 @@ func main() {
 	// Initial webhook TLS options
 	webhookTLSOpts := tlsOpts
 
+	ctx := ctrl.SetupSignalHandler()
+	restConfig := ctrl.GetConfigOrDie()
+
+	// the webhook certificate is issued by the operator when no valid certificate has been provided. A client
+	// not backed by the cache is used, as the certificate is needed before the manager is started
+	directClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client")
+		os.Exit(1)
+	}
+	webhookCertManager := &webhookcert.CertificateManager{
+		Client:      directClient,
+		Namespace:   namespace,
+		ServiceName: webhookServiceName,
+		Log:         setupLog.WithName("webhook-certificate"),
+	}
+
 	if len(webhookCertPath) > 0 {
 		setupLog.Info("Initializing webhook certificate watcher using provided certificates",
 			"webhook-cert-path", webhookCertPath, "webhook-cert-name", webhookCertName, "webhook-cert-key", webhookCertKey)
 
-		var err error
+		if err := webhookCertManager.Ensure(ctx); err != nil {
+			setupLog.Error(err, "Failed to issue webhook certificate")
+			os.Exit(1)
+		}
+		err = webhookcert.WaitForCertificateFiles(ctx, webhookCertWaitTimeout,
+			filepath.Join(webhookCertPath, webhookCertName), filepath.Join(webhookCertPath, webhookCertKey))
+		if err != nil {
+			setupLog.Error(err, "Timed out waiting for webhook certificate to be mounted")
+			os.Exit(1)
+		}
+
 		webhookCertWatcher, err = certwatcher.New(
 			filepath.Join(webhookCertPath, webhookCertName),
 			filepath.Join(webhookCertPath, webhookCertKey),
@@ -206,7 +241,7 @@ func main() {
 		})
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
 		HealthProbeBindAddress: probeAddr,
@@ -235,8 +270,6 @@ func main() {
 		os.Exit(1)
 	}
 
-	ctx := ctrl.SetupSignalHandler()
-
 	if err = (&controller.ConfigurationReconciler{
 		Client:        mgr.GetClient(),
 		Scheme:        mgr.GetScheme(),
@@ -244,6 +277,7 @@ func main() {
 		EventRecorder: mgr.GetEventRecorderFor("configuration-controller"),
 		Log:           logf.FromContext(ctx).WithName("configuration-controller"),
 		RestConfig:    mgr.GetConfig(),
+		CommandRunner: executor.NewRemoteCommandRunner(mgr.GetConfig()),
 		Version:       version,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Configuration")
@@ -256,6 +290,7 @@ func main() {
 		Namespace:     namespace,
 		EventRecorder: mgr.GetEventRecorderFor("node-controller"),
 		RestConfig:    mgr.GetConfig(),
+		CommandRunner: executor.NewRemoteCommandRunner(mgr.GetConfig()),
 		Log:           logf.FromContext(ctx).WithName("node-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Node")
@@ -267,13 +302,51 @@ func main() {
 		Namespace:           namespace,
 		EventRecorder:       mgr.GetEventRecorderFor("healthcheck-controller"),
 		RestConfig:          mgr.GetConfig(),
+		CommandRunner:       executor.NewRemoteCommandRunner(mgr.GetConfig()),
 		Log:                 logf.FromContext(ctx).WithName("healthcheck-controller"),
 		HealthCheckInterval: healthCheckInterval,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Node")
 	}
 
-	webhookMgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	if err = (&controller.OVNBackupReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		Namespace:     namespace,
+		EventRecorder: mgr.GetEventRecorderFor("backup-controller"),
+		RestConfig:    mgr.GetConfig(),
+		CommandRunner: executor.NewRemoteCommandRunner(mgr.GetConfig()),
+		Log:           logf.FromContext(ctx).WithName("backup-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OVNBackup")
+		os.Exit(1)
+	}
+
+	if err = (&controller.OVNBackupScheduleReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		Namespace:     namespace,
+		EventRecorder: mgr.GetEventRecorderFor("backup-schedule-controller"),
+		Log:           logf.FromContext(ctx).WithName("backup-schedule-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OVNBackupSchedule")
+		os.Exit(1)
+	}
+
+	if err = (&controller.OVNRestoreReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		Namespace:     namespace,
+		EventRecorder: mgr.GetEventRecorderFor("restore-controller"),
+		RestConfig:    mgr.GetConfig(),
+		CommandRunner: executor.NewRemoteCommandRunner(mgr.GetConfig()),
+		Log:           logf.FromContext(ctx).WithName("restore-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OVNRestore")
+		os.Exit(1)
+	}
+
+	webhookMgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
 			BindAddress: "",
@@ -288,7 +361,7 @@ func main() {
 	}
 
 	// nolint:goconst
-	if err = webhookkubeovnv1.SetupConfigurationWebhookWithManager(webhookMgr); err != nil {
+	if err = webhookkubeovnv1.SetupConfigurationWebhookWithManager(webhookMgr, namespace); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Configuration")
 		os.Exit(1)
 	}
@@ -310,6 +383,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := mgr.Add(webhookCertManager); err != nil {
+		setupLog.Error(err, "unable to add webhook certificate manager")
+		os.Exit(1)
+	}
+
 	if metricsCertWatcher != nil {
 		setupLog.Info("Adding metrics certificate watcher to manager")
 		if err := mgr.Add(metricsCertWatcher); err != nil {
@@ -0,0 +1,152 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+// Store persists backup artifacts to a backup target
+type Store interface {
+	// Put stores data read until EOF under name and returns the location it was stored at. data is streamed to
+	// the target, so artifacts larger than the memory available to the operator can be stored
+	Put(ctx context.Context, name string, data io.Reader) (string, error)
+	// Get returns the artifact stored at location
+	Get(ctx context.Context, location string) ([]byte, error)
+	// Delete removes the artifact stored at location
+	Delete(ctx context.Context, location string) error
+	// Close releases any resources created while accessing the target
+	Close(ctx context.Context) error
+}
+
+// StoreOptions are the dependencies needed to access backup targets
+type StoreOptions struct {
+	Client     client.Client
+	RestConfig *rest.Config
+	Namespace  string
+	// Image and ImagePullSecrets are used by the pod mounting persistent volume claims
+	Image            string
+	ImagePullSecrets []string
+}
+
+// NewStore returns the Store for a backup target
+func NewStore(ctx context.Context, target ovnoperatorv1.BackupTarget, opts StoreOptions) (Store, error) {
+	switch {
+	case target.PersistentVolumeClaim != nil:
+		return newPVCStore(*target.PersistentVolumeClaim, opts), nil
+	case target.Object != nil:
+		return newObjectStore(*target.Object, opts), nil
+	case target.S3 != nil:
+		return newS3Store(ctx, *target.S3, opts)
+	default:
+		return nil, errors.New("no backup target specified")
+	}
+}
+
+// ValidateTarget checks exactly one backup target is set
+func ValidateTarget(target ovnoperatorv1.BackupTarget) error {
+	count := 0
+	for _, set := range []bool{target.PersistentVolumeClaim != nil, target.Object != nil, target.S3 != nil} {
+		if set {
+			count++
+		}
+	}
+	if count != 1 {
+		return fmt.Errorf("expected exactly one of persistentVolumeClaim, object or s3 to be set, found %d", count)
+	}
+	return nil
+}
+
+// CompressedSnapshot is a reader streaming a gzip compressed database snapshot. The size and sha256 checksum of
+// the compressed data are recorded as it is read
+type CompressedSnapshot struct {
+	reader *io.PipeReader
+	hash   hash.Hash
+	size   int64
+	done   chan struct{}
+	err    error
+}
+
+// Compress returns a CompressedSnapshot of the data written by write, which is run in a separate goroutine and
+// blocks until the compressed data has been read
+func Compress(write func(w io.Writer) error) *CompressedSnapshot {
+	reader, writer := io.Pipe()
+	c := &CompressedSnapshot{reader: reader, hash: sha256.New(), done: make(chan struct{})}
+	go func() {
+		defer close(c.done)
+		gz := gzip.NewWriter(writer)
+		err := write(gz)
+		if closeErr := gz.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("error compressing snapshot: %w", closeErr)
+		}
+		c.err = err
+		// readers observe the error of write instead of EOF, so a failed snapshot is never stored as complete
+		writer.CloseWithError(err)
+	}()
+	return c
+}
+
+func (c *CompressedSnapshot) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.hash.Write(p[:n])
+	c.size += int64(n)
+	return n, err
+}
+
+// Close stops write if the snapshot has not been read to the end, and returns the error returned by write
+func (c *CompressedSnapshot) Close() error {
+	c.reader.Close()
+	<-c.done
+	return c.err
+}
+
+// Size returns the number of compressed bytes read
+func (c *CompressedSnapshot) Size() int64 {
+	return c.size
+}
+
+// Checksum returns the hex encoded sha256 checksum of the compressed bytes read
+func (c *CompressedSnapshot) Checksum() string {
+	return hex.EncodeToString(c.hash.Sum(nil))
+}
+
+// Decompress verifies the sha256 checksum of a compressed snapshot and returns the decompressed snapshot
+func Decompress(data []byte, checksum string) ([]byte, error) {
+	if actual := Checksum(data); actual != checksum {
+		return nil, fmt.Errorf("checksum mismatch, expected %s but found %s", checksum, actual)
+	}
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing snapshot: %w", err)
+	}
+	defer r.Close()
+	snapshot, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// Checksum returns the hex encoded sha256 checksum of data
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ArtifactName returns the name an artifact of a database is stored under, for example
+// nightly-northbound.db.gz for the OVN_Northbound database of the nightly backup
+func ArtifactName(backupName string, database string) string {
+	return fmt.Sprintf("%s-%s.db.gz", backupName, strings.ToLower(strings.TrimPrefix(database, "OVN_")))
+}
@@ -0,0 +1,215 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+const testNamespace = "kube-system"
+
+func Test_Compress(t *testing.T) {
+	assert := require.New(t)
+	snapshot := []byte(`{"name":"OVN_Northbound","version":"7.3.0"}`)
+	compressedSnapshot := Compress(func(w io.Writer) error {
+		_, err := w.Write(snapshot)
+		return err
+	})
+	compressed, err := io.ReadAll(compressedSnapshot)
+	assert.NoError(err)
+	assert.NoError(compressedSnapshot.Close())
+	assert.Equal(Checksum(compressed), compressedSnapshot.Checksum())
+	assert.Equal(int64(len(compressed)), compressedSnapshot.Size())
+
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	assert.NoError(err)
+	decompressed, err := io.ReadAll(r)
+	assert.NoError(err)
+	assert.Equal(snapshot, decompressed)
+
+	decompressed, err = Decompress(compressed, compressedSnapshot.Checksum())
+	assert.NoError(err)
+	assert.Equal(snapshot, decompressed)
+	_, err = Decompress(compressed, Checksum(snapshot))
+	assert.ErrorContains(err, "checksum mismatch")
+
+	// readers observe a failed snapshot instead of the end of the data
+	compressedSnapshot = Compress(func(w io.Writer) error {
+		_, _ = w.Write(snapshot)
+		return errors.New("connection reset")
+	})
+	_, err = io.ReadAll(compressedSnapshot)
+	assert.ErrorContains(err, "connection reset")
+	assert.ErrorContains(compressedSnapshot.Close(), "connection reset")
+
+	// closing a snapshot before it has been read stops the writer
+	compressedSnapshot = Compress(func(w io.Writer) error {
+		_, err := w.Write(snapshot)
+		return err
+	})
+	assert.Error(compressedSnapshot.Close())
+}
+
+func Test_ArtifactName(t *testing.T) {
+	assert := require.New(t)
+	assert.Equal("nightly-northbound.db.gz", ArtifactName("nightly", ovnoperatorv1.NBDatabase))
+	assert.Equal("nightly-southbound.db.gz", ArtifactName("nightly", ovnoperatorv1.SBDatabase))
+}
+
+func Test_ValidateTarget(t *testing.T) {
+	assert := require.New(t)
+	assert.Error(ValidateTarget(ovnoperatorv1.BackupTarget{}))
+	assert.NoError(ValidateTarget(ovnoperatorv1.BackupTarget{Object: &ovnoperatorv1.ObjectBackupTarget{}}))
+	assert.Error(ValidateTarget(ovnoperatorv1.BackupTarget{
+		Object: &ovnoperatorv1.ObjectBackupTarget{},
+		S3:     &ovnoperatorv1.S3BackupTarget{},
+	}))
+}
+
+func Test_ObjectStore(t *testing.T) {
+	assert := require.New(t)
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	store, err := NewStore(context.TODO(), ovnoperatorv1.BackupTarget{Object: &ovnoperatorv1.ObjectBackupTarget{Kind: ovnoperatorv1.ObjectTargetConfigMap}},
+		StoreOptions{Client: k8sClient, Namespace: testNamespace})
+	assert.NoError(err)
+
+	data := make([]byte, chunkSize*2+10)
+	_, err = rand.Read(data)
+	assert.NoError(err)
+	location, err := store.Put(context.TODO(), "nightly-southbound.db.gz", bytes.NewReader(data))
+	assert.NoError(err)
+
+	var stored []byte
+	for i := 0; i < 3; i++ {
+		cm := &corev1.ConfigMap{}
+		assert.NoError(k8sClient.Get(context.TODO(), types.NamespacedName{Name: ChunkName(location, i), Namespace: testNamespace}, cm))
+		if i == 0 {
+			assert.Equal("3", cm.Annotations[ChunksAnnotation])
+		}
+		stored = append(stored, cm.BinaryData[chunkDataKey]...)
+	}
+	assert.Equal(data, stored)
+
+	fetched, err := store.Get(context.TODO(), location)
+	assert.NoError(err)
+	assert.Equal(data, fetched)
+
+	assert.NoError(store.Delete(context.TODO(), location))
+	cmList := &corev1.ConfigMapList{}
+	assert.NoError(k8sClient.List(context.TODO(), cmList))
+	assert.Empty(cmList.Items)
+
+	// chunks stored before the data could not be read are removed
+	_, err = store.Put(context.TODO(), "nightly-northbound.db.gz", io.MultiReader(bytes.NewReader(data), iotest.ErrReader(errors.New("connection reset"))))
+	assert.ErrorContains(err, "connection reset")
+	assert.NoError(k8sClient.List(context.TODO(), cmList))
+	assert.Empty(cmList.Items)
+}
+
+func Test_S3Store(t *testing.T) {
+	assert := require.New(t)
+	objects := make(map[string][]byte)
+	uploads := make(map[string]map[int][]byte)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if Checksum(body) != r.Header.Get("x-amz-content-sha256") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		query := r.URL.Query()
+		uploadID := query.Get("uploadId")
+		switch {
+		case r.Method == http.MethodPost && query.Has("uploads"):
+			uploadID = fmt.Sprintf("upload-%d", len(uploads))
+			uploads[uploadID] = make(map[int][]byte)
+			_, _ = fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", uploadID)
+		case r.Method == http.MethodPut && uploadID != "":
+			partNumber, _ := strconv.Atoi(query.Get("partNumber"))
+			uploads[uploadID][partNumber] = body
+			w.Header().Set("ETag", fmt.Sprintf("\"etag-%d\"", partNumber))
+		case r.Method == http.MethodPost && uploadID != "":
+			complete := s3CompleteMultipartUpload{}
+			if err := xml.Unmarshal(body, &complete); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			var object []byte
+			for _, part := range complete.Parts {
+				object = append(object, uploads[uploadID][part.PartNumber]...)
+			}
+			objects[r.URL.Path] = object
+			delete(uploads, uploadID)
+		case r.Method == http.MethodDelete && uploadID != "":
+			delete(uploads, uploadID)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPut:
+			objects[r.URL.Path] = body
+		case r.Method == http.MethodGet:
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(body)
+		case r.Method == http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	store, err := newS3StoreWithCredentials(ovnoperatorv1.S3BackupTarget{Endpoint: server.URL, Bucket: "ovn", Prefix: "cluster1"}, "minio", "minio123")
+	assert.NoError(err)
+	location, err := store.Put(context.TODO(), "nightly-northbound.db.gz", strings.NewReader("snapshot"))
+	assert.NoError(err)
+	assert.Equal("cluster1/nightly-northbound.db.gz", location)
+	assert.Equal([]byte("snapshot"), objects["/ovn/cluster1/nightly-northbound.db.gz"])
+	fetched, err := store.Get(context.TODO(), location)
+	assert.NoError(err)
+	assert.Equal([]byte("snapshot"), fetched)
+
+	assert.NoError(store.Delete(context.TODO(), location))
+	assert.Empty(objects)
+
+	// artifacts larger than a part are streamed using a multipart upload
+	store.partSize = 4
+	location, err = store.Put(context.TODO(), "nightly-southbound.db.gz", strings.NewReader("large snapshot"))
+	assert.NoError(err)
+	assert.Equal([]byte("large snapshot"), objects["/ovn/cluster1/nightly-southbound.db.gz"])
+	assert.Empty(uploads)
+	assert.NoError(store.Delete(context.TODO(), location))
+
+	// uploads are aborted if the data cannot be read
+	_, err = store.Put(context.TODO(), "nightly-southbound.db.gz", io.MultiReader(strings.NewReader("large snapshot"), iotest.ErrReader(errors.New("connection reset"))))
+	assert.ErrorContains(err, "connection reset")
+	assert.Empty(uploads)
+	assert.Empty(objects)
+
+	_, err = newS3StoreWithCredentials(ovnoperatorv1.S3BackupTarget{Endpoint: server.URL, Bucket: "ovn", CredentialsSecret: "s3"}, "", "")
+	assert.ErrorContains(err, "needs to contain accessKeyID")
+}
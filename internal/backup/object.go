@@ -0,0 +1,148 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+const (
+	// chunkSize keeps each object well below the 1MiB limit of etcd values
+	chunkSize = 768 * 1024
+	// chunkDataKey is the key chunk data is stored under in secrets and configmaps
+	chunkDataKey = "data"
+	// ChunksAnnotation records the total number of chunks on the first chunk object
+	ChunksAnnotation = "kubeovn.io/backup-chunks"
+)
+
+// objectStore splits artifacts into chunks stored in secrets or configmaps named <artifact>-<index>
+type objectStore struct {
+	client    client.Client
+	namespace string
+	kind      string
+}
+
+func newObjectStore(target ovnoperatorv1.ObjectBackupTarget, opts StoreOptions) *objectStore {
+	kind := target.Kind
+	if kind == "" {
+		kind = ovnoperatorv1.ObjectTargetSecret
+	}
+	return &objectStore{client: opts.Client, namespace: opts.Namespace, kind: kind}
+}
+
+// Put stores chunks as they are read. The number of chunks is only known once data has been read to the end, so
+// it is recorded on the first chunk last, and artifacts which have not been stored completely cannot be read
+func (o *objectStore) Put(ctx context.Context, name string, data io.Reader) (string, error) {
+	var first []byte
+	count := 0
+	for {
+		chunk := make([]byte, chunkSize)
+		n, err := io.ReadFull(data, chunk)
+		if err == io.EOF && count > 0 {
+			break
+		}
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			_ = o.Delete(ctx, name)
+			return "", fmt.Errorf("error reading artifact %s: %v", name, err)
+		}
+		if count == 0 {
+			first = chunk[:n]
+		}
+		if putErr := o.putChunk(ctx, ChunkName(name, count), chunk[:n], nil); putErr != nil {
+			_ = o.Delete(ctx, name)
+			return "", putErr
+		}
+		count++
+		if err != nil {
+			break
+		}
+	}
+	if err := o.putChunk(ctx, ChunkName(name, 0), first, map[string]string{ChunksAnnotation: strconv.Itoa(count)}); err != nil {
+		_ = o.Delete(ctx, name)
+		return "", err
+	}
+	return name, nil
+}
+
+// putChunk creates or replaces a chunk object, chunks left behind by an interrupted backup are overwritten
+func (o *objectStore) putChunk(ctx context.Context, name string, chunk []byte, annotations map[string]string) error {
+	obj := o.newChunkObject(name)
+	obj.SetAnnotations(annotations)
+	switch v := obj.(type) {
+	case *corev1.Secret:
+		v.Data = map[string][]byte{chunkDataKey: chunk}
+	case *corev1.ConfigMap:
+		v.BinaryData = map[string][]byte{chunkDataKey: chunk}
+	}
+	err := o.client.Create(ctx, obj)
+	if apierrors.IsAlreadyExists(err) {
+		err = o.client.Update(ctx, obj)
+	}
+	if err != nil {
+		return fmt.Errorf("error storing %s %s: %v", o.kind, obj.GetName(), err)
+	}
+	return nil
+}
+
+// Get reassembles the chunks of an artifact, the number of chunks is read from the first chunk
+func (o *objectStore) Get(ctx context.Context, location string) ([]byte, error) {
+	var data []byte
+	count := 1
+	for i := 0; i < count; i++ {
+		obj := o.newChunkObject(ChunkName(location, i))
+		if err := o.client.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return nil, fmt.Errorf("error fetching %s %s: %v", o.kind, obj.GetName(), err)
+		}
+		if i == 0 {
+			var err error
+			if count, err = strconv.Atoi(obj.GetAnnotations()[ChunksAnnotation]); err != nil || count < 1 {
+				return nil, fmt.Errorf("%s %s has an invalid %s annotation", o.kind, obj.GetName(), ChunksAnnotation)
+			}
+		}
+		switch v := obj.(type) {
+		case *corev1.Secret:
+			data = append(data, v.Data[chunkDataKey]...)
+		case *corev1.ConfigMap:
+			data = append(data, v.BinaryData[chunkDataKey]...)
+		}
+	}
+	return data, nil
+}
+
+// Delete removes chunks in order until no further chunk is found
+func (o *objectStore) Delete(ctx context.Context, location string) error {
+	for i := 0; ; i++ {
+		obj := o.newChunkObject(ChunkName(location, i))
+		if err := o.client.Delete(ctx, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return fmt.Errorf("error deleting %s %s: %v", o.kind, obj.GetName(), err)
+		}
+	}
+}
+
+func (o *objectStore) Close(_ context.Context) error {
+	return nil
+}
+
+func (o *objectStore) newChunkObject(name string) client.Object {
+	meta := metav1.ObjectMeta{Name: name, Namespace: o.namespace}
+	if o.kind == ovnoperatorv1.ObjectTargetConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: meta}
+	}
+	return &corev1.Secret{ObjectMeta: meta, Type: corev1.SecretTypeOpaque}
+}
+
+// ChunkName returns the name of the object storing chunk index of an artifact
+func ChunkName(name string, index int) string {
+	return fmt.Sprintf("%s-%d", name, index)
+}
@@ -0,0 +1,151 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/harvester/kubeovn-operator/internal/executor"
+)
+
+const (
+	helperContainerName      = "backup"
+	helperPodReadyTimeout    = 3 * time.Minute
+	helperPodPollingInterval = 2 * time.Second
+	// HostMountPath is where the host directory is mounted in pods created by NewHostPod
+	HostMountPath = "/etc/ovn"
+)
+
+// PodRunner runs commands in a short lived pod, by streaming input to the pod through the same exec path
+// used to run commands in ovn-central. The pod is created on first use and deleted on Close
+type PodRunner struct {
+	opts     StoreOptions
+	template *corev1.Pod
+	pod      *corev1.Pod
+}
+
+// NewHostPod returns a PodRunner for a privileged pod pinned to a node, with hostPath mounted at HostMountPath
+func NewHostPod(name string, nodeName string, hostPath string, opts StoreOptions) *PodRunner {
+	pod := newHelperPod(name, opts, corev1.VolumeSource{
+		HostPath: &corev1.HostPathVolumeSource{Path: hostPath},
+	}, HostMountPath)
+	pod.Spec.NodeName = nodeName
+	// master nodes are commonly tainted, the pod needs to run regardless as it is pinned to the node
+	pod.Spec.Tolerations = []corev1.Toleration{{Operator: corev1.TolerationOpExists}}
+	pod.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{
+		Privileged: ptr.To(true),
+		RunAsUser:  ptr.To(int64(0)),
+	}
+	return &PodRunner{opts: opts, template: pod}
+}
+
+// Run executes cmd in the pod with input as stdin and returns stdout
+func (p *PodRunner) Run(ctx context.Context, cmd string, input []byte) ([]byte, error) {
+	return p.RunWithInput(ctx, cmd, bytes.NewReader(input))
+}
+
+// RunWithInput executes cmd in the pod with input streamed to stdin and returns stdout
+func (p *PodRunner) RunWithInput(ctx context.Context, cmd string, input io.Reader) ([]byte, error) {
+	pod, err := p.ensurePod(ctx)
+	if err != nil {
+		return nil, err
+	}
+	podExecutor, err := executor.NewRemoteCommandExecutor(ctx, p.opts.RestConfig, pod)
+	if err != nil {
+		return nil, fmt.Errorf("error generating new remote command executor: %v", err)
+	}
+	result, err := podExecutor.RunWithInput(helperContainerName, cmd, input)
+	if err != nil {
+		return result, fmt.Errorf("error running %s in pod %s: %v", cmd, pod.GetName(), err)
+	}
+	return result, nil
+}
+
+// Close deletes the pod, so mounted claims can be used by other workloads
+func (p *PodRunner) Close(ctx context.Context) error {
+	if p.pod == nil {
+		return nil
+	}
+	if err := p.opts.Client.Delete(ctx, p.pod); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error deleting pod %s: %v", p.pod.GetName(), err)
+	}
+	p.pod = nil
+	return nil
+}
+
+// ensurePod creates the pod if needed and waits for it to be running
+func (p *PodRunner) ensurePod(ctx context.Context) (*corev1.Pod, error) {
+	if p.pod != nil {
+		return p.pod, nil
+	}
+
+	pod := p.template.DeepCopy()
+	if err := p.opts.Client.Create(ctx, pod); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("error creating pod %s: %v", pod.GetName(), err)
+	}
+
+	err := wait.PollUntilContextTimeout(ctx, helperPodPollingInterval, helperPodReadyTimeout, true, func(ctx context.Context) (bool, error) {
+		if err := p.opts.Client.Get(ctx, types.NamespacedName{Name: pod.GetName(), Namespace: pod.GetNamespace()}, pod); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		if pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded {
+			return false, fmt.Errorf("pod %s is in phase %s", pod.GetName(), pod.Status.Phase)
+		}
+		return pod.Status.Phase == corev1.PodRunning, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error waiting for pod %s to be running: %v", pod.GetName(), err)
+	}
+	p.pod = pod
+	return pod, nil
+}
+
+func newHelperPod(name string, opts StoreOptions, source corev1.VolumeSource, mountPath string) *corev1.Pod {
+	var pullSecrets []corev1.LocalObjectReference
+	for _, secret := range opts.ImagePullSecrets {
+		pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: secret})
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: opts.Namespace,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:    corev1.RestartPolicyNever,
+			ImagePullSecrets: pullSecrets,
+			Containers: []corev1.Container{
+				{
+					Name:    helperContainerName,
+					Image:   opts.Image,
+					Command: []string{"sleep", "infinity"},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "backup", MountPath: mountPath},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{Name: "backup", VolumeSource: source},
+			},
+		},
+	}
+}
+
+// PodName prefixes name and truncates the result to a valid pod name
+func PodName(prefix string, name string) string {
+	podName := prefix + name
+	if len(podName) > 63 {
+		podName = strings.TrimSuffix(podName[:63], "-")
+	}
+	return podName
+}
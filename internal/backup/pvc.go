@@ -0,0 +1,58 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+const pvcMountPath = "/backup"
+
+// pvcStore writes artifacts to a persistent volume claim mounted by a pod, by streaming the data to the pod
+// through the same exec path used to run commands in ovn-central
+type pvcStore struct {
+	target ovnoperatorv1.PVCBackupTarget
+	runner *PodRunner
+}
+
+func newPVCStore(target ovnoperatorv1.PVCBackupTarget, opts StoreOptions) *pvcStore {
+	pod := newHelperPod(PVCPodName(target.ClaimName), opts, corev1.VolumeSource{
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: target.ClaimName},
+	}, pvcMountPath)
+	return &pvcStore{target: target, runner: &PodRunner{opts: opts, template: pod}}
+}
+
+func (p *pvcStore) Put(ctx context.Context, name string, data io.Reader) (string, error) {
+	location := path.Join("/", p.target.Path, name)
+	filePath := path.Join(pvcMountPath, location)
+	if _, err := p.runner.RunWithInput(ctx, fmt.Sprintf("mkdir -p '%s' && cat > '%s'", path.Dir(filePath), filePath), data); err != nil {
+		// partially written files are removed on a best effort basis, as the stream may have been interrupted
+		_ = p.Delete(ctx, location)
+		return "", err
+	}
+	return location, nil
+}
+
+func (p *pvcStore) Get(ctx context.Context, location string) ([]byte, error) {
+	return p.runner.Run(ctx, fmt.Sprintf("cat '%s'", path.Join(pvcMountPath, location)), nil)
+}
+
+func (p *pvcStore) Delete(ctx context.Context, location string) error {
+	_, err := p.runner.Run(ctx, fmt.Sprintf("rm -f '%s'", path.Join(pvcMountPath, location)), nil)
+	return err
+}
+
+// Close deletes the pod mounting the claim, so the claim can be used by other workloads
+func (p *pvcStore) Close(ctx context.Context) error {
+	return p.runner.Close(ctx)
+}
+
+// PVCPodName is the name of the pod mounting a backup claim
+func PVCPodName(claimName string) string {
+	return PodName("ovn-backup-", claimName)
+}
@@ -0,0 +1,250 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+const (
+	S3AccessKeyID     = "accessKeyID"
+	S3SecretAccessKey = "secretAccessKey"
+	s3DefaultRegion   = "us-east-1"
+	s3RequestTimeout  = 5 * time.Minute
+	// s3PartSize is the size of each part of a multipart upload, which is the amount of an artifact held in memory
+	// while it is uploaded. S3 requires parts other than the last to be at least 5MiB
+	s3PartSize = 8 * 1024 * 1024
+)
+
+// s3Store stores artifacts in an S3 compatible bucket using path style requests signed with AWS signature v4
+type s3Store struct {
+	endpoint        *url.URL
+	bucket          string
+	prefix          string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	httpClient      *http.Client
+	partSize        int
+	now             func() time.Time
+}
+
+// s3CompleteMultipartUpload is the request body completing a multipart upload
+type s3CompleteMultipartUpload struct {
+	XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+	Parts   []s3CompletedPart `xml:"Part"`
+}
+
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+func newS3Store(ctx context.Context, target ovnoperatorv1.S3BackupTarget, opts StoreOptions) (*s3Store, error) {
+	secret := &corev1.Secret{}
+	if err := opts.Client.Get(ctx, types.NamespacedName{Name: target.CredentialsSecret, Namespace: opts.Namespace}, secret); err != nil {
+		return nil, fmt.Errorf("error fetching s3 credentials secret %s: %v", target.CredentialsSecret, err)
+	}
+	return newS3StoreWithCredentials(target, string(secret.Data[S3AccessKeyID]), string(secret.Data[S3SecretAccessKey]))
+}
+
+func newS3StoreWithCredentials(target ovnoperatorv1.S3BackupTarget, accessKeyID, secretAccessKey string) (*s3Store, error) {
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, fmt.Errorf("s3 credentials secret %s needs to contain %s and %s", target.CredentialsSecret, S3AccessKeyID, S3SecretAccessKey)
+	}
+	endpoint, err := url.Parse(target.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing s3 endpoint %s: %v", target.Endpoint, err)
+	}
+	region := target.Region
+	if region == "" {
+		region = s3DefaultRegion
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if target.InsecureSkipTLSVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec
+	}
+	return &s3Store{
+		endpoint:        endpoint,
+		bucket:          target.Bucket,
+		prefix:          target.Prefix,
+		region:          region,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		httpClient:      &http.Client{Transport: transport, Timeout: s3RequestTimeout},
+		partSize:        s3PartSize,
+		now:             time.Now,
+	}, nil
+}
+
+// Put uploads artifacts smaller than a single part in one request, larger artifacts are streamed using a
+// multipart upload
+func (s *s3Store) Put(ctx context.Context, name string, data io.Reader) (string, error) {
+	key := path.Join(s.prefix, name)
+	part := make([]byte, s.partSize)
+	n, err := io.ReadFull(data, part)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		if _, _, err := s.do(ctx, http.MethodPut, key, nil, part[:n]); err != nil {
+			return "", err
+		}
+		return key, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error reading artifact %s: %v", name, err)
+	}
+	if err := s.putMultipart(ctx, key, data, part); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// putMultipart uploads part followed by the remaining data, one part at a time. The upload is aborted if it
+// fails, so the bucket does not retain the parts uploaded so far
+func (s *s3Store) putMultipart(ctx context.Context, key string, data io.Reader, part []byte) error {
+	body, _, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil)
+	if err != nil {
+		return err
+	}
+	var upload struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.Unmarshal(body, &upload); err != nil || upload.UploadID == "" {
+		return fmt.Errorf("error parsing s3 multipart upload response for %s: %v", key, err)
+	}
+
+	if err := s.uploadParts(ctx, key, upload.UploadID, data, part); err != nil {
+		if _, _, abortErr := s.do(ctx, http.MethodDelete, key, url.Values{"uploadId": {upload.UploadID}}, nil); abortErr != nil {
+			return fmt.Errorf("%v, error aborting multipart upload: %v", err, abortErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *s3Store) uploadParts(ctx context.Context, key string, uploadID string, data io.Reader, part []byte) error {
+	complete := s3CompleteMultipartUpload{}
+	n := len(part)
+	for number := 1; n > 0; number++ {
+		query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+		_, header, err := s.do(ctx, http.MethodPut, key, query, part[:n])
+		if err != nil {
+			return err
+		}
+		complete.Parts = append(complete.Parts, s3CompletedPart{PartNumber: number, ETag: header.Get("ETag")})
+
+		n, err = io.ReadFull(data, part)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return fmt.Errorf("error reading artifact %s: %v", key, err)
+		}
+	}
+
+	body, err := xml.Marshal(complete)
+	if err != nil {
+		return fmt.Errorf("error generating s3 complete multipart upload request: %v", err)
+	}
+	respBody, _, err := s.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, body)
+	if err != nil {
+		return err
+	}
+	// completing an upload can fail after the response status has been sent, the error is returned in the body
+	if bytes.Contains(respBody, []byte("<Error>")) {
+		return fmt.Errorf("s3 multipart upload of %s failed: %s", key, string(respBody))
+	}
+	return nil
+}
+
+func (s *s3Store) Get(ctx context.Context, location string) ([]byte, error) {
+	body, _, err := s.do(ctx, http.MethodGet, location, nil, nil)
+	return body, err
+}
+
+func (s *s3Store) Delete(ctx context.Context, location string) error {
+	_, _, err := s.do(ctx, http.MethodDelete, location, nil, nil)
+	return err
+}
+
+func (s *s3Store) Close(_ context.Context) error {
+	return nil
+}
+
+func (s *s3Store) do(ctx context.Context, method string, key string, query url.Values, body []byte) ([]byte, http.Header, error) {
+	objectURL := s.endpoint.JoinPath(s.bucket, key)
+	// encoded query parameters are sorted by key, as required by the canonical request
+	objectURL.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating s3 request: %v", err)
+	}
+	s.sign(req, body)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error during s3 %s of %s: %v", method, key, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading s3 response: %v", err)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, nil, fmt.Errorf("s3 %s of %s failed with status %d: %s", method, key, resp.StatusCode, string(respBody))
+	}
+	return respBody, resp.Header, nil
+}
+
+// sign adds an AWS signature v4 authorization header to the request
+func (s *s3Store) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := Checksum(body)
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, payloadHash, amzDate)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, s.region)
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, Checksum([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
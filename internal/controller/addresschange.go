@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/ovsdb"
+)
+
+const addressChangeJobPrefix = "ovn-address-change-"
+
+// reconcileAddressChanges removes the previous address of re-addressed master nodes from the raft clusters. The
+// previous address is kicked from both databases on the leader, and the database files on the node are moved
+// aside as they record the previous address of the local member. Changes are dropped from the status once
+// complete, and status.matchingNodeAddresses is only updated once no changes remain, which rolls out ovn-central
+// one pod at a time with the new addresses
+func (r *ConfigurationReconciler) reconcileAddressChanges(ctx context.Context, config *kubeovniov1.Configuration, nodes []kubeovniov1.MatchingNode) {
+	for i := range config.Status.AddressChanges {
+		change := &config.Status.AddressChanges[i]
+		switch change.Phase {
+		case kubeovniov1.NodeAddressChangePhaseDetected:
+			if err := r.kickPreviousAddress(ctx, change); err != nil {
+				r.Log.Error(err, "error kicking previous address of master node", "node", change.Node)
+				change.Message = err.Error()
+				continue
+			}
+			// a node removed since the change was detected has no database files to move aside
+			if !slices.ContainsFunc(nodes, func(node kubeovniov1.MatchingNode) bool {
+				return node.Name == change.Node
+			}) {
+				change.Phase = ""
+				continue
+			}
+			var files []string
+			for _, db := range restoreDatabases {
+				files = append(files, db.file)
+			}
+			job, err := newMoveDatabasesJob(config, r.Namespace, addressChangeJobPrefix, change.Node, files,
+				fmt.Sprintf("readdressed-%d", time.Now().Unix()))
+			if err == nil {
+				err = r.Create(ctx, job)
+			}
+			if err != nil {
+				r.Log.Error(err, "error creating job to move database files aside", "node", change.Node)
+				change.Message = fmt.Sprintf("kicked address %s, error moving database files aside: %v", change.PreviousAddress, err)
+				continue
+			}
+			change.JobName = job.GetName()
+			setAddressChangePhase(change, kubeovniov1.NodeAddressChangePhaseRebuilding,
+				fmt.Sprintf("kicked address %s, moving database files on node %s aside using job %s", change.PreviousAddress, change.Node, job.GetName()))
+			r.EventRecorder.Event(config, corev1.EventTypeNormal, "MasterAddressChanged", change.Message)
+		case kubeovniov1.NodeAddressChangePhaseRebuilding:
+			r.checkAddressChangeJob(ctx, config, change)
+		}
+	}
+
+	config.Status.AddressChanges = slices.DeleteFunc(config.Status.AddressChanges, func(change kubeovniov1.NodeAddressChange) bool {
+		return change.Phase == ""
+	})
+}
+
+// kickPreviousAddress kicks the previous address of a node from both databases by its exact server id, read from
+// the cluster status reported by each leader. Databases where the address is no longer a member are skipped
+func (r *ConfigurationReconciler) kickPreviousAddress(ctx context.Context, change *kubeovniov1.NodeAddressChange) error {
+	for _, db := range restoreDatabases {
+		output, err := executeOVNCentralCommand(ctx, db.statusCheck, db.leaderLabel, r.Client, r.CommandRunner, r.Namespace)
+		if err != nil {
+			return fmt.Errorf("error fetching %s cluster status %s: %v", db.name, string(output), err)
+		}
+		status, err := ovsdb.ParseClusterStatus(output)
+		if err != nil {
+			return fmt.Errorf("error parsing %s cluster status: %v", db.name, err)
+		}
+		result, err := kickRaftMember(ctx, r.Client, r.CommandRunner, r.Namespace, db, status, change.PreviousAddress)
+		if err != nil && !errors.Is(err, ovsdb.ErrMemberNotFound) {
+			return err
+		}
+		if err != nil {
+			result = err.Error()
+		}
+		r.Log.WithValues("node", change.Node, "database", db.name).Info(result)
+	}
+	return nil
+}
+
+// checkAddressChangeJob completes the change once the job has finished. A failed job does not block the rollout,
+// as the previous address has already been kicked, but the database files on the node need to be removed manually
+func (r *ConfigurationReconciler) checkAddressChangeJob(ctx context.Context, config *kubeovniov1.Configuration, change *kubeovniov1.NodeAddressChange) {
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, types.NamespacedName{Name: change.JobName, Namespace: r.Namespace}, job); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.EventRecorder.Event(config, corev1.EventTypeWarning, "MasterAddressChangeFailed",
+				fmt.Sprintf("job %s not found, database files on node %s may need to be removed manually", change.JobName, change.Node))
+			change.Phase = ""
+		} else {
+			r.Log.Error(err, "error fetching address change job", "job", change.JobName)
+		}
+		return
+	}
+
+	switch {
+	case job.Status.Succeeded > 0:
+		r.EventRecorder.Event(config, corev1.EventTypeNormal, "MasterAddressChanged",
+			fmt.Sprintf("removed address %s of node %s from the raft clusters, rolling out ovn-central with address %s", change.PreviousAddress, change.Node, change.Address))
+		change.Phase = ""
+	case job.Status.Failed > 0 && job.Status.Active == 0:
+		r.EventRecorder.Event(config, corev1.EventTypeWarning, "MasterAddressChangeFailed",
+			fmt.Sprintf("job %s failed, database files on node %s need to be removed manually", job.GetName(), change.Node))
+		change.Phase = ""
+	}
+}
+
+func setAddressChangePhase(change *kubeovniov1.NodeAddressChange, phase string, message string) {
+	change.Phase = phase
+	change.Message = message
+	change.LastTransitionTime = metav1.Now()
+}
@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+const (
+	testNBKickScript = "ovs-appctl -t /var/run/ovn/ovnnb_db.ctl cluster/kick OVN_Northbound a8b1"
+	testSBKickScript = "ovs-appctl -t /var/run/ovn/ovnsb_db.ctl cluster/kick OVN_Southbound a8b1"
+)
+
+// testClusterStatusOutput returns the cluster/status output of the leader on 10.0.0.1 with a member for each address
+func testClusterStatusOutput(database string, port int, members map[string]string) string {
+	var servers []string
+	for id, address := range members {
+		self := ""
+		if address == "10.0.0.1" {
+			self = " (self)"
+		}
+		servers = append(servers, fmt.Sprintf("    %s (%s at tcp:[%s]:%d)%s next_index=23 match_index=22", id, id, address, port, self))
+	}
+	return fmt.Sprintf(`e2d5
+Name: %s
+Server ID: e2d5 (e2d5ff61-5b8c-4c9f-9c0c-2bd0e54c8d7b)
+Address: tcp:[10.0.0.1]:%d
+Status: cluster member
+Role: leader
+Term: 3
+Leader: self
+Election timer: 1000
+Log: [2, 23]
+Servers:
+%s
+`, database, port, strings.Join(servers, "\n"))
+}
+
+// newAddressChangeTestExec returns cluster status for both databases, where node2 is a member with its previous
+// address 10.0.0.2, and 10.0.0.21 on node3 has 10.0.0.2 as a prefix
+func newAddressChangeTestExec(members map[string]string) *fakeExec {
+	exec := newFakeExec()
+	exec.outputs[kubeovniov1.NBCheckScript] = testClusterStatusOutput("OVN_Northbound", kubeovniov1.NBRaftPort, members)
+	exec.outputs[kubeovniov1.SBCheckScript] = testClusterStatusOutput("OVN_Southbound", kubeovniov1.SBRaftPort, members)
+	return exec
+}
+
+func testAddressChangeMembers() map[string]string {
+	return map[string]string{"e2d5": "10.0.0.1", "a8b1": "10.0.0.2", "c9d0": "10.0.0.21"}
+}
+
+// newAddressChangeTestReconciler returns a reconciler where node2 has changed its address from 10.0.0.2 to
+// 10.0.0.3 since ovn-central was last rendered
+func newAddressChangeTestReconciler(t *testing.T, exec *fakeExec, objs ...client.Object) (*ConfigurationReconciler, *kubeovniov1.Configuration, *record.FakeRecorder) {
+	config := newTestConfiguration()
+	config.Status.MatchingNodeAddresses = []string{"10.0.0.1", "10.0.0.2", "10.0.0.21"}
+	config.Status.MatchingNodes = []kubeovniov1.MatchingNode{
+		{Name: "node1", Address: "10.0.0.1"},
+		{Name: "node2", Address: "10.0.0.2"},
+		{Name: "node3", Address: "10.0.0.21"},
+	}
+	if len(objs) == 0 {
+		objs = []client.Object{
+			newTestMasterNode("node1", "10.0.0.1"),
+			newTestMasterNode("node2", "10.0.0.3"),
+			newTestMasterNode("node3", "10.0.0.21"),
+		}
+	}
+	objs = append(objs, newTestOVNCentralPod("ovn-central-1", "node1", true))
+	recorder := record.NewFakeRecorder(100)
+	return &ConfigurationReconciler{
+		Client: fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(objs...).
+			WithStatusSubresource(&batchv1.Job{}).Build(),
+		CommandRunner: exec,
+		EventRecorder: recorder,
+		Namespace:     defaultKubeovnNamespace,
+		Log:           logr.Discard(),
+	}, config, recorder
+}
+
+func listJobs(t *testing.T, k8sClient client.Client) []batchv1.Job {
+	jobs := &batchv1.JobList{}
+	require.NoError(t, k8sClient.List(context.TODO(), jobs))
+	return jobs.Items
+}
+
+func Test_AddressChangePhases(t *testing.T) {
+	assert := require.New(t)
+	exec := newAddressChangeTestExec(testAddressChangeMembers())
+	r, config, recorder := newAddressChangeTestReconciler(t, exec)
+
+	// the previous address is kicked from both databases by its exact server id, and the rendered addresses are kept
+	assert.NoError(r.findMasterNodes(context.TODO(), config))
+	assert.Len(config.Status.AddressChanges, 1)
+	change := config.Status.AddressChanges[0]
+	assert.Equal("node2", change.Node)
+	assert.Equal("10.0.0.2", change.PreviousAddress)
+	assert.Equal("10.0.0.3", change.Address)
+	assert.Equal(kubeovniov1.NodeAddressChangePhaseRebuilding, change.Phase)
+	assert.Equal([]string{kubeovniov1.NBCheckScript, testNBKickScript, kubeovniov1.SBCheckScript, testSBKickScript}, exec.scripts)
+	assert.Equal([]string{"10.0.0.1", "10.0.0.2", "10.0.0.21"}, config.Status.MatchingNodeAddresses)
+	requireEvent(t, recorder, "MasterAddressChanged")
+
+	jobs := listJobs(t, r.Client)
+	assert.Len(jobs, 1)
+	assert.Equal(change.JobName, jobs[0].Name)
+	assert.Equal("node2", jobs[0].Spec.Template.Spec.NodeName)
+	assert.Contains(jobs[0].Spec.Template.Spec.Containers[0].Command[2], "ovnnb_db.db")
+	assert.Contains(jobs[0].Spec.Template.Spec.Containers[0].Command[2], "ovnsb_db.db")
+
+	// nothing changes while the job is running
+	assert.NoError(r.findMasterNodes(context.TODO(), config))
+	assert.Equal(kubeovniov1.NodeAddressChangePhaseRebuilding, config.Status.AddressChanges[0].Phase)
+	assert.Len(exec.scripts, 4)
+	assert.Equal([]string{"10.0.0.1", "10.0.0.2", "10.0.0.21"}, config.Status.MatchingNodeAddresses)
+
+	// the new addresses are rendered once the database files have been moved aside
+	setJobStatus(t, r.Client, change.JobName, batchv1.JobStatus{Succeeded: 1})
+	assert.NoError(r.findMasterNodes(context.TODO(), config))
+	assert.Empty(config.Status.AddressChanges)
+	assert.ElementsMatch([]string{"10.0.0.1", "10.0.0.3", "10.0.0.21"}, config.Status.MatchingNodeAddresses)
+	assert.Contains(config.Status.MatchingNodes, kubeovniov1.MatchingNode{Name: "node2", Address: "10.0.0.3"})
+	requireEvent(t, recorder, "MasterAddressChanged")
+	assert.Len(exec.scripts, 4)
+}
+
+func Test_AddressChangeKickFailures(t *testing.T) {
+	assert := require.New(t)
+	exec := newAddressChangeTestExec(testAddressChangeMembers())
+	r, config, _ := newAddressChangeTestReconciler(t, exec)
+
+	// a failed kick is retried without creating a job or rendering the new addresses
+	exec.err = errors.New("connection refused")
+	assert.NoError(r.findMasterNodes(context.TODO(), config))
+	assert.Equal(kubeovniov1.NodeAddressChangePhaseDetected, config.Status.AddressChanges[0].Phase)
+	assert.Contains(config.Status.AddressChanges[0].Message, "connection refused")
+	assert.Empty(listJobs(t, r.Client))
+	assert.Equal([]string{"10.0.0.1", "10.0.0.2", "10.0.0.21"}, config.Status.MatchingNodeAddresses)
+
+	// an address shared by more than one member is never kicked
+	exec = newAddressChangeTestExec(map[string]string{"e2d5": "10.0.0.1", "a8b1": "10.0.0.2", "f00d": "10.0.0.2"})
+	r.CommandRunner = exec
+	assert.NoError(r.findMasterNodes(context.TODO(), config))
+	assert.Equal(kubeovniov1.NodeAddressChangePhaseDetected, config.Status.AddressChanges[0].Phase)
+	assert.Contains(config.Status.AddressChanges[0].Message, "found 2 raft members with address 10.0.0.2")
+	assert.Equal([]string{kubeovniov1.NBCheckScript}, exec.scripts)
+	assert.Empty(listJobs(t, r.Client))
+
+	// an address which is no longer a member is not kicked, and its database files are still moved aside
+	exec = newAddressChangeTestExec(map[string]string{"e2d5": "10.0.0.1", "c9d0": "10.0.0.21"})
+	r.CommandRunner = exec
+	assert.NoError(r.findMasterNodes(context.TODO(), config))
+	assert.Equal(kubeovniov1.NodeAddressChangePhaseRebuilding, config.Status.AddressChanges[0].Phase)
+	assert.Equal([]string{kubeovniov1.NBCheckScript, kubeovniov1.SBCheckScript}, exec.scripts)
+	assert.Len(listJobs(t, r.Client), 1)
+}
+
+func Test_AddressChangeJobFailed(t *testing.T) {
+	assert := require.New(t)
+	exec := newAddressChangeTestExec(testAddressChangeMembers())
+	r, config, recorder := newAddressChangeTestReconciler(t, exec)
+
+	assert.NoError(r.findMasterNodes(context.TODO(), config))
+	setJobStatus(t, r.Client, config.Status.AddressChanges[0].JobName, batchv1.JobStatus{Failed: 3})
+
+	// the previous address has been kicked, so the new addresses are rendered
+	assert.NoError(r.findMasterNodes(context.TODO(), config))
+	assert.Empty(config.Status.AddressChanges)
+	assert.ElementsMatch([]string{"10.0.0.1", "10.0.0.3", "10.0.0.21"}, config.Status.MatchingNodeAddresses)
+	requireEvent(t, recorder, "MasterAddressChangeFailed")
+}
+
+func Test_AddressChangePaused(t *testing.T) {
+	assert := require.New(t)
+	exec := newAddressChangeTestExec(testAddressChangeMembers())
+	r, config, _ := newAddressChangeTestReconciler(t, exec)
+	config.Spec.Paused = true
+
+	// the change is recorded, but raft membership and the rendered addresses are not changed
+	assert.NoError(r.findMasterNodes(context.TODO(), config))
+	assert.Len(config.Status.AddressChanges, 1)
+	assert.Equal(kubeovniov1.NodeAddressChangePhaseDetected, config.Status.AddressChanges[0].Phase)
+	assert.Empty(exec.scripts)
+	assert.Equal([]string{"10.0.0.1", "10.0.0.2", "10.0.0.21"}, config.Status.MatchingNodeAddresses)
+
+	config.Spec.Paused = false
+	assert.NoError(r.findMasterNodes(context.TODO(), config))
+	assert.Equal(kubeovniov1.NodeAddressChangePhaseRebuilding, config.Status.AddressChanges[0].Phase)
+}
+
+func Test_AddressChangeNodeRemoved(t *testing.T) {
+	assert := require.New(t)
+	exec := newAddressChangeTestExec(testAddressChangeMembers())
+	r, config, _ := newAddressChangeTestReconciler(t, exec,
+		newTestMasterNode("node1", "10.0.0.1"),
+		newTestMasterNode("node3", "10.0.0.21"),
+	)
+	now := metav1.Now()
+	config.Status.AddressChanges = []kubeovniov1.NodeAddressChange{
+		{Node: "node2", PreviousAddress: "10.0.0.2", Address: "10.0.0.3", Phase: kubeovniov1.NodeAddressChangePhaseDetected,
+			DetectedTime: now, LastTransitionTime: now},
+	}
+
+	// the previous address of a node removed before the change started is kicked, without moving files aside
+	assert.NoError(r.findMasterNodes(context.TODO(), config))
+	assert.Empty(config.Status.AddressChanges)
+	assert.Contains(exec.scripts, testNBKickScript)
+	assert.Contains(exec.scripts, testSBKickScript)
+	assert.Empty(listJobs(t, r.Client))
+	assert.ElementsMatch([]string{"10.0.0.1", "10.0.0.21"}, config.Status.MatchingNodeAddresses)
+	assert.Equal([]kubeovniov1.MatchingNode{{Name: "node1", Address: "10.0.0.1"}, {Name: "node3", Address: "10.0.0.21"}}, config.Status.MatchingNodes)
+}
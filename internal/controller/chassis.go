@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/ovsdb"
+	"github.com/harvester/kubeovn-operator/internal/render"
+)
+
+// hostnameLabel is set by the kubelet to the hostname of the node, which is used by ovn-controller
+// as the chassis hostname
+const hostnameLabel = "kubernetes.io/hostname"
+
+const (
+	defaultChassisGCInterval    = time.Hour
+	defaultChassisGCGracePeriod = 24 * time.Hour
+)
+
+// auditChassis lists chassis records from the southbound leader and compares them with the nodes and ovs-ovn pods.
+// chassis are only cleaned up when a node is deleted, so reinstalled or renamed nodes can leave stale chassis behind
+func (r *HealthCheckReconciler) auditChassis(ctx context.Context, config *kubeovniov1.Configuration) error {
+	result, err := executeOVNCentralCommand(ctx, kubeovniov1.ChassisListScript, kubeovniov1.SBLeaderLabel, r.Client, r.CommandRunner, r.Namespace)
+	if err != nil {
+		return fmt.Errorf("error listing chassis %s: %v", string(result), err)
+	}
+	chassis, err := ovsdb.ParseChassisList(result)
+	if err != nil {
+		return err
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := r.List(ctx, nodeList); err != nil {
+		return fmt.Errorf("error listing nodes: %v", err)
+	}
+	nodeHostnames := make(map[string]string, len(nodeList.Items))
+	for _, node := range nodeList.Items {
+		nodeHostnames[node.GetName()] = node.GetLabels()[hostnameLabel]
+	}
+
+	ovsPods, err := podList(ctx, kubeovniov1.OVSOVNLabel, r.Client, r.Namespace)
+	if err != nil {
+		return fmt.Errorf("error fetching ovs-ovn pods: %v", err)
+	}
+	var ovsNodes []string
+	for _, pod := range ovsPods.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.Spec.NodeName != "" {
+			ovsNodes = append(ovsNodes, pod.Spec.NodeName)
+		}
+	}
+
+	previous := config.Status.Chassis
+	status := ovsdb.AuditChassis(chassis, nodeHostnames, ovsNodes)
+	now := metav1.Now()
+	ovsdb.RetainDetectedTime(previous.StaleChassis, status.StaleChassis, now)
+	ovsdb.RetainDetectedTime(previous.DuplicateChassis, status.DuplicateChassis, now)
+	status.LastAuditTime = now
+	r.recordChassisEvents(config, previous, status)
+	config.Status.Chassis = status
+
+	if len(status.StaleChassis) != 0 || len(status.DuplicateChassis) != 0 || len(status.NodesWithoutChassis) != 0 {
+		config.SetCondition(kubeovniov1.ChassisConsistent, metav1.ConditionFalse,
+			fmt.Sprintf("%d stale chassis, %d duplicate chassis, %d nodes without chassis",
+				len(status.StaleChassis), len(status.DuplicateChassis), len(status.NodesWithoutChassis)), kubeovniov1.ChassisInconsistentReason)
+	} else {
+		config.SetCondition(kubeovniov1.ChassisConsistent, metav1.ConditionTrue, fmt.Sprintf("%d chassis match nodes", len(chassis)), kubeovniov1.ChassisConsistentReason)
+	}
+	return nil
+}
+
+// recordChassisEvents generates a warning event for each inconsistency which was not reported by the previous audit
+func (r *HealthCheckReconciler) recordChassisEvents(config *kubeovniov1.Configuration, previous, current kubeovniov1.ChassisAuditStatus) {
+	for _, c := range current.StaleChassis {
+		if !containsChassis(previous.StaleChassis, c) {
+			r.EventRecorder.Event(config, corev1.EventTypeWarning, "StaleChassis",
+				fmt.Sprintf("chassis %s with hostname %s does not match any node", c.Name, c.Hostname))
+		}
+	}
+	for _, c := range current.DuplicateChassis {
+		if !containsChassis(previous.DuplicateChassis, c) {
+			r.EventRecorder.Event(config, corev1.EventTypeWarning, "DuplicateChassis",
+				fmt.Sprintf("chassis %s shares hostname %s with another chassis", c.Name, c.Hostname))
+		}
+	}
+	for _, node := range current.NodesWithoutChassis {
+		if !slices.Contains(previous.NodesWithoutChassis, node) {
+			r.EventRecorder.Event(config, corev1.EventTypeWarning, "NodeWithoutChassis",
+				fmt.Sprintf("node %s is running ovs-ovn but has no chassis", node))
+		}
+	}
+}
+
+func containsChassis(refs []kubeovniov1.ChassisReference, ref kubeovniov1.ChassisReference) bool {
+	return slices.ContainsFunc(refs, func(r kubeovniov1.ChassisReference) bool {
+		return r.Name == ref.Name && r.Hostname == ref.Hostname
+	})
+}
+
+// collectStaleChassis deletes chassis which have been stale for longer than the grace period. Chassis are
+// otherwise only deleted by the node finalizer, which does not run when nodes are force removed or reimaged
+func (r *HealthCheckReconciler) collectStaleChassis(ctx context.Context, config *kubeovniov1.Configuration) error {
+	policy := config.Spec.Maintenance.ChassisGC
+	if !policy.Enabled || config.Spec.Paused {
+		return nil
+	}
+
+	interval := durationOrDefault(policy.Interval, defaultChassisGCInterval)
+	gracePeriod := durationOrDefault(policy.GracePeriod, defaultChassisGCGracePeriod)
+	now := metav1.Now()
+	if config.Status.Chassis.LastCollectionTime.Add(interval).After(now.Time) {
+		return nil
+	}
+
+	var collected, remaining []kubeovniov1.ChassisReference
+	for _, c := range config.Status.Chassis.StaleChassis {
+		if c.DetectedTime.Add(gracePeriod).After(now.Time) {
+			remaining = append(remaining, c)
+			continue
+		}
+
+		if policy.DryRun {
+			r.EventRecorder.Event(config, corev1.EventTypeNormal, "ChassisGCDryRun",
+				fmt.Sprintf("stale chassis %s with hostname %s would be deleted", c.Name, c.Hostname))
+			collected = append(collected, c)
+			remaining = append(remaining, c)
+			continue
+		}
+
+		// chassis names are read from the southbound database, names which are not safe to use in a script are
+		// reported and left for manual cleanup
+		script, err := render.GenerateChassisDeleteScript(c.Name)
+		if err != nil {
+			r.Log.Error(err, "error rendering chassis delete script", "chassis", c.Name)
+			r.EventRecorder.Event(config, corev1.EventTypeWarning, "ChassisDeleteFailed",
+				fmt.Sprintf("stale chassis with hostname %s needs to be deleted manually: %v", c.Hostname, err))
+			remaining = append(remaining, c)
+			continue
+		}
+		r.Log.WithValues("chassis", c.Name, "hostname", c.Hostname).Info("deleting stale chassis")
+		// failed deletions are retried on the next run, chassis already deleted are still recorded in status
+		if result, err := executeOVNCentralCommand(ctx, script, kubeovniov1.SBLeaderLabel, r.Client, r.CommandRunner, r.Namespace); err != nil {
+			r.Log.Error(err, "error deleting stale chassis", "chassis", c.Name, "command output", string(result))
+			r.EventRecorder.Event(config, corev1.EventTypeWarning, "ChassisDeleteFailed",
+				fmt.Sprintf("error deleting stale chassis %s with hostname %s: %v", c.Name, c.Hostname, err))
+			remaining = append(remaining, c)
+			continue
+		}
+		r.EventRecorder.Event(config, corev1.EventTypeNormal, "ChassisDeleted",
+			fmt.Sprintf("stale chassis %s with hostname %s has been deleted", c.Name, c.Hostname))
+		collected = append(collected, c)
+	}
+
+	config.Status.Chassis.StaleChassis = remaining
+	config.Status.Chassis.CollectedChassis = collected
+	config.Status.Chassis.LastCollectionTime = now
+	return nil
+}
+
+func durationOrDefault(d metav1.Duration, defaultDuration time.Duration) time.Duration {
+	if d.Duration <= 0 {
+		return defaultDuration
+	}
+	return d.Duration
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/executor"
+	"github.com/harvester/kubeovn-operator/internal/ovsdb"
+)
+
+// ovnDatabaseMaintenance are the scripts used to monitor and compact a database
+type ovnDatabaseMaintenance struct {
+	name           string
+	leaderLabel    string
+	memoryScript   string
+	fileSizeScript string
+	compactScript  string
+}
+
+var (
+	nbMaintenance = ovnDatabaseMaintenance{
+		name:           kubeovniov1.NBDatabase,
+		leaderLabel:    kubeovniov1.NBLeaderLabel,
+		memoryScript:   kubeovniov1.NBMemoryScript,
+		fileSizeScript: kubeovniov1.NBFileSizeScript,
+		compactScript:  kubeovniov1.NBCompactScript,
+	}
+	sbMaintenance = ovnDatabaseMaintenance{
+		name:           kubeovniov1.SBDatabase,
+		leaderLabel:    kubeovniov1.SBLeaderLabel,
+		memoryScript:   kubeovniov1.SBMemoryScript,
+		fileSizeScript: kubeovniov1.SBFileSizeScript,
+		compactScript:  kubeovniov1.SBCompactScript,
+	}
+)
+
+// collectDBUsage records the database file size and memory usage of the leader in status. Failures are logged
+// and leave the usage unset, as they should not affect the health conditions set from cluster/status
+func (r *HealthCheckReconciler) collectDBUsage(ctx context.Context, db ovnDatabaseMaintenance, status *kubeovniov1.OVNDatabaseStatus, previous *kubeovniov1.OVNDatabaseStatus) {
+	if status == nil {
+		return
+	}
+	if previous != nil {
+		status.LastCompactionTime = previous.LastCompactionTime
+	}
+
+	result, err := executeOVNCentralCommand(ctx, db.memoryScript, db.leaderLabel, r.Client, r.CommandRunner, r.Namespace)
+	if err != nil {
+		r.Log.Error(err, "memory usage check failure", "database", db.name, "command output", string(result))
+	} else if status.Memory, err = ovsdb.ParseMemoryShow(result); err != nil {
+		r.Log.Error(err, "error parsing memory usage", "database", db.name)
+	}
+
+	result, err = executeOVNCentralCommand(ctx, db.fileSizeScript, db.leaderLabel, r.Client, r.CommandRunner, r.Namespace)
+	if err != nil {
+		r.Log.Error(err, "file size check failure", "database", db.name, "command output", string(result))
+	} else if status.FileSize, err = ovsdb.ParseFileSize(result); err != nil {
+		r.Log.Error(err, "error parsing file size", "database", db.name)
+	}
+
+	ovnDatabaseFileSize.WithLabelValues(db.name).Set(float64(status.FileSize))
+	ovnDatabaseLogLength.WithLabelValues(db.name).Set(float64(status.LogLength))
+}
+
+// reconcileCompaction compacts databases exceeding the thresholds in spec.maintenance.compaction, or both
+// databases when requested using the compact annotation. Thresholds are evaluated against the usage recorded
+// by the last healthcheck, and are ignored while the configuration is paused
+func (r *HealthCheckReconciler) reconcileCompaction(ctx context.Context, config *kubeovniov1.Configuration) error {
+	_, requested := config.GetAnnotations()[kubeovniov1.CompactAnnotation]
+	policy := config.Spec.Maintenance.Compaction
+	now := metav1.Now()
+
+	databases := []struct {
+		maintenance ovnDatabaseMaintenance
+		status      *kubeovniov1.OVNDatabaseStatus
+	}{
+		{maintenance: nbMaintenance, status: config.Status.OVNDatabases.Northbound},
+		{maintenance: sbMaintenance, status: config.Status.OVNDatabases.Southbound},
+	}
+	for _, db := range databases {
+		var reason string
+		if requested {
+			reason = fmt.Sprintf("requested by annotation %s", kubeovniov1.CompactAnnotation)
+		} else if !config.Spec.Paused {
+			reason = ovsdb.CompactionReason(policy, db.status, now)
+		}
+		if reason == "" {
+			continue
+		}
+
+		r.Log.WithValues("database", db.maintenance.name, "reason", reason).Info("compacting database")
+		if err := r.compactDatabase(ctx, db.maintenance); err != nil {
+			r.Log.Error(err, "database compaction failure", "database", db.maintenance.name)
+			r.EventRecorder.Event(config, corev1.EventTypeWarning, "DatabaseCompactionFailed",
+				fmt.Sprintf("error compacting %s: %v", db.maintenance.name, err))
+			continue
+		}
+		r.EventRecorder.Event(config, corev1.EventTypeNormal, "DatabaseCompacted",
+			fmt.Sprintf("compacted %s, %s", db.maintenance.name, reason))
+		if db.status != nil {
+			db.status.LastCompactionTime = &now
+		}
+	}
+
+	if !requested {
+		return nil
+	}
+	// the annotation is removed using a copy, so the status computed by the healthcheck is not overwritten
+	// by the response of the patch
+	annotated := config.DeepCopy()
+	patched := config.DeepCopy()
+	delete(patched.Annotations, kubeovniov1.CompactAnnotation)
+	if err := r.Patch(ctx, patched, client.MergeFrom(annotated)); err != nil {
+		return fmt.Errorf("error removing annotation %s: %v", kubeovniov1.CompactAnnotation, err)
+	}
+	return nil
+}
+
+// compactDatabase runs ovsdb-server/compact on each running ovn-central pod, as every raft member keeps its own
+// log and database file. Followers are compacted before the leader
+func (r *HealthCheckReconciler) compactDatabase(ctx context.Context, db ovnDatabaseMaintenance) error {
+	selector, err := labels.Parse(db.leaderLabel)
+	if err != nil {
+		return fmt.Errorf("error parsing label %s: %v", db.leaderLabel, err)
+	}
+	pods, err := podList(ctx, kubeovniov1.OVNCentralLabel, r.Client, r.Namespace)
+	if err != nil {
+		return fmt.Errorf("error fetching ovn-central pods: %v", err)
+	}
+	slices.SortStableFunc(pods.Items, func(a, b corev1.Pod) int {
+		if selector.Matches(labels.Set(a.GetLabels())) == selector.Matches(labels.Set(b.GetLabels())) {
+			return 0
+		}
+		if selector.Matches(labels.Set(a.GetLabels())) {
+			return 1
+		}
+		return -1
+	})
+
+	var errs []error
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		podExecutor, err := executor.NewRemoteCommandExecutor(ctx, r.RestConfig, &pod)
+		if err != nil {
+			return fmt.Errorf("error generating new remote command executor: %v", err)
+		}
+		if result, err := podExecutor.Run(kubeovniov1.OVNCentralContainerName, db.compactScript); err != nil {
+			errs = append(errs, fmt.Errorf("pod %s: %s %v", pod.GetName(), string(result), err))
+		}
+	}
+	return errors.Join(errs...)
+}
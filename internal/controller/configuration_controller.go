@@ -18,6 +18,9 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"slices"
@@ -26,6 +29,7 @@ import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
@@ -46,6 +50,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/executor"
+	"github.com/harvester/kubeovn-operator/internal/ovsdb"
 	"github.com/harvester/kubeovn-operator/internal/render"
 	"github.com/harvester/kubeovn-operator/internal/templates"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -55,6 +61,7 @@ import (
 type ConfigurationReconciler struct {
 	client.Client
 	RestConfig    *rest.Config
+	CommandRunner executor.CommandRunner
 	Scheme        *runtime.Scheme
 	Namespace     string
 	EventRecorder record.EventRecorder
@@ -74,6 +81,7 @@ type reconcileFuncs func(context.Context, *kubeovniov1.Configuration) error
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=issuers;certificates,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=endpoints,verbs=get;list;watch;create;update;patch;delete
@@ -108,6 +116,17 @@ func (r *ConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	config := configObj.DeepCopy()
+	// only the default configuration is authoritative. the node controller and bootstrapper always
+	// resolve the default configuration, so rendering any other object would leave them out of sync
+	if !isDefaultConfiguration(config, r.Namespace) {
+		r.Log.WithValues("name", config.Name).Info("ignoring configuration, only the default configuration is reconciled", "default", kubeovniov1.DefaultConfigurationName)
+		if config.DeletionTimestamp != nil && controllerutil.ContainsFinalizer(config, kubeovniov1.KubeOVNConfigurationFinalizer) {
+			controllerutil.RemoveFinalizer(config, kubeovniov1.KubeOVNConfigurationFinalizer)
+			return ctrl.Result{}, r.Client.Patch(ctx, config, client.MergeFrom(configObj))
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// if deletiontimestamp is set, then no further processing is needed as we let k8s gc the associated objects
 	if config.DeletionTimestamp != nil {
 		return reconcile.Result{}, r.deleteClusterScopedReference(ctx, config)
@@ -127,11 +146,17 @@ func (r *ConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		}
 	}
 
+	// not all phase health checks are driven by watched objects, so requeue until the rollout completes
+	result := ctrl.Result{}
+	if rolloutInProgress(config) || len(config.Status.AddressChanges) != 0 {
+		result.RequeueAfter = phaseRequeueInterval
+	}
+
 	if reflect.DeepEqual(configObj.Status, config.Status) {
-		return ctrl.Result{}, nil
+		return result, nil
 	}
 
-	return ctrl.Result{}, r.Client.Status().Patch(ctx, config, client.MergeFrom(configObj))
+	return result, r.Client.Status().Patch(ctx, config, client.MergeFrom(configObj))
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -142,15 +167,22 @@ func (r *ConfigurationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return r.AddWatches(b).Complete(r)
 }
 
-// applyObject will check if Config object is not already deploying. If a change is needed then it triggers
-// create/update of associated objects
+// applyObject triggers create/update of associated objects. Objects are applied in phases, and each phase
+// needs to be healthy before the next phase is applied
 func (r *ConfigurationReconciler) applyObject(ctx context.Context, config *kubeovniov1.Configuration) error {
-	if len(config.Status.MatchingNodeAddresses) == 0 {
-		r.Log.WithValues("name", config.Name).Info("waiting for matching master node requirement to be met")
+	if config.Spec.Paused {
+		if !config.ConditionTrue(kubeovniov1.PausedCondition) {
+			config.SetCondition(kubeovniov1.PausedCondition, metav1.ConditionTrue, "reconcile of managed objects is paused", kubeovniov1.PausedReason)
+		}
+		r.Log.WithValues("name", config.Name).Info("configuration is paused, skipping applying objects")
 		return nil
 	}
-	if config.Status.Status == kubeovniov1.ConfigurationStatusDeploying {
-		r.Log.WithValues("name", config.Name).Info("skipping applying objects as objects are already deploying")
+	if config.ConditionTrue(kubeovniov1.PausedCondition) {
+		config.SetCondition(kubeovniov1.PausedCondition, metav1.ConditionFalse, "reconcile of managed objects has resumed", kubeovniov1.ResumedReason)
+	}
+
+	if len(config.Status.MatchingNodeAddresses) == 0 {
+		r.Log.WithValues("name", config.Name).Info("waiting for matching master node requirement to be met")
 		return nil
 	}
 
@@ -160,57 +192,249 @@ func (r *ConfigurationReconciler) applyObject(ctx context.Context, config *kubeo
 		return fmt.Errorf("error looking up fake namespaced object: %v", err)
 	}
 
-	// fetch webhook secret which will be used for validating webhook
+	// fetch webhook secret which will be used for validating webhook. The secret is issued by the operator if it
+	// has not been provided, and changes to it trigger a reconcile
 	webhookSecret := &corev1.Secret{}
 	err = r.Client.Get(ctx, types.NamespacedName{Name: kubeovniov1.KubeOVNOperatorWebhookCertSecret, Namespace: r.Namespace}, webhookSecret)
-	if err != nil {
+	if err != nil && !apierrors.IsNotFound(err) {
 		return fmt.Errorf("error fetching secret %s: %w", kubeovniov1.KubeOVNOperatorWebhookCertSecret, err)
 	}
 	caCert, ok := webhookSecret.Data["ca.crt"]
 	if !ok {
-		return fmt.Errorf("no key found for ca.crt in secret %v", kubeovniov1.KubeOVNOperatorWebhookCertSecret)
+		r.Log.WithValues("name", config.Name).Info("waiting for webhook certificate to be issued", "secret", kubeovniov1.KubeOVNOperatorWebhookCertSecret)
+		config.Status.PhaseMessage = fmt.Sprintf("waiting for ca.crt in secret %s", kubeovniov1.KubeOVNOperatorWebhookCertSecret)
+		return nil
 	}
-	for objectType, objectList := range templates.OrderedObjectList {
-		r.Log.WithValues("objectType", objectType).Info("processing object type")
-		// chceck if objectType is a clusterscoped object so we can defined correct ownership
-		namespaced, err := apiutil.IsObjectNamespaced(objectType, r.Scheme, r.Client.RESTMapper())
-		if err != nil {
-			return fmt.Errorf("unable to identify if objecttype %s is namedspaced: %v", objectType.GetObjectKind(), err)
+
+	if err := r.seedCurrentVersion(ctx, config); err != nil {
+		return err
+	}
+	version, upgradeErr := r.resolveVersion(config)
+	if config.Spec.ApplyMode == kubeovniov1.ApplyModePlan {
+		return r.planPhases(ctx, config, fakeNSObj, version, string(caCert))
+	}
+	if config.Spec.RollbackTo != nil {
+		return r.rollbackToRevision(ctx, config, *config.Spec.RollbackTo)
+	}
+	config.Status.RolledBackRevision = 0
+	if config.ConditionTrue(kubeovniov1.RolledBackCondition) {
+		config.SetCondition(kubeovniov1.RolledBackCondition, metav1.ConditionFalse, "rollback has been cleared, applying objects rendered from spec", kubeovniov1.RollbackClearedReason)
+	}
+	r.updateUpgradeStatus(config, version, upgradeErr)
+	if config.ConditionTrue(kubeovniov1.PlannedCondition) {
+		config.SetCondition(kubeovniov1.PlannedCondition, metav1.ConditionFalse, "plan mode is disabled", kubeovniov1.PlanDisabledReason)
+	}
+	config.Status.Plan = nil
+
+	tlsSecret, tlsChecksum, err := r.ovnTLSSecret(ctx, config, false)
+	if err != nil {
+		return err
+	}
+
+	var managedObjects []kubeovniov1.ObjectReference
+	var renderedObjs []client.Object
+	for _, phase := range templates.OrderedPhases {
+		r.Log.WithValues("phase", phase.Name).Info("processing phase")
+		config.Status.Phase = phase.Name
+		objs, err := r.renderPhase(config, phase, fakeNSObj, version, string(caCert), tlsSecret, tlsChecksum)
+		if errors.Is(err, errCertManagerNotInstalled) {
+			r.Log.WithValues("phase", phase.Name).Info("waiting for cert-manager crds to be installed", "reason", err.Error())
+			waitForRollout(config, err.Error())
+			config.Status.ManagedObjects = mergeObjectReferences(config.Status.ManagedObjects, managedObjects)
+			return nil
 		}
-		objs, err := render.GenerateObjects(objectList, config, objectType, r.RestConfig, r.Version, string(caCert))
 		if err != nil {
-			return fmt.Errorf("error during object generation for type %s: %v", objectType.GetObjectKind().GroupVersionKind(), err)
+			return err
 		}
+		var appliedObjs []*unstructured.Unstructured
 		for _, obj := range objs {
-			var ownerObj client.Object
-			if namespaced {
-				ownerObj = config
-			} else {
-				ownerObj = fakeNSObj
+			appliedObj, err := r.reconcileObject(ctx, obj)
+			if err != nil {
+				return fmt.Errorf("error reconcilling object %s/%s: %v", obj.GetNamespace(), obj.GetName(), err)
 			}
-			err = controllerutil.SetControllerReference(ownerObj, obj, r.Scheme)
+			objRef, err := generateObjectReference(obj, appliedObj)
 			if err != nil {
-				return fmt.Errorf("error setting controller reference on object %s/%s: %v", obj.GetNamespace(), obj.GetName(), err)
+				return fmt.Errorf("error generating object reference for %s/%s: %v", obj.GetNamespace(), obj.GetName(), err)
 			}
+			managedObjects = append(managedObjects, objRef)
+			appliedObjs = append(appliedObjs, appliedObj)
+			renderedObjs = append(renderedObjs, obj)
+		}
 
-			if err = r.reconcileObject(ctx, obj); err != nil {
-				return fmt.Errorf("error reconcilling object %s/%s: %v", obj.GetNamespace(), obj.GetName(), err)
-			}
+		// next phase is only applied once all objects in the current phase are healthy
+		ready, message, err := r.phaseReady(ctx, phase, appliedObjs)
+		if err != nil {
+			return fmt.Errorf("error checking health of phase %s: %v", phase.Name, err)
+		}
+		if !ready {
+			r.Log.WithValues("phase", phase.Name).Info("waiting for phase to become healthy", "reason", message)
+			waitForRollout(config, message)
+			// objects from later phases have not been applied yet, and are retained until the rollout completes
+			config.Status.ManagedObjects = mergeObjectReferences(config.Status.ManagedObjects, managedObjects)
+			return nil
 		}
 	}
+	sortObjectReferences(managedObjects)
+
+	// objects which were previously applied, but are no longer rendered need to be pruned
+	previousObjects := slices.Concat(config.Status.ManagedObjects, config.Status.OrphanedObjects)
+	if err := r.pruneObjects(ctx, config, findOrphanedObjects(previousObjects, managedObjects)); err != nil {
+		return err
+	}
+	config.Status.ManagedObjects = managedObjects
+	config.Status.Phase = templates.PhaseComplete
+	config.Status.PhaseMessage = ""
 	config.Status.Status = kubeovniov1.ConfigurationStatusDeployed
-	return nil
+	config.Status.Images = render.ApplyAirgapImages(render.ResolveImages(config, version), config)
+	r.completeUpgrade(config, version)
+	return r.recordRevision(ctx, config, renderedObjs, version)
 }
 
-// reconcileObject will mimic kubectl apply to apply objects
-func (r *ConfigurationReconciler) reconcileObject(ctx context.Context, obj client.Object) error {
+// errCertManagerNotInstalled is returned when cert-manager integration is enabled, but the apiserver does not
+// serve the cert-manager kinds
+var errCertManagerNotInstalled = errors.New("cert-manager crds are not installed")
+
+// renderPhase renders all objects in a phase. The kube-ovn-tls secret issued by the operator is applied with the
+// base resources, and workloads using it are annotated with its checksum
+func (r *ConfigurationReconciler) renderPhase(config *kubeovniov1.Configuration, phase templates.Phase, fakeNSObj *corev1.Namespace, version string, caCert string, tlsSecret *corev1.Secret, tlsChecksum string) ([]client.Object, error) {
+	var phaseObjs []client.Object
+	for _, objectTemplates := range phase.Objects {
+		objs, err := r.renderObjects(config, objectTemplates, fakeNSObj, version, caCert)
+		if err != nil {
+			return nil, err
+		}
+		phaseObjs = append(phaseObjs, render.ApplyTLSChecksum(objs, tlsChecksum)...)
+	}
+	if phase.Name == templates.PhaseBaseResources && tlsSecret != nil {
+		objs, err := postProcessObjects(config, []client.Object{tlsSecret})
+		if err != nil {
+			return nil, err
+		}
+		phaseObjs = append(phaseObjs, objs...)
+	}
+	return phaseObjs, nil
+}
+
+// postProcessObjects applies user defined overrides, airgap rewrites and cert-manager changes to rendered objects
+func postProcessObjects(config *kubeovniov1.Configuration, objs []client.Object) ([]client.Object, error) {
+	// apply user defined overrides before objects are applied, as server side apply
+	// would otherwise revert any manual changes to managed objects
+	objs, err := render.ApplyOverrides(objs, config.Spec.Overrides)
+	if err != nil {
+		return nil, fmt.Errorf("error applying overrides: %v", err)
+	}
+	// airgap rewrites run last so images introduced by overrides are also moved to the local registry
+	objs = render.ApplyAirgap(objs, config)
+	return render.ApplyCertManager(objs, config), nil
+}
+
+// renderObjects renders the templates for a specific object type, applies user defined overrides and
+// sets the controller reference for the rendered objects
+func (r *ConfigurationReconciler) renderObjects(config *kubeovniov1.Configuration, objectTemplates templates.ObjectTemplates, fakeNSObj *corev1.Namespace, version string, caCert string) ([]client.Object, error) {
+	objectType := objectTemplates.ObjectType
+	// unstructured objects are cert-manager objects, which are only rendered when the integration is enabled
+	_, isUnstructured := objectType.(*unstructured.Unstructured)
+	if isUnstructured && !config.Spec.CertManager.Enabled {
+		return nil, nil
+	}
+	objs, err := render.GenerateObjects(objectTemplates.Templates, config, objectType, r.RestConfig, version, caCert)
+	if err != nil {
+		return nil, fmt.Errorf("error during object generation for type %s: %v", objectType.GetObjectKind().GroupVersionKind(), err)
+	}
+	objs, err = postProcessObjects(config, objs)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objs {
+		// check if the object is clusterscoped so we can define correct ownership. this is checked for each
+		// rendered object, as unstructured objects only have a kind once rendered
+		namespaced, err := apiutil.IsObjectNamespaced(obj, r.Scheme, r.Client.RESTMapper())
+		if err != nil {
+			if isUnstructured && meta.IsNoMatchError(err) {
+				return nil, fmt.Errorf("%w: %v", errCertManagerNotInstalled, err)
+			}
+			return nil, fmt.Errorf("unable to identify if object %s %s is namespaced: %v", obj.GetObjectKind().GroupVersionKind(), obj.GetName(), err)
+		}
+		var ownerObj client.Object
+		if namespaced {
+			ownerObj = config
+		} else {
+			ownerObj = fakeNSObj
+		}
+		err = controllerutil.SetControllerReference(ownerObj, obj, r.Scheme)
+		if err != nil {
+			return nil, fmt.Errorf("error setting controller reference on object %s/%s: %v", obj.GetNamespace(), obj.GetName(), err)
+		}
+	}
+	return objs, nil
+}
+
+// reconcileObject will mimic kubectl apply to apply objects, and returns the object
+// as returned by the apiserver. Objects annotated with kubeovn.io/unmanaged=true are not applied
+// and the existing object is returned as is
+func (r *ConfigurationReconciler) reconcileObject(ctx context.Context, obj client.Object) (*unstructured.Unstructured, error) {
 	var err error
 	unstructuredObj := &unstructured.Unstructured{}
 	unstructuredObj.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
 	if err != nil {
-		return fmt.Errorf("error convering new object %s to unstructured object %v", obj.GetName(), err)
+		return nil, fmt.Errorf("error convering new object %s to unstructured object %v", obj.GetName(), err)
+	}
+
+	existingObj, err := r.fetchExistingObject(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if existingObj != nil && isUnmanaged(existingObj) {
+		r.Log.WithValues("namespace", obj.GetNamespace(), "name", obj.GetName()).Info("object is marked as unmanaged, skipping apply")
+		// objects read from the cache have no type meta, which is needed to check their readiness
+		unstructuredExisting := &unstructured.Unstructured{}
+		unstructuredExisting.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(existingObj)
+		if err != nil {
+			return nil, fmt.Errorf("error converting existing object %s to unstructured object %v", obj.GetName(), err)
+		}
+		gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+		if err != nil {
+			return nil, fmt.Errorf("error looking up kind of object %s: %v", obj.GetName(), err)
+		}
+		unstructuredExisting.SetGroupVersionKind(gvk)
+		return unstructuredExisting, nil
+	}
+	return unstructuredObj, r.Patch(ctx, unstructuredObj, client.Apply, client.ForceOwnership, client.FieldOwner("kubeovn-operator"))
+}
+
+// fetchExistingObject returns the current state of the object, or nil if it does not exist yet. Typed objects are
+// read from the cache, as all rendered types are watched. The cache is limited to the operator namespace, so objects
+// rendered into other namespaces, and kinds which are not registered in the scheme, are read from the apiserver
+// as unstructured objects
+func (r *ConfigurationReconciler) fetchExistingObject(ctx context.Context, obj client.Object) (client.Object, error) {
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return nil, fmt.Errorf("error looking up kind of object %s: %v", obj.GetName(), err)
+	}
+	var existingObj client.Object
+	if typedObj, err := r.Scheme.New(gvk); err == nil {
+		existingObj, _ = typedObj.(client.Object)
+	}
+	outsideCache := obj.GetNamespace() != "" && obj.GetNamespace() != r.Namespace
+	if _, ok := obj.(*unstructured.Unstructured); ok || existingObj == nil || outsideCache {
+		unstructuredObj := &unstructured.Unstructured{}
+		unstructuredObj.SetGroupVersionKind(gvk)
+		existingObj = unstructuredObj
 	}
-	return r.Patch(ctx, unstructuredObj, client.Apply, client.ForceOwnership, client.FieldOwner("kubeovn-operator"))
+	err = r.Get(ctx, client.ObjectKeyFromObject(obj), existingObj)
+	if err != nil {
+		// kind may not be registered yet if the associated crd has not been applied
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error fetching existing object %s: %v", obj.GetName(), err)
+	}
+	return existingObj, nil
+}
+
+// isUnmanaged returns true if the object has been annotated by a user to stop the operator from applying changes
+func isUnmanaged(obj client.Object) bool {
+	return obj.GetAnnotations()[kubeovniov1.UnmanagedAnnotation] == "true"
 }
 
 // filterObject returns the configuration object if object is owned by the configuratino controller
@@ -229,6 +453,15 @@ func (r *ConfigurationReconciler) filterObject(ctx context.Context, obj client.O
 	return result
 }
 
+// filterSecret maps owned secrets to their configuration. The webhook certificate is not owned by a configuration,
+// but its ca is rendered into the kube-ovn-webhook configuration, so changes are mapped to the default configuration
+func (r *ConfigurationReconciler) filterSecret(ctx context.Context, obj client.Object) []ctrl.Request {
+	if obj.GetName() == kubeovniov1.KubeOVNOperatorWebhookCertSecret && obj.GetNamespace() == r.Namespace {
+		return []ctrl.Request{{NamespacedName: types.NamespacedName{Name: kubeovniov1.DefaultConfigurationName, Namespace: r.Namespace}}}
+	}
+	return r.filterObject(ctx, obj)
+}
+
 // AddWatches adds watches for all objects types being managed by the controller to ensure any changes
 // to managed objects results in reconcile of configuration object
 func (r *ConfigurationReconciler) AddWatches(b *builder.Builder) *builder.Builder {
@@ -256,12 +489,34 @@ func (r *ConfigurationReconciler) AddWatches(b *builder.Builder) *builder.Builde
 			return true
 		},
 	}
-	for key := range templates.OrderedObjectList {
+	for _, key := range templates.ObjectTypes() {
 		b.Watches(key, handler.EnqueueRequestsFromMapFunc(r.filterObject), builder.WithPredicates(updatePred))
 	}
+	// secrets are issued by the operator rather than rendered from templates
+	b.Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.filterSecret), builder.WithPredicates(updatePred))
+	// master nodes are rendered into ovn-central, so changes to node labels and addresses need to be picked up
+	b.Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.filterNode), builder.WithPredicates(nodePred))
 	return b
 }
 
+// nodePred ignores node updates which do not change the labels or internal address of a node, as nodes are
+// updated frequently by the kubelet
+var nodePred = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, oldOK := e.ObjectOld.(*corev1.Node)
+		newNode, newOK := e.ObjectNew.(*corev1.Node)
+		if !oldOK || !newOK {
+			return true
+		}
+		return nodeInternalIP(*oldNode) != nodeInternalIP(*newNode) || !reflect.DeepEqual(oldNode.Labels, newNode.Labels)
+	},
+}
+
+// filterNode maps node changes to the default configuration, which is the only configuration rendering master nodes
+func (r *ConfigurationReconciler) filterNode(ctx context.Context, obj client.Object) []ctrl.Request {
+	return []ctrl.Request{{NamespacedName: types.NamespacedName{Name: kubeovniov1.DefaultConfigurationName, Namespace: r.Namespace}}}
+}
+
 // findMasterNodes will find nodes matching the master label criteria in the configuration
 func (r *ConfigurationReconciler) findMasterNodes(ctx context.Context, config *kubeovniov1.Configuration) error {
 
@@ -277,10 +532,12 @@ func (r *ConfigurationReconciler) findMasterNodes(ctx context.Context, config *k
 	}
 
 	var nodeAddresses []string
+	var nodes []kubeovniov1.MatchingNode
 	for _, v := range nodeList.Items {
 		address := nodeInternalIP(v)
 		if len(address) > 0 {
 			nodeAddresses = append(nodeAddresses, address)
+			nodes = append(nodes, kubeovniov1.MatchingNode{Name: v.Name, Address: address})
 		}
 	}
 
@@ -293,10 +550,25 @@ func (r *ConfigurationReconciler) findMasterNodes(ctx context.Context, config *k
 		return nil
 	}
 
+	// a master node keeping its name with a new address leaves its previous address as a raft member, so the
+	// rendered addresses are only updated once the previous addresses have been removed from the clusters
+	config.Status.AddressChanges = ovsdb.MergeAddressChanges(config.Status.MatchingNodes, nodes, config.Status.AddressChanges, metav1.Now())
+	if len(config.Status.AddressChanges) != 0 {
+		// raft membership is not changed during manual intervention or while databases are being restored
+		if config.Spec.Paused {
+			return nil
+		}
+		r.reconcileAddressChanges(ctx, config, nodes)
+		if len(config.Status.AddressChanges) != 0 {
+			return nil
+		}
+	}
+
 	if !addressArrayEqual(nodeAddresses, config.Status.MatchingNodeAddresses) {
 		config.SetCondition(kubeovniov1.WaitingForMatchignNodesCondition, metav1.ConditionFalse, fmt.Sprintf("found nodes %s", strings.Join(nodeAddresses, ",")), kubeovniov1.NodesFoundReason)
 		config.Status.MatchingNodeAddresses = nodeAddresses
 	}
+	config.Status.MatchingNodes = nodes
 	return nil
 }
 
@@ -412,6 +684,141 @@ func nodeInternalIP(node corev1.Node) string {
 	return ""
 }
 
+// pruneObjects deletes orphaned objects owned by the configuration or reports them in status
+// when the configuration prune mode is ReportOnly
+func (r *ConfigurationReconciler) pruneObjects(ctx context.Context, config *kubeovniov1.Configuration, orphans []kubeovniov1.ObjectReference) error {
+	var remaining []kubeovniov1.ObjectReference
+	for _, ref := range orphans {
+		// deleting a crd will remove all associated objects, which needs to be an explicit user action
+		if ref.GVK.Group == apiextensionsv1.GroupName && ref.GVK.Kind == "CustomResourceDefinition" {
+			r.Log.WithValues("name", ref.Name).Info("skipping prune of custom resource definition")
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: ref.GVK.Group, Version: ref.GVK.Version, Kind: ref.GVK.Kind})
+		err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, obj)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("error fetching orphaned object %s %s/%s: %v", ref.GVK.Kind, ref.Namespace, ref.Name, err)
+		}
+
+		// the kube-ovn-tls secret is taken over by cert-manager when the integration is enabled
+		if _, ok := obj.GetAnnotations()[kubeovniov1.CertManagerCertificateAnnotation]; ok {
+			r.Log.WithValues("kind", ref.GVK.Kind, "namespace", ref.Namespace, "name", ref.Name).Info("skipping prune of object managed by cert-manager")
+			continue
+		}
+
+		if !r.ownedByConfiguration(config, obj) {
+			r.Log.WithValues("kind", ref.GVK.Kind, "namespace", ref.Namespace, "name", ref.Name).Info("skipping prune of object not owned by configuration")
+			continue
+		}
+
+		if config.Spec.PruneMode == kubeovniov1.PruneModeReportOnly {
+			r.EventRecorder.Event(config, corev1.EventTypeWarning, "OrphanedObject",
+				fmt.Sprintf("%s %s/%s is no longer rendered and needs to be removed", ref.GVK.Kind, ref.Namespace, ref.Name))
+			remaining = append(remaining, ref)
+			continue
+		}
+
+		r.Log.WithValues("kind", ref.GVK.Kind, "namespace", ref.Namespace, "name", ref.Name).Info("pruning orphaned object")
+		if err := r.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error pruning orphaned object %s %s/%s: %v", ref.GVK.Kind, ref.Namespace, ref.Name, err)
+		}
+		r.EventRecorder.Event(config, corev1.EventTypeNormal, "PrunedObject",
+			fmt.Sprintf("%s %s/%s is no longer rendered and has been deleted", ref.GVK.Kind, ref.Namespace, ref.Name))
+	}
+	sortObjectReferences(remaining)
+	config.Status.OrphanedObjects = remaining
+	return nil
+}
+
+// ownedByConfiguration checks if the object is controlled by the configuration or the fake namespace
+// used as owner of cluster scoped objects
+func (r *ConfigurationReconciler) ownedByConfiguration(config *kubeovniov1.Configuration, obj client.Object) bool {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil {
+		return false
+	}
+	if owner.Kind == kubeovniov1.Kind && owner.UID == config.GetUID() {
+		return true
+	}
+	return owner.Kind == "Namespace" && owner.Name == kubeovniov1.KubeOVNFakeNamespace
+}
+
+// mergeObjectReferences replaces references in previous with those in current, retaining any
+// previous references which are not present in current
+func mergeObjectReferences(previous, current []kubeovniov1.ObjectReference) []kubeovniov1.ObjectReference {
+	merged := slices.Clone(current)
+	merged = append(merged, findOrphanedObjects(previous, current)...)
+	sortObjectReferences(merged)
+	return merged
+}
+
+// findOrphanedObjects returns references present in previous which are not present in current
+func findOrphanedObjects(previous, current []kubeovniov1.ObjectReference) []kubeovniov1.ObjectReference {
+	currentKeys := make(map[string]bool, len(current))
+	for _, ref := range current {
+		currentKeys[objectReferenceKey(ref)] = true
+	}
+
+	var orphans []kubeovniov1.ObjectReference
+	for _, ref := range previous {
+		key := objectReferenceKey(ref)
+		if currentKeys[key] {
+			continue
+		}
+		// avoid duplicates when an object exists in both managed and orphaned lists
+		currentKeys[key] = true
+		orphans = append(orphans, ref)
+	}
+	return orphans
+}
+
+// generateObjectReference records the rendered object and the resource version it was applied at
+func generateObjectReference(renderedObj client.Object, appliedObj *unstructured.Unstructured) (kubeovniov1.ObjectReference, error) {
+	hash, err := objectHash(renderedObj)
+	if err != nil {
+		return kubeovniov1.ObjectReference{}, err
+	}
+	gvk := appliedObj.GroupVersionKind()
+	return kubeovniov1.ObjectReference{
+		GVK: kubeovniov1.GroupVersionKind{
+			Group:   gvk.Group,
+			Version: gvk.Version,
+			Kind:    gvk.Kind,
+		},
+		Namespace:       appliedObj.GetNamespace(),
+		Name:            appliedObj.GetName(),
+		ResourceVersion: appliedObj.GetResourceVersion(),
+		Hash:            hash,
+		Unmanaged:       isUnmanaged(appliedObj),
+	}, nil
+}
+
+// objectHash returns a sha256 hash of the json representation of an object
+func objectHash(obj any) (string, error) {
+	content, err := json.Marshal(obj)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling object for hashing: %v", err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(content)), nil
+}
+
+// sortObjectReferences sorts references to ensure status is stable across reconciles
+// as objects are rendered in a random order
+func sortObjectReferences(refs []kubeovniov1.ObjectReference) {
+	slices.SortFunc(refs, func(a, b kubeovniov1.ObjectReference) int {
+		return strings.Compare(objectReferenceKey(a), objectReferenceKey(b))
+	})
+}
+
+func objectReferenceKey(ref kubeovniov1.ObjectReference) string {
+	return strings.Join([]string{ref.GVK.Group, ref.GVK.Version, ref.GVK.Kind, ref.Namespace, ref.Name}, "/")
+}
+
 func addressArrayEqual(existing []string, discovered []string) bool {
 	slices.Sort(existing)
 	slices.Sort(discovered)
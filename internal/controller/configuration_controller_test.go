@@ -30,11 +30,14 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	resourceapi "k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/yaml"
 
 	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/templates"
 )
 
 var (
@@ -45,6 +48,7 @@ var (
 const (
 	newVersion            = "v1.14.1"
 	kubeOVNControllerName = "kube-ovn-controller"
+	ovnICControllerName   = "ovn-ic-controller"
 )
 
 var _ = Describe("Configuration Controller", func() {
@@ -128,18 +132,54 @@ var _ = Describe("Configuration Controller", func() {
 					if resource.Status.Status != kubeovniov1.ConfigurationStatusDeployed {
 						return fmt.Errorf("expected to find configuration status to be %s but got %s", kubeovniov1.ConfigurationStatusDeployed, resource.Status.Status)
 					}
+					if resource.Status.Phase != templates.PhaseComplete {
+						return fmt.Errorf("expected to find phase to be %s but got %s", templates.PhaseComplete, resource.Status.Phase)
+					}
 					return nil
+				}, "300s", "5s").Should(BeNil())
+			})
+
+			It("checking managed objects have been recorded in status", func() {
+				Eventually(func() error {
+					resource := &kubeovniov1.Configuration{}
+					err := k8sClient.Get(ctx, typedConfig, resource)
+					if err != nil {
+						return err
+					}
+					for _, v := range resource.Status.ManagedObjects {
+						if v.GVK.Kind == "Deployment" && v.Name == kubeOVNControllerName {
+							if v.ResourceVersion == "" || v.Hash == "" {
+								return fmt.Errorf("expected resourceVersion and hash to be recorded for %s", kubeOVNControllerName)
+							}
+							return nil
+						}
+					}
+					return fmt.Errorf("expected to find %s in managed objects", kubeOVNControllerName)
+				}, "30s", "5s").Should(BeNil())
+			})
+
+			It("checking a revision of rendered objects has been recorded", func() {
+				Eventually(func() error {
+					resource := &kubeovniov1.Configuration{}
+					err := k8sClient.Get(ctx, typedConfig, resource)
+					if err != nil {
+						return err
+					}
+					if resource.Status.CurrentRevision == 0 {
+						return fmt.Errorf("expected current revision to be recorded in status")
+					}
+					revision := &appsv1.ControllerRevision{}
+					return k8sClient.Get(ctx, types.NamespacedName{Name: fmt.Sprintf("%s-%d", resource.Name, resource.Status.CurrentRevision), Namespace: resource.Namespace}, revision)
 				}, "30s", "5s").Should(BeNil())
 			})
 
 			// trigger upgrade
 			It("Patch Version to simulate an upgrade", func() {
-				cr.Version = newVersion
-				// patching is immaterial and is only needed to trigger reconcile of the object
 				resource := &kubeovniov1.Configuration{}
 				err := k8sClient.Get(ctx, typedConfig, resource)
 				Expect(err).ToNot(HaveOccurred())
-				resource.Spec.Global.Images.KubeOVNImage.Tag = newVersion
+				Expect(resource.Status.CurrentVersion).To(Equal(cr.Version))
+				resource.Spec.Version = newVersion
 				err = k8sClient.Update(ctx, resource)
 				Expect(err).ToNot(HaveOccurred())
 			})
@@ -160,8 +200,22 @@ var _ = Describe("Configuration Controller", func() {
 						}
 					}
 					return nil
-				}, "30s", "5s").Should(BeNil())
+				}, "300s", "5s").Should(BeNil())
+			})
+			It("checking current version has been updated once the upgrade completes", func() {
+				Eventually(func() error {
+					resource := &kubeovniov1.Configuration{}
+					err := k8sClient.Get(ctx, typedConfig, resource)
+					if err != nil {
+						return err
+					}
+					if resource.Status.CurrentVersion != newVersion || resource.ConditionTrue(kubeovniov1.UpgradingCondition) {
+						return fmt.Errorf("waiting for upgrade to %s to complete, current version %s", newVersion, resource.Status.CurrentVersion)
+					}
+					return nil
+				}, "300s", "5s").Should(BeNil())
 			})
+
 			// validate node finalizers exist
 			It("checking node finalizers exist", func() {
 				Eventually(func() error {
@@ -185,6 +239,141 @@ var _ = Describe("Configuration Controller", func() {
 				}, "120s", "5s").ShouldNot(HaveOccurred())
 			})
 
+			It("pause configuration", func() {
+				resource := &kubeovniov1.Configuration{}
+				err := k8sClient.Get(ctx, typedConfig, resource)
+				Expect(err).ToNot(HaveOccurred())
+				resource.Spec.Paused = true
+				err = k8sClient.Update(ctx, resource)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("checking paused condition has been set", func() {
+				Eventually(func() error {
+					resource := &kubeovniov1.Configuration{}
+					err := k8sClient.Get(ctx, typedConfig, resource)
+					if err != nil {
+						return err
+					}
+					if !resource.ConditionTrue(kubeovniov1.PausedCondition) {
+						return fmt.Errorf("expected condition %s to be true", kubeovniov1.PausedCondition)
+					}
+					return nil
+				}, "30s", "5s").Should(BeNil())
+			})
+
+			It("resume configuration", func() {
+				resource := &kubeovniov1.Configuration{}
+				err := k8sClient.Get(ctx, typedConfig, resource)
+				Expect(err).ToNot(HaveOccurred())
+				resource.Spec.Paused = false
+				err = k8sClient.Update(ctx, resource)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(func() error {
+					err := k8sClient.Get(ctx, typedConfig, resource)
+					if err != nil {
+						return err
+					}
+					if !resource.ConditionFalse(kubeovniov1.PausedCondition) {
+						return fmt.Errorf("expected condition %s to be false", kubeovniov1.PausedCondition)
+					}
+					return nil
+				}, "30s", "5s").Should(BeNil())
+			})
+
+			It("plan a change to kube-ovn-controller resources", func() {
+				resource := &kubeovniov1.Configuration{}
+				err := k8sClient.Get(ctx, typedConfig, resource)
+				Expect(err).ToNot(HaveOccurred())
+				resource.Spec.ApplyMode = kubeovniov1.ApplyModePlan
+				resource.Spec.KubeOVNController.Limits.CPU = resourceapi.MustParse("1500m")
+				err = k8sClient.Update(ctx, resource)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("checking plan has been recorded without changing kube-ovn-controller", func() {
+				Eventually(func() error {
+					resource := &kubeovniov1.Configuration{}
+					err := k8sClient.Get(ctx, typedConfig, resource)
+					if err != nil {
+						return err
+					}
+					for _, v := range resource.Status.Plan {
+						if v.GVK.Kind != "Deployment" || v.Name != kubeOVNControllerName {
+							continue
+						}
+						if v.Action != kubeovniov1.PlanActionChange || len(v.ChangedFields) == 0 {
+							return fmt.Errorf("expected %s to be planned as changed, got %s", kubeOVNControllerName, v.Action)
+						}
+						d := &appsv1.Deployment{}
+						err = k8sClient.Get(ctx, types.NamespacedName{Name: kubeOVNControllerName, Namespace: defaultKubeovnNamespace}, d)
+						if err != nil {
+							return err
+						}
+						if d.Spec.Template.Spec.Containers[0].Resources.Limits.Cpu().Equal(resourceapi.MustParse("1500m")) {
+							return fmt.Errorf("expected %s to not be modified in plan mode", kubeOVNControllerName)
+						}
+						return nil
+					}
+					return fmt.Errorf("expected to find %s in plan", kubeOVNControllerName)
+				}, "30s", "5s").Should(BeNil())
+			})
+
+			It("apply planned changes", func() {
+				resource := &kubeovniov1.Configuration{}
+				err := k8sClient.Get(ctx, typedConfig, resource)
+				Expect(err).ToNot(HaveOccurred())
+				resource.Spec.ApplyMode = kubeovniov1.ApplyModeApply
+				err = k8sClient.Update(ctx, resource)
+				Expect(err).ToNot(HaveOccurred())
+				Eventually(func() error {
+					err := k8sClient.Get(ctx, typedConfig, resource)
+					if err != nil {
+						return err
+					}
+					if len(resource.Status.Plan) != 0 {
+						return fmt.Errorf("expected plan to be cleared from status")
+					}
+					return nil
+				}, "30s", "5s").Should(BeNil())
+			})
+
+			It("enable ovn-ic-controller", func() {
+				resource := &kubeovniov1.Configuration{}
+				err := k8sClient.Get(ctx, typedConfig, resource)
+				Expect(err).ToNot(HaveOccurred())
+				resource.Spec.Component.EnableIC = ptr.To(true)
+				err = k8sClient.Update(ctx, resource)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("checking ovn-ic-controller deployment has been created", func() {
+				Eventually(func() error {
+					d := &appsv1.Deployment{}
+					return k8sClient.Get(ctx, types.NamespacedName{Name: ovnICControllerName, Namespace: defaultKubeovnNamespace}, d)
+				}, "30s", "5s").Should(BeNil())
+			})
+
+			It("disable ovn-ic-controller", func() {
+				resource := &kubeovniov1.Configuration{}
+				err := k8sClient.Get(ctx, typedConfig, resource)
+				Expect(err).ToNot(HaveOccurred())
+				resource.Spec.Component.EnableIC = ptr.To(false)
+				err = k8sClient.Update(ctx, resource)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("checking ovn-ic-controller deployment has been pruned", func() {
+				Eventually(func() error {
+					d := &appsv1.Deployment{}
+					err := k8sClient.Get(ctx, types.NamespacedName{Name: ovnICControllerName, Namespace: defaultKubeovnNamespace}, d)
+					if apierrors.IsNotFound(err) || (err == nil && !d.DeletionTimestamp.IsZero()) {
+						return nil
+					}
+					return fmt.Errorf("waiting for %s to be pruned", ovnICControllerName)
+				}, "30s", "5s").Should(BeNil())
+			})
+
 			It("Cleanup the specific resource instance Configuration", func() {
 				resource := &kubeovniov1.Configuration{}
 				err := k8sClient.Get(ctx, typedConfig, resource)
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -16,6 +18,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/executor"
+	"github.com/harvester/kubeovn-operator/internal/ovsdb"
 )
 
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
@@ -24,6 +28,7 @@ import (
 type HealthCheckReconciler struct {
 	client.Client
 	RestConfig          *rest.Config
+	CommandRunner       executor.CommandRunner
 	Scheme              *runtime.Scheme
 	EventRecorder       record.EventRecorder
 	Namespace           string
@@ -53,13 +58,20 @@ func (r *HealthCheckReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	config := configObj.DeepCopy()
 
+	if !isDefaultConfiguration(config, r.Namespace) {
+		r.Log.WithValues("name", configObj.Name).Info("ignoring configuration, only the default configuration is health checked")
+		return ctrl.Result{}, nil
+	}
+
 	// deletion timestamp is set on object, no need for run further checks
 	if !config.DeletionTimestamp.IsZero() {
 		r.Log.WithValues("name", configObj.Name).Info("configuration being deleted, no further healthchecks needed")
 		return ctrl.Result{}, nil
 	}
 
-	if config.Status.Status != kubeovniov1.ConfigurationStatusDeployed {
+	// healthchecks keep running while later rollouts are in progress, as a broken raft member stops the
+	// ovn-central phase from becoming ready, and raft recovery needs the database status to repair it
+	if !installed(config) {
 		r.Log.WithValues("name", configObj.Name).Info("waiting for resources to be deployed")
 		return ctrl.Result{}, nil
 	}
@@ -68,12 +80,25 @@ func (r *HealthCheckReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, fmt.Errorf("error during execution of reconcileOVNDBHealth: %v", err)
 	}
 
-	// healthcheck only updates conditions. since object is also reconciled by another controller we ignore the rest
-	if !reflect.DeepEqual(config.Status.Conditions, configObj.Status.Conditions) {
+	if err := r.reconcileCompaction(ctx, config); err != nil {
+		return ctrl.Result{}, fmt.Errorf("error during execution of reconcileCompaction: %v", err)
+	}
+
+	recovering, err := r.reconcileRaftRecovery(ctx, config)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("error during execution of reconcileRaftRecovery: %v", err)
+	}
+
+	// healthcheck only updates conditions, database, chassis and raft recovery status, and removes the maintenance
+	// annotations. since object is also reconciled by another controller we ignore the rest
+	if !reflect.DeepEqual(config.Status, configObj.Status) {
 		if err := r.Client.Status().Patch(ctx, config, client.MergeFrom(configObj)); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
+	if recovering {
+		return ctrl.Result{RequeueAfter: raftRecoveryRequeueInterval}, nil
+	}
 	return ctrl.Result{RequeueAfter: time.Duration(r.HealthCheckInterval) * time.Second}, nil
 }
 
@@ -93,8 +118,10 @@ func (r *HealthCheckReconciler) reconcileOVNDBHealth(ctx context.Context, config
 		return fmt.Errorf("error fetching northbound leader: %v", err)
 	}
 
+	// the status reported by a previous leader is cleared, as raft recovery and compaction act on it
 	if len(nbPods.Items) == 0 {
 		config.SetCondition(kubeovniov1.OVNNBLeaderFound, metav1.ConditionFalse, "no pods matching northbound leader label requirements found", kubeovniov1.LeaderNotFound)
+		config.Status.OVNDatabases.Northbound = nil
 	} else {
 		runNBCheck = true
 		config.SetCondition(kubeovniov1.OVNNBLeaderFound, metav1.ConditionTrue, fmt.Sprintf("northbound leader found %s", nbPods.Items[0].GetName()), kubeovniov1.LeaderFound)
@@ -108,34 +135,133 @@ func (r *HealthCheckReconciler) reconcileOVNDBHealth(ctx context.Context, config
 
 	if len(sbPods.Items) == 0 {
 		config.SetCondition(kubeovniov1.OVNSBLeaderFound, metav1.ConditionFalse, "no pods matching southbound leader label requirements found", kubeovniov1.LeaderNotFound)
+		config.Status.OVNDatabases.Southbound = nil
 	} else {
 		runSBCheck = true
-		config.SetCondition(kubeovniov1.OVNSBLeaderFound, metav1.ConditionTrue, fmt.Sprintf("northbound leader found %s", sbPods.Items[0].GetName()), kubeovniov1.LeaderFound)
+		config.SetCondition(kubeovniov1.OVNSBLeaderFound, metav1.ConditionTrue, fmt.Sprintf("southbound leader found %s", sbPods.Items[0].GetName()), kubeovniov1.LeaderFound)
 	}
 
 	// run health check on northbound db
 	if runNBCheck {
-		result, err := executeOVNCentralCommand(ctx, kubeovniov1.NBCheckScript, kubeovniov1.SBLeaderLabel, r.Client, r.RestConfig, r.Namespace)
-		if err != nil {
-			r.Log.Error(err, "NBCheck failure", "command output", string(result))
-			config.SetCondition(kubeovniov1.OVNNBDBHealth, metav1.ConditionFalse, string(result), kubeovniov1.DBHealth)
-		} else {
-			config.SetCondition(kubeovniov1.OVNNBDBHealth, metav1.ConditionTrue, string(result), kubeovniov1.DBHealth)
-		}
+		status := r.checkOVNDB(ctx, config, kubeovniov1.NBCheckScript, kubeovniov1.NBLeaderLabel,
+			kubeovniov1.OVNNBDBHealth, kubeovniov1.OVNNBRaftMembers)
+		r.collectDBUsage(ctx, nbMaintenance, status, config.Status.OVNDatabases.Northbound)
+		config.Status.OVNDatabases.Northbound = status
 	}
 
 	if runSBCheck {
-		result, err := executeOVNCentralCommand(ctx, kubeovniov1.SBCheckScript, kubeovniov1.SBLeaderLabel, r.Client, r.RestConfig, r.Namespace)
+		status := r.checkOVNDB(ctx, config, kubeovniov1.SBCheckScript, kubeovniov1.SBLeaderLabel,
+			kubeovniov1.OVNSBDBHealth, kubeovniov1.OVNSBRaftMembers)
+		r.collectDBUsage(ctx, sbMaintenance, status, config.Status.OVNDatabases.Southbound)
+		config.Status.OVNDatabases.Southbound = status
+		if err := r.auditChassis(ctx, config); err != nil {
+			r.Log.Error(err, "chassis audit failure")
+			config.SetCondition(kubeovniov1.ChassisConsistent, metav1.ConditionUnknown, err.Error(), kubeovniov1.ConditionCheckFailed)
+		} else if err := r.collectStaleChassis(ctx, config); err != nil {
+			return fmt.Errorf("error collecting stale chassis: %v", err)
+		}
+	}
+
+	return r.checkNorthd(ctx, config)
+}
+
+// checkNorthd queries the status of ovn-northd in each ovn-central pod. northd runs in active/standby mode
+// using a lock in the southbound db, so exactly one instance is expected to be active
+func (r *HealthCheckReconciler) checkNorthd(ctx context.Context, config *kubeovniov1.Configuration) error {
+	pods, err := podList(ctx, kubeovniov1.OVNCentralLabel, r.Client, r.Namespace)
+	if err != nil {
+		return fmt.Errorf("error fetching ovn-central pods: %v", err)
+	}
+
+	var active, standby, unknown []string
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			unknown = append(unknown, pod.GetName())
+			continue
+		}
+		podExecutor, err := executor.NewRemoteCommandExecutor(ctx, r.RestConfig, &pod)
+		if err != nil {
+			return fmt.Errorf("error generating new remote command executor: %v", err)
+		}
+		result, err := podExecutor.Run(kubeovniov1.OVNCentralContainerName, kubeovniov1.NorthdCheckScript)
+		if err != nil {
+			r.Log.Error(err, "northd status check failure", "pod", pod.GetName(), "command output", string(result))
+			unknown = append(unknown, pod.GetName())
+			continue
+		}
+		status, err := ovsdb.ParseNorthdStatus(result)
 		if err != nil {
-			r.Log.Error(err, "SBCheck failure", "command output", string(result))
-			config.SetCondition(kubeovniov1.OVNSBDBHealth, metav1.ConditionFalse, string(result), kubeovniov1.DBHealth)
-		} else {
-			config.SetCondition(kubeovniov1.OVNSBDBHealth, metav1.ConditionTrue, string(result), kubeovniov1.DBHealth)
+			r.Log.Error(err, "error parsing northd status", "pod", pod.GetName())
+			unknown = append(unknown, pod.GetName())
+			continue
 		}
+		switch status {
+		case kubeovniov1.NorthdStatusActive:
+			active = append(active, pod.GetName())
+		case kubeovniov1.NorthdStatusStandby:
+			standby = append(standby, pod.GetName())
+		default:
+			unknown = append(unknown, pod.GetName())
+		}
+	}
+
+	message := fmt.Sprintf("%d active [%s], %d standby, %d unknown [%s]", len(active), strings.Join(active, ","),
+		len(standby), len(unknown), strings.Join(unknown, ","))
+	if len(active) != 1 {
+		config.SetCondition(kubeovniov1.OVNNorthdHealth, metav1.ConditionFalse, message, kubeovniov1.NorthdDegradedReason)
+	} else {
+		config.SetCondition(kubeovniov1.OVNNorthdHealth, metav1.ConditionTrue, message, kubeovniov1.NorthdHealthyReason)
 	}
 	return nil
 }
 
+// checkOVNDB runs cluster/status on the leader of a database and returns the parsed raft status. The raft members
+// are compared with the master node addresses, and the health and membership conditions are updated
+func (r *HealthCheckReconciler) checkOVNDB(ctx context.Context, config *kubeovniov1.Configuration, script string, label string, healthCondition string, membersCondition string) *kubeovniov1.OVNDatabaseStatus {
+	result, err := executeOVNCentralCommand(ctx, script, label, r.Client, r.CommandRunner, r.Namespace)
+	if err != nil {
+		r.Log.Error(err, "cluster status check failure", "condition", healthCondition, "command output", string(result))
+		config.SetCondition(healthCondition, metav1.ConditionFalse, fmt.Sprintf("error fetching cluster status: %v", err), kubeovniov1.DBHealth)
+		return nil
+	}
+
+	status, err := ovsdb.ParseClusterStatus(result)
+	if err != nil {
+		r.Log.Error(err, "error parsing cluster status", "condition", healthCondition, "command output", string(result))
+		config.SetCondition(healthCondition, metav1.ConditionFalse, fmt.Sprintf("error parsing cluster status: %v", err), kubeovniov1.DBHealth)
+		return nil
+	}
+	ovsdb.CompareMembers(status, config.Status.MatchingNodeAddresses)
+	status.LastUpdateTime = metav1.Now()
+
+	var disconnected []string
+	for _, member := range status.Members {
+		if member.Connection == kubeovniov1.RaftConnectionDisconnected {
+			disconnected = append(disconnected, member.ServerID)
+		}
+	}
+	if len(disconnected) != 0 {
+		config.SetCondition(healthCondition, metav1.ConditionFalse, fmt.Sprintf("role %s term %d, members disconnected from leader: %s",
+			status.Role, status.Term, strings.Join(disconnected, ",")), kubeovniov1.DBHealth)
+	} else {
+		config.SetCondition(healthCondition, metav1.ConditionTrue, fmt.Sprintf("role %s term %d, %d members connected",
+			status.Role, status.Term, len(status.Members)), kubeovniov1.DBHealth)
+	}
+
+	if len(status.MissingAddresses) != 0 || len(status.UnexpectedAddresses) != 0 {
+		config.SetCondition(membersCondition, metav1.ConditionFalse, fmt.Sprintf("missing members: [%s], unexpected members: [%s]",
+			strings.Join(status.MissingAddresses, ","), strings.Join(status.UnexpectedAddresses, ",")), kubeovniov1.RaftMembersMismatchReason)
+	} else {
+		config.SetCondition(membersCondition, metav1.ConditionTrue, "raft members match master node addresses", kubeovniov1.RaftMembersMatchReason)
+	}
+	return status
+}
+
+// installed returns true once all phases have been applied successfully at least once
+func installed(config *kubeovniov1.Configuration) bool {
+	return config.Status.Status == kubeovniov1.ConfigurationStatusDeployed || config.Status.CurrentRevision > 0
+}
+
 // checkNeeded calculates if Healthcheck interval has passed before triggering another health check
 func (r *HealthCheckReconciler) checkNeeded(config *kubeovniov1.Configuration) bool {
 	condition := config.LookupCondition(kubeovniov1.OVNNBLeaderFound)
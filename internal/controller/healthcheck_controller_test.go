@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+func Test_HealthCheckClearsStatusWithoutLeader(t *testing.T) {
+	assert := require.New(t)
+	r := &HealthCheckReconciler{
+		Client:              fake.NewClientBuilder().WithScheme(newTestScheme(t)).Build(),
+		EventRecorder:       record.NewFakeRecorder(100),
+		Namespace:           defaultKubeovnNamespace,
+		Log:                 logr.Discard(),
+		HealthCheckInterval: 60,
+	}
+	config := newTestConfiguration()
+	config.Status.OVNDatabases.Northbound = testDisconnectedStatus(metav1.Now())
+	config.Status.OVNDatabases.Southbound = testDisconnectedStatus(metav1.Now())
+
+	// status reported by a previous leader is not retained once no leader is found
+	assert.NoError(r.reconcileOVNDBHealth(context.TODO(), config))
+	assert.Nil(config.Status.OVNDatabases.Northbound)
+	assert.Nil(config.Status.OVNDatabases.Southbound)
+	assert.True(config.ConditionExists(kubeovniov1.OVNNBLeaderFound))
+	assert.False(config.ConditionTrue(kubeovniov1.OVNNBLeaderFound))
+	assert.False(config.ConditionTrue(kubeovniov1.OVNSBLeaderFound))
+}
+
+func Test_HealthCheckIgnoresConfigurationOutsideNamespace(t *testing.T) {
+	assert := require.New(t)
+	config := newTestConfiguration()
+	config.Namespace = "default"
+	config.Status.Status = kubeovniov1.ConfigurationStatusDeployed
+	config.Status.CurrentRevision = 1
+	r := &HealthCheckReconciler{
+		Client:              fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(config).WithStatusSubresource(config).Build(),
+		EventRecorder:       record.NewFakeRecorder(100),
+		Namespace:           defaultKubeovnNamespace,
+		Log:                 logr.Discard(),
+		HealthCheckInterval: 60,
+	}
+
+	// the controllers only resolve the default configuration in the operator namespace
+	result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(config)})
+	assert.NoError(err)
+	assert.Equal(ctrl.Result{}, result)
+	assert.NoError(r.Get(context.TODO(), client.ObjectKeyFromObject(config), config))
+	assert.False(config.ConditionExists(kubeovniov1.OVNNBLeaderFound))
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var tlsCertificateExpiry = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "kubeovn_operator_tls_certificate_expiry_timestamp_seconds",
+		Help: "Expiry of certificates issued by the built-in ovn pki as a unix timestamp",
+	},
+	[]string{"secret"},
+)
+
+var ovnDatabaseFileSize = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "kubeovn_operator_ovn_database_file_size_bytes",
+		Help: "Size of the ovn database file on the raft leader",
+	},
+	[]string{"database"},
+)
+
+var ovnDatabaseLogLength = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "kubeovn_operator_ovn_database_raft_log_entries",
+		Help: "Number of entries in the raft log of the ovn database leader",
+	},
+	[]string{"database"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(tlsCertificateExpiry, ovnDatabaseFileSize, ovnDatabaseLogLength)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"slices"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -24,11 +26,15 @@ import (
 	"github.com/harvester/kubeovn-operator/internal/render"
 )
 
+// pausedRequeueInterval is how often node deletion is rechecked while the configuration is paused
+const pausedRequeueInterval = 30 * time.Second
+
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;create;update;patch;delete
 
 type NodeReconciler struct {
 	client.Client
 	RestConfig    *rest.Config
+	CommandRunner executor.CommandRunner
 	Scheme        *runtime.Scheme
 	EventRecorder record.EventRecorder
 	Namespace     string
@@ -90,11 +96,24 @@ func fetchKubeovnConfig(ctx context.Context, client client.Client, namespace str
 	return config, err
 }
 
+// isDefaultConfiguration returns true if the configuration is the singleton resolved by fetchKubeovnConfig.
+// the validating webhook rejects configurations with any other name or namespace, but objects created before the
+// webhook was enabled are still ignored by all controllers
+func isDefaultConfiguration(config *kubeovniov1.Configuration, namespace string) bool {
+	return config.GetName() == kubeovniov1.DefaultConfigurationName && config.GetNamespace() == namespace
+}
+
 // reconcileNodeDeletion will delete the node entry from the ovn database and remove finalizer
 // allowing node to be removed from apiserver
 func (r *NodeReconciler) reconcileNodeDeletion(ctx context.Context, config *kubeovniov1.Configuration, node *corev1.Node) (ctrl.Result, error) {
 	nodeObj := node.DeepCopy()
 
+	// finalizer is retained while paused, as cleanup of ovn databases is not safe during manual intervention
+	if config.Spec.Paused {
+		r.Log.WithValues("node", node.GetName()).Info("configuration is paused, requeuing node deletion")
+		return ctrl.Result{RequeueAfter: pausedRequeueInterval}, nil
+	}
+
 	nodeIP := nodeInternalIP(*nodeObj)
 	if len(nodeIP) == 0 {
 		// should not be possible but lets return and ignore this node
@@ -129,6 +148,8 @@ func (r *NodeReconciler) reconcileNodeDeletion(ctx context.Context, config *kube
 
 // reconcileOVNCentralState attempts to perform the kubeovn cleanup procedure for ovn north and south
 // databases running on ovn-central as documented at https://kubeovn.github.io/docs/v1.12.x/en/ops/change-ovn-central-node/
+// the procedure needs a live raft leader, when all master nodes have lost their databases they are recovered
+// from a backup using an OVNRestore
 func (r *NodeReconciler) reconcileOVNCentralState(ctx context.Context, nodeIP string) error {
 	// generate nb cleanup template
 	r.Log.WithValues("nodeIP", nodeIP).Info("removing node from ovn northbound/southbound database")
@@ -175,7 +196,7 @@ func podList(ctx context.Context, label string, k8sClient client.Client, namespa
 // executeRemoteScriptOnLeader helps users execute remote scripts on a specific pod and return results
 // it emulates kubectl exec against the OVNCentral pod
 func (r *NodeReconciler) executeRemoteScriptOnLeader(ctx context.Context, script string, label string, node string) error {
-	result, err := executeOVNCentralCommand(ctx, script, label, r.Client, r.RestConfig, r.Namespace)
+	result, err := executeOVNCentralCommand(ctx, script, label, r.Client, r.CommandRunner, r.Namespace)
 	if err != nil {
 		return fmt.Errorf("error during southbound cleanup command execution %s: %v", string(result), err)
 	}
@@ -194,7 +215,25 @@ func removeElement(elements []string, element string) []string {
 }
 
 // executeOVNCentralCommand is a wrapper to abstract OVNCentralCommand execution
-func executeOVNCentralCommand(ctx context.Context, script string, label string, k8sClient client.Client, restConfig *rest.Config, namespace string) ([]byte, error) {
+func executeOVNCentralCommand(ctx context.Context, script string, label string, k8sClient client.Client, runner executor.CommandRunner, namespace string) ([]byte, error) {
+	pod, err := ovnCentralLeaderPod(ctx, label, k8sClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return runner.Run(ctx, pod, kubeovniov1.OVNCentralContainerName, script)
+}
+
+// streamOVNCentralCommand writes the output of a script run on the leader to output as it is produced
+func streamOVNCentralCommand(ctx context.Context, script string, label string, k8sClient client.Client, runner executor.CommandRunner, namespace string, output io.Writer) ([]byte, error) {
+	pod, err := ovnCentralLeaderPod(ctx, label, k8sClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return runner.Stream(ctx, pod, kubeovniov1.OVNCentralContainerName, script, output)
+}
+
+// ovnCentralLeaderPod returns the only ovn-central pod holding a leader label
+func ovnCentralLeaderPod(ctx context.Context, label string, k8sClient client.Client, namespace string) (*corev1.Pod, error) {
 	podList, err := podList(ctx, label, k8sClient, namespace)
 	if err != nil {
 		return nil, fmt.Errorf("error generating pod list when checking for label %s: %v", label, err)
@@ -203,10 +242,5 @@ func executeOVNCentralCommand(ctx context.Context, script string, label string,
 	if len(podList.Items) == 0 || len(podList.Items) > 1 {
 		return nil, fmt.Errorf("expected to find only one leader pod, but found %d, requeuing until condition is met", len(podList.Items))
 	}
-	pod := podList.Items[0]
-	podExecutor, err := executor.NewRemoteCommandExecutor(ctx, restConfig, &pod)
-	if err != nil {
-		return nil, fmt.Errorf("error generating new remote command executor: %v", err)
-	}
-	return podExecutor.Run(kubeovniov1.OVNCentralContainerName, script)
+	return &podList.Items[0], nil
 }
@@ -0,0 +1,270 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/backup"
+	"github.com/harvester/kubeovn-operator/internal/executor"
+	"github.com/harvester/kubeovn-operator/internal/render"
+)
+
+const (
+	// backupPendingRequeueInterval is how often a pending backup is rechecked while waiting for the database leaders
+	backupPendingRequeueInterval = 30 * time.Second
+	// backupTimeout is how long a backup can be running before it is considered to be interrupted
+	backupTimeout = 30 * time.Minute
+)
+
+// +kubebuilder:rbac:groups=kubeovn.io,resources=ovnbackups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kubeovn.io,resources=ovnbackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=kubeovn.io,resources=ovnbackups/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;delete
+
+type OVNBackupReconciler struct {
+	client.Client
+	RestConfig    *rest.Config
+	CommandRunner executor.CommandRunner
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+	Namespace     string
+	Log           logr.Logger
+}
+
+func (r *OVNBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kubeovniov1.OVNBackup{}).
+		Named("kubeovn-backup-controller").Complete(r)
+}
+
+// Reconcile takes a snapshot of the northbound and southbound databases from their raft leaders using
+// ovsdb-client backup, and stores the compressed snapshots in the backup target. Backups are only taken once,
+// and the stored artifacts are removed when the backup is deleted
+func (r *OVNBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	backupObj := &kubeovniov1.OVNBackup{}
+	if err := r.Get(ctx, req.NamespacedName, backupObj); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Log.WithValues("name", req.Name).Info("backup not found")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	ovnBackup := backupObj.DeepCopy()
+	if !ovnBackup.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.reconcileBackupDeletion(ctx, ovnBackup)
+	}
+
+	if !controllerutil.ContainsFinalizer(ovnBackup, kubeovniov1.BackupFinalizer) {
+		controllerutil.AddFinalizer(ovnBackup, kubeovniov1.BackupFinalizer)
+		return ctrl.Result{}, r.Patch(ctx, ovnBackup, client.MergeFrom(backupObj))
+	}
+
+	switch ovnBackup.Status.Phase {
+	case kubeovniov1.BackupPhaseCompleted, kubeovniov1.BackupPhaseFailed:
+		return ctrl.Result{}, nil
+	case kubeovniov1.BackupPhaseRunning:
+		// the cache may not have observed the completed status yet
+		if ovnBackup.Status.StartTime != nil && time.Since(ovnBackup.Status.StartTime.Time) < backupTimeout {
+			return ctrl.Result{RequeueAfter: backupPendingRequeueInterval}, nil
+		}
+		// the operator restarted while the backup was running, artifacts stored so far are listed in status
+		// and are cleaned up when the backup is deleted
+		r.failBackup(ovnBackup, "backup was interrupted before completion")
+		return ctrl.Result{}, r.Status().Patch(ctx, ovnBackup, client.MergeFrom(backupObj))
+	}
+
+	if err := backup.ValidateTarget(ovnBackup.Spec.Target); err != nil {
+		r.failBackup(ovnBackup, err.Error())
+		return ctrl.Result{}, r.Status().Patch(ctx, ovnBackup, client.MergeFrom(backupObj))
+	}
+
+	config, err := fetchKubeovnConfig(ctx, r.Client, r.Namespace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	if err != nil {
+		ovnBackup.Status.Phase = kubeovniov1.BackupPhasePending
+		ovnBackup.Status.Message = "waiting for kube-ovn to be deployed"
+		return ctrl.Result{RequeueAfter: backupPendingRequeueInterval}, r.Status().Patch(ctx, ovnBackup, client.MergeFrom(backupObj))
+	}
+	// snapshots are taken from the database leaders, which keep serving while other components are rolled out
+	message, err := r.waitingForLeaders(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if message != "" {
+		ovnBackup.Status.Phase = kubeovniov1.BackupPhasePending
+		ovnBackup.Status.Message = message
+		return ctrl.Result{RequeueAfter: backupPendingRequeueInterval}, r.Status().Patch(ctx, ovnBackup, client.MergeFrom(backupObj))
+	}
+
+	now := metav1.Now()
+	ovnBackup.Status.Phase = kubeovniov1.BackupPhaseRunning
+	ovnBackup.Status.Message = ""
+	ovnBackup.Status.StartTime = &now
+	if err := r.Status().Patch(ctx, ovnBackup, client.MergeFrom(backupObj)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	runningObj := ovnBackup.DeepCopy()
+	if err := r.takeBackup(ctx, config, ovnBackup); err != nil {
+		r.Log.WithValues("name", ovnBackup.Name).Error(err, "backup failed")
+		r.failBackup(ovnBackup, err.Error())
+	} else {
+		completionTime := metav1.Now()
+		ovnBackup.Status.Phase = kubeovniov1.BackupPhaseCompleted
+		ovnBackup.Status.CompletionTime = &completionTime
+		r.EventRecorder.Event(ovnBackup, corev1.EventTypeNormal, "BackupCompleted",
+			fmt.Sprintf("stored %d database snapshots", len(ovnBackup.Status.Artifacts)))
+	}
+	return ctrl.Result{}, r.Status().Patch(ctx, ovnBackup, client.MergeFrom(runningObj))
+}
+
+// waitingForLeaders returns a message if the northbound or southbound leader has not been elected
+func (r *OVNBackupReconciler) waitingForLeaders(ctx context.Context) (string, error) {
+	for _, label := range []string{kubeovniov1.NBLeaderLabel, kubeovniov1.SBLeaderLabel} {
+		pods, err := podList(ctx, label, r.Client, r.Namespace)
+		if err != nil {
+			return "", err
+		}
+		if len(pods.Items) == 0 {
+			return fmt.Sprintf("waiting for ovn-central pod with label %s", label), nil
+		}
+	}
+	return "", nil
+}
+
+// takeBackup stores a snapshot of each database, artifacts are recorded in status as they are stored
+func (r *OVNBackupReconciler) takeBackup(ctx context.Context, config *kubeovniov1.Configuration, ovnBackup *kubeovniov1.OVNBackup) (err error) {
+	store, err := backup.NewStore(ctx, ovnBackup.Spec.Target, backupStoreOptions(r.Client, r.RestConfig, r.Namespace, config))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := store.Close(ctx); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	databases := []struct {
+		name   string
+		script string
+		label  string
+	}{
+		{name: kubeovniov1.NBDatabase, script: kubeovniov1.NBBackupScript, label: kubeovniov1.NBLeaderLabel},
+		{name: kubeovniov1.SBDatabase, script: kubeovniov1.SBBackupScript, label: kubeovniov1.SBLeaderLabel},
+	}
+	for _, db := range databases {
+		leader, err := leaderPodName(ctx, db.label, r.Client, r.Namespace)
+		if err != nil {
+			return fmt.Errorf("error fetching %s leader: %v", db.name, err)
+		}
+		// snapshots of large databases are streamed through gzip into the store, instead of being held in memory
+		snapshot := backup.Compress(func(w io.Writer) error {
+			result, err := streamOVNCentralCommand(ctx, db.script, db.label, r.Client, r.CommandRunner, r.Namespace, w)
+			if err != nil {
+				return fmt.Errorf("error taking snapshot of %s %s: %v", db.name, string(result), err)
+			}
+			return nil
+		})
+		location, err := store.Put(ctx, backup.ArtifactName(ovnBackup.Name, db.name), snapshot)
+		if snapshotErr := snapshot.Close(); err == nil && snapshotErr != nil {
+			// the store may not observe a stream which was cut short, so the truncated artifact is removed
+			if deleteErr := store.Delete(ctx, location); deleteErr != nil {
+				r.Log.WithValues("name", ovnBackup.Name, "location", location).Error(deleteErr, "error deleting incomplete backup artifact")
+			}
+			err = snapshotErr
+		}
+		if err != nil {
+			return fmt.Errorf("error storing snapshot of %s: %v", db.name, err)
+		}
+		ovnBackup.Status.Artifacts = append(ovnBackup.Status.Artifacts, kubeovniov1.BackupArtifact{
+			Database: db.name,
+			Location: location,
+			Size:     snapshot.Size(),
+			Checksum: snapshot.Checksum(),
+			Leader:   leader,
+		})
+	}
+	return nil
+}
+
+// reconcileBackupDeletion removes the stored artifacts before removing the finalizer
+func (r *OVNBackupReconciler) reconcileBackupDeletion(ctx context.Context, ovnBackup *kubeovniov1.OVNBackup) error {
+	if !controllerutil.ContainsFinalizer(ovnBackup, kubeovniov1.BackupFinalizer) {
+		return nil
+	}
+	backupObj := ovnBackup.DeepCopy()
+
+	if len(ovnBackup.Status.Artifacts) != 0 {
+		// the configuration is only needed for the image used to mount persistent volume claims
+		config, err := fetchKubeovnConfig(ctx, r.Client, r.Namespace)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		store, err := backup.NewStore(ctx, ovnBackup.Spec.Target, backupStoreOptions(r.Client, r.RestConfig, r.Namespace, config))
+		if err != nil {
+			return err
+		}
+		for _, artifact := range ovnBackup.Status.Artifacts {
+			r.Log.WithValues("name", ovnBackup.Name, "location", artifact.Location).Info("deleting backup artifact")
+			if err := store.Delete(ctx, artifact.Location); err != nil {
+				_ = store.Close(ctx)
+				return fmt.Errorf("error deleting backup artifact %s: %v", artifact.Location, err)
+			}
+		}
+		if err := store.Close(ctx); err != nil {
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(ovnBackup, kubeovniov1.BackupFinalizer)
+	return r.Patch(ctx, ovnBackup, client.MergeFrom(backupObj))
+}
+
+func (r *OVNBackupReconciler) failBackup(ovnBackup *kubeovniov1.OVNBackup, message string) {
+	now := metav1.Now()
+	ovnBackup.Status.Phase = kubeovniov1.BackupPhaseFailed
+	ovnBackup.Status.Message = message
+	ovnBackup.Status.CompletionTime = &now
+	r.EventRecorder.Event(ovnBackup, corev1.EventTypeWarning, "BackupFailed", message)
+}
+
+// backupStoreOptions returns the options used to access backup targets, pods created to access volumes
+// use the ovn-central image
+func backupStoreOptions(k8sClient client.Client, restConfig *rest.Config, namespace string, config *kubeovniov1.Configuration) backup.StoreOptions {
+	return backup.StoreOptions{
+		Client:           k8sClient,
+		RestConfig:       restConfig,
+		Namespace:        namespace,
+		Image:            config.Status.Images[render.OVNCentralImageKey],
+		ImagePullSecrets: config.Spec.Global.Registry.ImagePullSecrets,
+	}
+}
+
+// leaderPodName returns the name of the ovn-central pod holding a leader label
+func leaderPodName(ctx context.Context, label string, k8sClient client.Client, namespace string) (string, error) {
+	pods, err := podList(ctx, label, k8sClient, namespace)
+	if err != nil {
+		return "", err
+	}
+	if len(pods.Items) != 1 {
+		return "", fmt.Errorf("expected to find only one leader pod, but found %d", len(pods.Items))
+	}
+	return pods.Items[0].GetName(), nil
+}
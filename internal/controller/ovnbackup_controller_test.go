@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/backup"
+)
+
+func newTestBackup(name string, created time.Time, phase string) *kubeovniov1.OVNBackup {
+	return &kubeovniov1.OVNBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         defaultKubeovnNamespace,
+			CreationTimestamp: metav1.NewTime(created),
+			Labels:            map[string]string{kubeovniov1.BackupScheduleLabel: "nightly"},
+			Finalizers:        []string{kubeovniov1.BackupFinalizer},
+		},
+		Spec: kubeovniov1.OVNBackupSpec{
+			Target: kubeovniov1.BackupTarget{Object: &kubeovniov1.ObjectBackupTarget{Kind: "ConfigMap"}},
+		},
+		Status: kubeovniov1.OVNBackupStatus{Phase: phase},
+	}
+}
+
+func newBackupTestClient(t *testing.T, objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(objs...).
+		WithStatusSubresource(&kubeovniov1.OVNBackup{}, &kubeovniov1.OVNBackupSchedule{}).Build()
+}
+
+func reconcileBackup(t *testing.T, r *OVNBackupReconciler, name string) *kubeovniov1.OVNBackup {
+	_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: defaultKubeovnNamespace}})
+	require.NoError(t, err)
+	ovnBackup := &kubeovniov1.OVNBackup{}
+	require.NoError(t, r.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: defaultKubeovnNamespace}, ovnBackup))
+	return ovnBackup
+}
+
+func Test_BackupWaitsForLeaders(t *testing.T) {
+	assert := require.New(t)
+	exec := newFakeExec()
+	exec.outputs[kubeovniov1.NBBackupScript] = "nb snapshot"
+	exec.outputs[kubeovniov1.SBBackupScript] = "sb snapshot"
+
+	// the configuration is still rolling out, which does not block backups while the leaders are elected
+	config := newTestConfiguration()
+	config.Status.Status = kubeovniov1.ConfigurationStatusDeploying
+	config.Status.CurrentRevision = 1
+	r := &OVNBackupReconciler{
+		Client:        newBackupTestClient(t, config, newTestBackup("backup", time.Now(), "")),
+		CommandRunner: exec,
+		EventRecorder: record.NewFakeRecorder(100),
+		Namespace:     defaultKubeovnNamespace,
+		Log:           logr.Discard(),
+	}
+
+	ovnBackup := reconcileBackup(t, r, "backup")
+	assert.Equal(kubeovniov1.BackupPhasePending, ovnBackup.Status.Phase)
+	assert.Contains(ovnBackup.Status.Message, kubeovniov1.NBLeaderLabel)
+	assert.Empty(exec.scripts)
+
+	assert.NoError(r.Create(context.TODO(), newTestOVNCentralPod("ovn-central-1", "node1", true)))
+	ovnBackup = reconcileBackup(t, r, "backup")
+	assert.Equal(kubeovniov1.BackupPhaseCompleted, ovnBackup.Status.Phase, ovnBackup.Status.Message)
+	assert.Len(ovnBackup.Status.Artifacts, 2)
+	assert.Equal([]string{kubeovniov1.NBBackupScript, kubeovniov1.SBBackupScript}, exec.scripts)
+}
+
+func Test_BackupStreamsSnapshots(t *testing.T) {
+	assert := require.New(t)
+	exec := newFakeExec()
+	exec.outputs[kubeovniov1.NBBackupScript] = "nb snapshot"
+	exec.outputs[kubeovniov1.SBBackupScript] = "sb snapshot"
+	config := newTestConfiguration()
+	r := &OVNBackupReconciler{
+		Client:        newBackupTestClient(t, config, newTestOVNCentralPod("ovn-central-1", "node1", true), newTestBackup("backup", time.Now(), "")),
+		CommandRunner: exec,
+		EventRecorder: record.NewFakeRecorder(100),
+		Namespace:     defaultKubeovnNamespace,
+		Log:           logr.Discard(),
+	}
+
+	// the size and checksum of the compressed snapshot are recorded as it is streamed into the store
+	ovnBackup := reconcileBackup(t, r, "backup")
+	assert.Equal(kubeovniov1.BackupPhaseCompleted, ovnBackup.Status.Phase, ovnBackup.Status.Message)
+	expected := map[string]string{kubeovniov1.NBDatabase: "nb snapshot", kubeovniov1.SBDatabase: "sb snapshot"}
+	store, err := backup.NewStore(context.TODO(), ovnBackup.Spec.Target, backup.StoreOptions{Client: r.Client, Namespace: defaultKubeovnNamespace})
+	assert.NoError(err)
+	for _, artifact := range ovnBackup.Status.Artifacts {
+		data, err := store.Get(context.TODO(), artifact.Location)
+		assert.NoError(err)
+		assert.Equal(int64(len(data)), artifact.Size)
+		snapshot, err := backup.Decompress(data, artifact.Checksum)
+		assert.NoError(err)
+		assert.Equal(expected[artifact.Database], string(snapshot))
+	}
+
+	// a failed snapshot does not leave a partial artifact behind
+	exec.err = errors.New("ovsdb-client: connection refused")
+	assert.NoError(r.Create(context.TODO(), newTestBackup("failed", time.Now(), "")))
+	ovnBackup = reconcileBackup(t, r, "failed")
+	assert.Equal(kubeovniov1.BackupPhaseFailed, ovnBackup.Status.Phase)
+	assert.Contains(ovnBackup.Status.Message, "connection refused")
+	assert.Empty(ovnBackup.Status.Artifacts)
+	configMaps := &corev1.ConfigMapList{}
+	assert.NoError(r.List(context.TODO(), configMaps, client.InNamespace(defaultKubeovnNamespace)))
+	for _, cm := range configMaps.Items {
+		assert.NotContains(cm.Name, "failed")
+	}
+}
@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups=kubeovn.io,resources=ovnbackupschedules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kubeovn.io,resources=ovnbackupschedules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=kubeovn.io,resources=ovnbackupschedules/finalizers,verbs=update
+
+type OVNBackupScheduleReconciler struct {
+	client.Client
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+	Namespace     string
+	Log           logr.Logger
+}
+
+func (r *OVNBackupScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kubeovniov1.OVNBackupSchedule{}).
+		Owns(&kubeovniov1.OVNBackup{}).
+		Named("kubeovn-backup-schedule-controller").Complete(r)
+}
+
+// Reconcile creates an OVNBackup each time the schedule interval passes, and deletes completed backups beyond
+// the retention count. Backups are owned by the schedule, so deleting the schedule removes all of its backups
+func (r *OVNBackupScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	scheduleObj := &kubeovniov1.OVNBackupSchedule{}
+	if err := r.Get(ctx, req.NamespacedName, scheduleObj); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Log.WithValues("name", req.Name).Info("backup schedule not found")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	schedule := scheduleObj.DeepCopy()
+	if !schedule.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	interval := schedule.Spec.Interval.Duration
+	if interval <= 0 {
+		r.EventRecorder.Event(schedule, corev1.EventTypeWarning, "InvalidInterval", "spec.interval needs to be greater than zero")
+		return ctrl.Result{}, nil
+	}
+
+	backups, err := r.listBackups(ctx, schedule)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// the newest backup is also considered, as the status update recording the last schedule time may not
+	// have been observed yet when the reconcile is triggered by the creation of the backup
+	lastScheduleTime := schedule.Status.LastScheduleTime
+	if len(backups) != 0 && (lastScheduleTime == nil || lastScheduleTime.Before(&backups[0].CreationTimestamp)) {
+		lastScheduleTime = &backups[0].CreationTimestamp
+	}
+
+	now := metav1.Now()
+	nextSchedule := now.Add(interval)
+	if !schedule.Spec.Suspend {
+		if lastScheduleTime == nil || !lastScheduleTime.Add(interval).After(now.Time) {
+			// backups waiting for the database leaders would otherwise pile up, a new backup is created once the
+			// newest has finished
+			if len(backups) != 0 && backupInProgress(&backups[0]) {
+				r.Log.WithValues("schedule", schedule.Name, "backup", backups[0].Name).Info("skipping scheduled backup, newest backup has not finished")
+				nextSchedule = now.Add(backupPendingRequeueInterval)
+			} else {
+				if err := r.createBackup(ctx, schedule, now); err != nil {
+					return ctrl.Result{}, err
+				}
+				schedule.Status.LastScheduleTime = &now
+			}
+		} else {
+			nextSchedule = lastScheduleTime.Add(interval)
+		}
+	}
+
+	if err := r.reconcileRetention(ctx, schedule, backups); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !reflect.DeepEqual(schedule.Status, scheduleObj.Status) {
+		if err := r.Status().Patch(ctx, schedule, client.MergeFrom(scheduleObj)); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: time.Until(nextSchedule)}, nil
+}
+
+func (r *OVNBackupScheduleReconciler) createBackup(ctx context.Context, schedule *kubeovniov1.OVNBackupSchedule, now metav1.Time) error {
+	ovnBackup := &kubeovniov1.OVNBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", schedule.Name, now.UTC().Format("20060102150405")),
+			Namespace: schedule.Namespace,
+			Labels: map[string]string{
+				kubeovniov1.BackupScheduleLabel: schedule.Name,
+			},
+		},
+		Spec: kubeovniov1.OVNBackupSpec{
+			Target: schedule.Spec.Target,
+		},
+	}
+	if err := controllerutil.SetControllerReference(schedule, ovnBackup, r.Scheme); err != nil {
+		return fmt.Errorf("error setting owner reference on backup %s: %v", ovnBackup.Name, err)
+	}
+	r.Log.WithValues("schedule", schedule.Name, "backup", ovnBackup.Name).Info("creating scheduled backup")
+	if err := r.Create(ctx, ovnBackup); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("error creating backup %s: %v", ovnBackup.Name, err)
+	}
+	return nil
+}
+
+// listBackups returns the backups created by a schedule, newest first
+func (r *OVNBackupScheduleReconciler) listBackups(ctx context.Context, schedule *kubeovniov1.OVNBackupSchedule) ([]kubeovniov1.OVNBackup, error) {
+	backupList := &kubeovniov1.OVNBackupList{}
+	if err := r.List(ctx, backupList, client.InNamespace(schedule.Namespace),
+		client.MatchingLabels{kubeovniov1.BackupScheduleLabel: schedule.Name}); err != nil {
+		return nil, fmt.Errorf("error listing backups for schedule %s: %v", schedule.Name, err)
+	}
+
+	backups := backupList.Items
+	slices.SortFunc(backups, func(a, b kubeovniov1.OVNBackup) int {
+		return b.CreationTimestamp.Compare(a.CreationTimestamp.Time)
+	})
+	return backups, nil
+}
+
+// reconcileRetention keeps the newest completed backups up to the retention count. Failed backups are
+// retained until a newer backup has completed, so failures remain visible until the schedule recovers. Pending
+// backups older than the newest backup are stale and count toward the retention count
+func (r *OVNBackupScheduleReconciler) reconcileRetention(ctx context.Context, schedule *kubeovniov1.OVNBackupSchedule, backups []kubeovniov1.OVNBackup) error {
+	retention := int(schedule.Spec.Retention)
+	if retention < 1 {
+		retention = 1
+	}
+
+	var retained []string
+	var kept int
+	var lastSuccessful *kubeovniov1.OVNBackup
+	for i := range backups {
+		b := &backups[i]
+		if !b.DeletionTimestamp.IsZero() {
+			continue
+		}
+		switch b.Status.Phase {
+		case kubeovniov1.BackupPhaseCompleted:
+			if lastSuccessful == nil {
+				lastSuccessful = b
+			}
+			if kept < retention {
+				kept++
+				retained = append(retained, b.Name)
+				continue
+			}
+		case kubeovniov1.BackupPhaseFailed:
+			if lastSuccessful == nil {
+				continue
+			}
+		case kubeovniov1.BackupPhaseRunning:
+			continue
+		default:
+			if i == 0 {
+				continue
+			}
+			if kept < retention {
+				kept++
+				continue
+			}
+		}
+
+		r.Log.WithValues("schedule", schedule.Name, "backup", b.Name).Info("deleting backup outside of retention")
+		if err := r.Delete(ctx, b); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error deleting backup %s: %v", b.Name, err)
+		}
+	}
+
+	schedule.Status.RetainedBackups = retained
+	if lastSuccessful != nil {
+		schedule.Status.LastSuccessfulBackupName = lastSuccessful.Name
+		schedule.Status.LastSuccessfulBackupTime = lastSuccessful.Status.CompletionTime
+	}
+	return nil
+}
+
+// backupInProgress returns true if a backup has not been started yet, or is still running
+func backupInProgress(b *kubeovniov1.OVNBackup) bool {
+	if !b.DeletionTimestamp.IsZero() {
+		return false
+	}
+	switch b.Status.Phase {
+	case "", kubeovniov1.BackupPhasePending, kubeovniov1.BackupPhaseRunning:
+		return true
+	}
+	return false
+}
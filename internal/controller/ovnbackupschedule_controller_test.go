@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+func Test_BackupScheduleSkipsWhileInProgress(t *testing.T) {
+	assert := require.New(t)
+	now := time.Now()
+	lastSchedule := metav1.NewTime(now.Add(-2 * time.Hour))
+	schedule := &kubeovniov1.OVNBackupSchedule{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: defaultKubeovnNamespace},
+		Spec: kubeovniov1.OVNBackupScheduleSpec{
+			Interval:  metav1.Duration{Duration: time.Hour},
+			Retention: 2,
+			Target:    kubeovniov1.BackupTarget{Object: &kubeovniov1.ObjectBackupTarget{Kind: "ConfigMap"}},
+		},
+		Status: kubeovniov1.OVNBackupScheduleStatus{LastScheduleTime: &lastSchedule},
+	}
+	r := &OVNBackupScheduleReconciler{
+		Client: newBackupTestClient(t, schedule, newTestBackup("nightly-pending", lastSchedule.Time, kubeovniov1.BackupPhasePending)),
+		Scheme: newTestScheme(t),
+		Log:    logr.Discard(),
+	}
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "nightly", Namespace: defaultKubeovnNamespace}}
+
+	// no backup is created while the newest is pending
+	result, err := r.Reconcile(context.TODO(), request)
+	assert.NoError(err)
+	assert.LessOrEqual(result.RequeueAfter, backupPendingRequeueInterval)
+	backups := &kubeovniov1.OVNBackupList{}
+	assert.NoError(r.List(context.TODO(), backups))
+	assert.Len(backups.Items, 1)
+
+	// a backup is created once the newest has finished
+	pending := &kubeovniov1.OVNBackup{}
+	assert.NoError(r.Get(context.TODO(), types.NamespacedName{Name: "nightly-pending", Namespace: defaultKubeovnNamespace}, pending))
+	pending.Status.Phase = kubeovniov1.BackupPhaseFailed
+	assert.NoError(r.Status().Update(context.TODO(), pending))
+	_, err = r.Reconcile(context.TODO(), request)
+	assert.NoError(err)
+	assert.NoError(r.List(context.TODO(), backups))
+	assert.Len(backups.Items, 2)
+}
+
+func Test_BackupScheduleRetainsStalePending(t *testing.T) {
+	assert := require.New(t)
+	now := time.Now()
+	schedule := &kubeovniov1.OVNBackupSchedule{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: defaultKubeovnNamespace},
+		Spec:       kubeovniov1.OVNBackupScheduleSpec{Interval: metav1.Duration{Duration: time.Hour}, Retention: 2},
+	}
+	r := &OVNBackupScheduleReconciler{
+		Client: newBackupTestClient(t, schedule,
+			newTestBackup("nightly-4", now, kubeovniov1.BackupPhasePending),
+			newTestBackup("nightly-3", now.Add(-time.Hour), kubeovniov1.BackupPhasePending),
+			newTestBackup("nightly-2", now.Add(-2*time.Hour), kubeovniov1.BackupPhaseCompleted),
+			newTestBackup("nightly-1", now.Add(-3*time.Hour), kubeovniov1.BackupPhaseCompleted),
+		),
+		Log: logr.Discard(),
+	}
+	backups, err := r.listBackups(context.TODO(), schedule)
+	assert.NoError(err)
+
+	// the newest pending backup may still be started, the stale pending backup takes up a retained slot
+	assert.NoError(r.reconcileRetention(context.TODO(), schedule, backups))
+	assert.Equal([]string{"nightly-2"}, schedule.Status.RetainedBackups)
+	deleted := &kubeovniov1.OVNBackup{}
+	assert.NoError(r.Get(context.TODO(), types.NamespacedName{Name: "nightly-1", Namespace: defaultKubeovnNamespace}, deleted))
+	assert.False(deleted.DeletionTimestamp.IsZero(), "expected backup outside of retention to be deleted")
+	for _, name := range []string{"nightly-4", "nightly-3", "nightly-2"} {
+		retained := &kubeovniov1.OVNBackup{}
+		assert.NoError(r.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: defaultKubeovnNamespace}, retained))
+		assert.True(retained.DeletionTimestamp.IsZero(), "expected backup %s to be retained", name)
+	}
+}
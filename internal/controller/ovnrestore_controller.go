@@ -0,0 +1,483 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/backup"
+	"github.com/harvester/kubeovn-operator/internal/executor"
+	"github.com/harvester/kubeovn-operator/internal/ovsdb"
+	"github.com/harvester/kubeovn-operator/internal/render"
+)
+
+const (
+	// restoreRequeueInterval is how often a step waiting on ovn-central is rechecked
+	restoreRequeueInterval = 10 * time.Second
+	// restoreStepTimeout is how long a step can wait on ovn-central before the restore is failed
+	restoreStepTimeout = 15 * time.Minute
+	defaultOVNDir      = "/etc/origin/ovn"
+)
+
+// +kubebuilder:rbac:groups=kubeovn.io,resources=ovnrestores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kubeovn.io,resources=ovnrestores/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=kubeovn.io,resources=ovnrestores/finalizers,verbs=update
+
+type OVNRestoreReconciler struct {
+	client.Client
+	RestConfig    *rest.Config
+	CommandRunner executor.CommandRunner
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+	Namespace     string
+	Log           logr.Logger
+}
+
+// restoreDatabase is a database restored from an OVNBackup
+type restoreDatabase struct {
+	name        string
+	file        string
+	port        int
+	statusCheck string
+	leaderLabel string
+}
+
+var restoreDatabases = []restoreDatabase{
+	{name: kubeovniov1.NBDatabase, file: "ovnnb_db.db", port: kubeovniov1.NBRaftPort, statusCheck: kubeovniov1.NBCheckScript, leaderLabel: kubeovniov1.NBLeaderLabel},
+	{name: kubeovniov1.SBDatabase, file: "ovnsb_db.db", port: kubeovniov1.SBRaftPort, statusCheck: kubeovniov1.SBCheckScript, leaderLabel: kubeovniov1.SBLeaderLabel},
+}
+
+var restoreSteps = []string{
+	kubeovniov1.RestoreStepValidate,
+	kubeovniov1.RestoreStepScaleDown,
+	kubeovniov1.RestoreStepReplaceDatabases,
+	kubeovniov1.RestoreStepConvertToCluster,
+	kubeovniov1.RestoreStepScaleUp,
+	kubeovniov1.RestoreStepVerifyLeaders,
+}
+
+func (r *OVNRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kubeovniov1.OVNRestore{}).
+		Named("kubeovn-restore-controller").Complete(r)
+}
+
+// Reconcile restores the northbound and southbound databases from an OVNBackup when all members of the raft
+// clusters have lost their databases. The snapshots taken by ovsdb-client backup are standalone databases, so
+// the raft clusters are recreated from the snapshots on a single bootstrap node, and the remaining master nodes
+// join the new clusters when ovn-central is started. Each step is recorded in status as it is run, and steps
+// waiting on ovn-central are rechecked until restoreStepTimeout
+func (r *OVNRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	restoreObj := &kubeovniov1.OVNRestore{}
+	if err := r.Get(ctx, req.NamespacedName, restoreObj); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Log.WithValues("name", req.Name).Info("restore not found")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	restore := restoreObj.DeepCopy()
+	if !restore.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	switch restore.Status.Phase {
+	case kubeovniov1.RestorePhaseCompleted, kubeovniov1.RestorePhaseFailed:
+		return ctrl.Result{}, nil
+	}
+
+	config, err := fetchKubeovnConfig(ctx, r.Client, r.Namespace)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		restore.Status.Phase = kubeovniov1.RestorePhasePending
+		restore.Status.Message = "waiting for kube-ovn configuration"
+		return ctrl.Result{RequeueAfter: restoreRequeueInterval}, r.Status().Patch(ctx, restore, client.MergeFrom(restoreObj))
+	}
+
+	if restore.Status.Phase != kubeovniov1.RestorePhaseRunning {
+		now := metav1.Now()
+		restore.Status.Phase = kubeovniov1.RestorePhaseRunning
+		restore.Status.Message = ""
+		restore.Status.StartTime = &now
+		restore.Status.Steps = nil
+		for _, name := range restoreSteps {
+			restore.Status.Steps = append(restore.Status.Steps, kubeovniov1.RestoreStep{Name: name, Status: kubeovniov1.RestorePhasePending})
+		}
+	}
+
+	for i := range restore.Status.Steps {
+		step := &restore.Status.Steps[i]
+		if step.Status == kubeovniov1.RestorePhaseCompleted {
+			continue
+		}
+		if step.Status != kubeovniov1.RestorePhaseRunning {
+			now := metav1.Now()
+			step.Status = kubeovniov1.RestorePhaseRunning
+			step.StartTime = &now
+		}
+
+		done, message, err := r.runRestoreStep(ctx, step.Name, restore, config)
+		step.Message = message
+		if err == nil && !done && time.Since(step.StartTime.Time) > restoreStepTimeout {
+			err = fmt.Errorf("timed out after %s: %s", restoreStepTimeout, message)
+		}
+		if err != nil {
+			r.Log.WithValues("name", restore.Name, "step", step.Name).Error(err, "restore failed")
+			r.failRestore(restore, step, err)
+			return ctrl.Result{}, r.Status().Patch(ctx, restore, client.MergeFrom(restoreObj))
+		}
+		if !done {
+			return ctrl.Result{RequeueAfter: restoreRequeueInterval}, r.Status().Patch(ctx, restore, client.MergeFrom(restoreObj))
+		}
+
+		now := metav1.Now()
+		step.Status = kubeovniov1.RestorePhaseCompleted
+		step.CompletionTime = &now
+		r.Log.WithValues("name", restore.Name, "step", step.Name).Info(message)
+		// progress is recorded after each step, so completed steps are not repeated if the operator restarts
+		if err := r.Status().Patch(ctx, restore, client.MergeFrom(restoreObj)); err != nil {
+			return ctrl.Result{}, err
+		}
+		restoreObj = restore.DeepCopy()
+	}
+
+	now := metav1.Now()
+	restore.Status.Phase = kubeovniov1.RestorePhaseCompleted
+	restore.Status.Message = fmt.Sprintf("restored databases from backup %s", restore.Spec.BackupName)
+	restore.Status.CompletionTime = &now
+	r.EventRecorder.Event(restore, corev1.EventTypeNormal, "RestoreCompleted", restore.Status.Message)
+	return ctrl.Result{}, r.Status().Patch(ctx, restore, client.MergeFrom(restoreObj))
+}
+
+// runRestoreStep runs a step and returns if the step is done, along with a message describing its progress
+func (r *OVNRestoreReconciler) runRestoreStep(ctx context.Context, name string, restore *kubeovniov1.OVNRestore, config *kubeovniov1.Configuration) (bool, string, error) {
+	switch name {
+	case kubeovniov1.RestoreStepValidate:
+		return r.validateRestore(ctx, restore, config)
+	case kubeovniov1.RestoreStepScaleDown:
+		return r.scaleDownOVNCentral(ctx, restore, config)
+	case kubeovniov1.RestoreStepReplaceDatabases:
+		return r.replaceDatabases(ctx, restore, config)
+	case kubeovniov1.RestoreStepConvertToCluster:
+		return r.convertToCluster(ctx, restore, config)
+	case kubeovniov1.RestoreStepScaleUp:
+		return r.scaleUpOVNCentral(ctx, restore, config)
+	case kubeovniov1.RestoreStepVerifyLeaders:
+		return r.verifyLeaders(ctx, config)
+	default:
+		return false, "", fmt.Errorf("unknown restore step %s", name)
+	}
+}
+
+// validateRestore fetches and verifies the snapshots before ovn-central is stopped
+func (r *OVNRestoreReconciler) validateRestore(ctx context.Context, restore *kubeovniov1.OVNRestore, config *kubeovniov1.Configuration) (bool, string, error) {
+	restoreList := &kubeovniov1.OVNRestoreList{}
+	if err := r.List(ctx, restoreList, client.InNamespace(restore.Namespace)); err != nil {
+		return false, "", fmt.Errorf("error listing restores: %v", err)
+	}
+	for _, v := range restoreList.Items {
+		if v.Name != restore.Name && v.Status.Phase == kubeovniov1.RestorePhaseRunning {
+			return false, "", fmt.Errorf("restore %s is already running", v.Name)
+		}
+	}
+
+	if len(config.Status.MatchingNodeAddresses) == 0 {
+		return false, "", errors.New("no master nodes found in configuration status")
+	}
+
+	if _, err := r.fetchSnapshots(ctx, restore, config); err != nil {
+		return false, "", err
+	}
+	return true, fmt.Sprintf("verified snapshots of backup %s", restore.Spec.BackupName), nil
+}
+
+// scaleDownOVNCentral pauses the configuration, so the operator does not scale ovn-central back up, and waits
+// for all ovn-central pods to be removed
+func (r *OVNRestoreReconciler) scaleDownOVNCentral(ctx context.Context, restore *kubeovniov1.OVNRestore, config *kubeovniov1.Configuration) (bool, string, error) {
+	if !config.Spec.Paused {
+		configObj := config.DeepCopy()
+		config.Spec.Paused = true
+		if err := r.Patch(ctx, config, client.MergeFrom(configObj)); err != nil {
+			return false, "", fmt.Errorf("error pausing configuration %s: %v", config.Name, err)
+		}
+		restore.Status.PausedConfiguration = true
+		r.EventRecorder.Event(config, corev1.EventTypeNormal, "RestorePaused", fmt.Sprintf("configuration paused by restore %s", restore.Name))
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: kubeovniov1.OVNCentralDeploymentName, Namespace: r.Namespace}, deployment); err != nil {
+		return false, "", fmt.Errorf("error fetching deployment %s: %v", kubeovniov1.OVNCentralDeploymentName, err)
+	}
+	if restore.Status.Replicas == nil {
+		restore.Status.Replicas = ptr.To(ptr.Deref(deployment.Spec.Replicas, 1))
+	}
+	if ptr.Deref(deployment.Spec.Replicas, 1) != 0 {
+		deploymentObj := deployment.DeepCopy()
+		deployment.Spec.Replicas = ptr.To(int32(0))
+		if err := r.Patch(ctx, deployment, client.MergeFrom(deploymentObj)); err != nil {
+			return false, "", fmt.Errorf("error scaling down deployment %s: %v", deployment.Name, err)
+		}
+	}
+
+	pods, err := podList(ctx, kubeovniov1.OVNCentralLabel, r.Client, r.Namespace)
+	if err != nil {
+		return false, "", fmt.Errorf("error listing ovn-central pods: %v", err)
+	}
+	if len(pods.Items) != 0 {
+		return false, fmt.Sprintf("waiting for %d ovn-central pods to terminate", len(pods.Items)), nil
+	}
+	return true, fmt.Sprintf("scaled down ovn-central from %d replicas", *restore.Status.Replicas), nil
+}
+
+// replaceDatabases moves the existing database files aside on all master nodes, so nodes other than the
+// bootstrap node join the restored cluster, and writes the snapshots to the bootstrap node
+func (r *OVNRestoreReconciler) replaceDatabases(ctx context.Context, restore *kubeovniov1.OVNRestore, config *kubeovniov1.Configuration) (bool, string, error) {
+	snapshots, err := r.fetchSnapshots(ctx, restore, config)
+	if err != nil {
+		return false, "", err
+	}
+	nodes, err := masterNodes(ctx, r.Client, config)
+	if err != nil {
+		return false, "", err
+	}
+	if restore.Status.BootstrapNode == "" {
+		restore.Status.BootstrapNode = nodes[0].Name
+	}
+
+	var files []string
+	for _, db := range restoreDatabases {
+		files = append(files, db.file)
+	}
+	script, err := render.GenerateMoveDatabasesScript(backup.HostMountPath, files, "pre-restore-"+restore.Name)
+	if err != nil {
+		return false, "", err
+	}
+
+	var nodeNames []string
+	for _, node := range nodes {
+		err := r.runOnNode(ctx, config, node.Name, func(runner *backup.PodRunner) error {
+			if result, err := runner.Run(ctx, script, nil); err != nil {
+				return fmt.Errorf("error moving databases aside %s: %v", string(result), err)
+			}
+			if node.Name != restore.Status.BootstrapNode {
+				return nil
+			}
+			for _, db := range restoreDatabases {
+				filePath := path.Join(backup.HostMountPath, db.file+".standalone")
+				if result, err := runner.Run(ctx, fmt.Sprintf("cat > '%s'", filePath), snapshots[db.name]); err != nil {
+					return fmt.Errorf("error writing snapshot of %s %s: %v", db.name, string(result), err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return false, "", fmt.Errorf("error replacing databases on node %s: %v", node.Name, err)
+		}
+		nodeNames = append(nodeNames, node.Name)
+	}
+	return true, fmt.Sprintf("replaced databases on nodes %s, snapshots written to %s", strings.Join(nodeNames, ","), restore.Status.BootstrapNode), nil
+}
+
+// convertToCluster converts the standalone snapshots on the bootstrap node into single member raft databases,
+// using the same raft address ovn-central uses on the node
+func (r *OVNRestoreReconciler) convertToCluster(ctx context.Context, restore *kubeovniov1.OVNRestore, config *kubeovniov1.Configuration) (bool, string, error) {
+	node := &corev1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: restore.Status.BootstrapNode}, node); err != nil {
+		return false, "", fmt.Errorf("error fetching bootstrap node %s: %v", restore.Status.BootstrapNode, err)
+	}
+	address := nodeInternalIP(*node)
+	if address == "" {
+		return false, "", fmt.Errorf("bootstrap node %s has no internal ip", node.Name)
+	}
+	protocol := "tcp"
+	if config.Spec.Networking.EnableSSL != nil && *config.Spec.Networking.EnableSSL {
+		protocol = "ssl"
+	}
+
+	err := r.runOnNode(ctx, config, node.Name, func(runner *backup.PodRunner) error {
+		for _, db := range restoreDatabases {
+			script, err := render.GenerateCreateClusterScript(backup.HostMountPath, db.file, protocol, address, db.port)
+			if err != nil {
+				return err
+			}
+			if result, err := runner.Run(ctx, script, nil); err != nil {
+				return fmt.Errorf("error creating %s cluster %s: %v", db.name, string(result), err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, "", err
+	}
+	return true, fmt.Sprintf("created raft clusters on node %s with address %s", node.Name, address), nil
+}
+
+// scaleUpOVNCentral restores the ovn-central replicas and resumes the configuration if it was paused by the restore
+func (r *OVNRestoreReconciler) scaleUpOVNCentral(ctx context.Context, restore *kubeovniov1.OVNRestore, config *kubeovniov1.Configuration) (bool, string, error) {
+	replicas := ptr.Deref(restore.Status.Replicas, int32(len(config.Status.MatchingNodeAddresses)))
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: kubeovniov1.OVNCentralDeploymentName, Namespace: r.Namespace}, deployment); err != nil {
+		return false, "", fmt.Errorf("error fetching deployment %s: %v", kubeovniov1.OVNCentralDeploymentName, err)
+	}
+	if ptr.Deref(deployment.Spec.Replicas, 1) != replicas {
+		deploymentObj := deployment.DeepCopy()
+		deployment.Spec.Replicas = ptr.To(replicas)
+		if err := r.Patch(ctx, deployment, client.MergeFrom(deploymentObj)); err != nil {
+			return false, "", fmt.Errorf("error scaling up deployment %s: %v", deployment.Name, err)
+		}
+	}
+
+	if restore.Status.PausedConfiguration && config.Spec.Paused {
+		configObj := config.DeepCopy()
+		config.Spec.Paused = false
+		if err := r.Patch(ctx, config, client.MergeFrom(configObj)); err != nil {
+			return false, "", fmt.Errorf("error resuming configuration %s: %v", config.Name, err)
+		}
+		restore.Status.PausedConfiguration = false
+		r.EventRecorder.Event(config, corev1.EventTypeNormal, "RestoreResumed", fmt.Sprintf("configuration resumed by restore %s", restore.Name))
+	}
+	return true, fmt.Sprintf("scaled up ovn-central to %d replicas", replicas), nil
+}
+
+// verifyLeaders waits for both databases to elect a leader with all master nodes as raft members
+func (r *OVNRestoreReconciler) verifyLeaders(ctx context.Context, config *kubeovniov1.Configuration) (bool, string, error) {
+	var leaders []string
+	for _, db := range restoreDatabases {
+		result, err := executeOVNCentralCommand(ctx, db.statusCheck, db.leaderLabel, r.Client, r.CommandRunner, r.Namespace)
+		if err != nil {
+			return false, fmt.Sprintf("waiting for %s leader: %v", db.name, err), nil
+		}
+		status, err := ovsdb.ParseClusterStatus(result)
+		if err != nil {
+			return false, fmt.Sprintf("waiting for %s leader: %v", db.name, err), nil
+		}
+		ovsdb.CompareMembers(status, config.Status.MatchingNodeAddresses)
+		if status.Role != "leader" || len(status.MissingAddresses) != 0 {
+			return false, fmt.Sprintf("waiting for %s members to join, role %s, missing members: [%s]",
+				db.name, status.Role, strings.Join(status.MissingAddresses, ",")), nil
+		}
+		leaders = append(leaders, fmt.Sprintf("%s leader %s", db.name, status.Address))
+	}
+	return true, strings.Join(leaders, ", "), nil
+}
+
+// fetchSnapshots returns the decompressed snapshot of each database in the backup, after verifying their checksums
+func (r *OVNRestoreReconciler) fetchSnapshots(ctx context.Context, restore *kubeovniov1.OVNRestore, config *kubeovniov1.Configuration) (snapshots map[string][]byte, err error) {
+	ovnBackup := &kubeovniov1.OVNBackup{}
+	if err := r.Get(ctx, types.NamespacedName{Name: restore.Spec.BackupName, Namespace: restore.Namespace}, ovnBackup); err != nil {
+		return nil, fmt.Errorf("error fetching backup %s: %v", restore.Spec.BackupName, err)
+	}
+	if ovnBackup.Status.Phase != kubeovniov1.BackupPhaseCompleted {
+		return nil, fmt.Errorf("backup %s is in phase %s, expected %s", ovnBackup.Name, ovnBackup.Status.Phase, kubeovniov1.BackupPhaseCompleted)
+	}
+
+	store, err := backup.NewStore(ctx, ovnBackup.Spec.Target, backupStoreOptions(r.Client, r.RestConfig, r.Namespace, config))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := store.Close(ctx); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	snapshots = make(map[string][]byte)
+	for _, db := range restoreDatabases {
+		idx := slices.IndexFunc(ovnBackup.Status.Artifacts, func(a kubeovniov1.BackupArtifact) bool {
+			return a.Database == db.name
+		})
+		if idx == -1 {
+			return nil, fmt.Errorf("backup %s has no snapshot of %s", ovnBackup.Name, db.name)
+		}
+		artifact := ovnBackup.Status.Artifacts[idx]
+		data, err := store.Get(ctx, artifact.Location)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching snapshot of %s: %v", db.name, err)
+		}
+		snapshot, err := backup.Decompress(data, artifact.Checksum)
+		if err != nil {
+			return nil, fmt.Errorf("error verifying snapshot of %s: %v", db.name, err)
+		}
+		snapshots[db.name] = snapshot
+	}
+	return snapshots, nil
+}
+
+// masterNodes returns the nodes matching the master node label with an internal ip, sorted by name
+func masterNodes(ctx context.Context, k8sClient client.Client, config *kubeovniov1.Configuration) ([]corev1.Node, error) {
+	set, err := labels.ConvertSelectorToLabelsMap(config.Spec.MasterNodesLabel)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing label selector %s: %v", config.Spec.MasterNodesLabel, err)
+	}
+	nodeList := &corev1.NodeList{}
+	if err := k8sClient.List(ctx, nodeList, client.MatchingLabels(set)); err != nil {
+		return nil, fmt.Errorf("error listing nodes: %v", err)
+	}
+
+	nodes := slices.DeleteFunc(nodeList.Items, func(node corev1.Node) bool {
+		return nodeInternalIP(node) == ""
+	})
+	if len(nodes) == 0 {
+		return nil, errors.New("no master nodes found")
+	}
+	slices.SortFunc(nodes, func(a, b corev1.Node) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return nodes, nil
+}
+
+// runOnNode runs fn with a pod mounting the ovn directory of a node, and removes the pod once fn returns
+func (r *OVNRestoreReconciler) runOnNode(ctx context.Context, config *kubeovniov1.Configuration, nodeName string, fn func(runner *backup.PodRunner) error) error {
+	ovnDir := config.Spec.OVNDir
+	if ovnDir == "" {
+		ovnDir = defaultOVNDir
+	}
+	runner := backup.NewHostPod(restorePodName(nodeName), nodeName, ovnDir, backupStoreOptions(r.Client, r.RestConfig, r.Namespace, config))
+	err := fn(runner)
+	if closeErr := runner.Close(ctx); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (r *OVNRestoreReconciler) failRestore(restore *kubeovniov1.OVNRestore, step *kubeovniov1.RestoreStep, err error) {
+	now := metav1.Now()
+	step.Status = kubeovniov1.RestorePhaseFailed
+	step.Message = err.Error()
+	step.CompletionTime = &now
+	restore.Status.Phase = kubeovniov1.RestorePhaseFailed
+	restore.Status.Message = fmt.Sprintf("step %s failed: %v", step.Name, err)
+	// the configuration is left paused, as ovn-central needs manual recovery once databases have been moved aside
+	if restore.Status.PausedConfiguration {
+		restore.Status.Message += ", configuration remains paused for manual recovery"
+	}
+	restore.Status.CompletionTime = &now
+	r.EventRecorder.Event(restore, corev1.EventTypeWarning, "RestoreFailed", restore.Status.Message)
+}
+
+// restorePodName is the name of the pod mounting the ovn directory of a node during a restore
+func restorePodName(nodeName string) string {
+	return backup.PodName("ovn-restore-", nodeName)
+}
@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/templates"
+)
+
+// ignoredPlanFields are updated by the apiserver on every apply and do not reflect a change to the object
+var ignoredPlanFields = []string{"metadata.managedFields", "metadata.resourceVersion", "metadata.generation"}
+
+// planObject runs a server side dry run apply of the object and compares the result with the existing object
+// to identify the fields which would be changed. The cluster is not modified
+func (r *ConfigurationReconciler) planObject(ctx context.Context, obj client.Object) (kubeovniov1.ObjectPlan, error) {
+	var err error
+	unstructuredObj := &unstructured.Unstructured{}
+	unstructuredObj.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return kubeovniov1.ObjectPlan{}, fmt.Errorf("error convering new object %s to unstructured object %v", obj.GetName(), err)
+	}
+
+	gvk := unstructuredObj.GroupVersionKind()
+	objPlan := kubeovniov1.ObjectPlan{
+		GVK: kubeovniov1.GroupVersionKind{
+			Group:   gvk.Group,
+			Version: gvk.Version,
+			Kind:    gvk.Kind,
+		},
+		Namespace: unstructuredObj.GetNamespace(),
+		Name:      unstructuredObj.GetName(),
+	}
+
+	existingObj, err := r.fetchExistingObject(ctx, unstructuredObj)
+	if err != nil {
+		return objPlan, err
+	}
+
+	// a dry run of new objects is skipped, as they may depend on crds which have not been applied yet
+	if existingObj == nil {
+		objPlan.Action = kubeovniov1.PlanActionCreate
+		return objPlan, nil
+	}
+
+	if isUnmanaged(existingObj) {
+		objPlan.Action = kubeovniov1.PlanActionUnmanaged
+		return objPlan, nil
+	}
+
+	if err := r.Patch(ctx, unstructuredObj, client.Apply, client.ForceOwnership, client.FieldOwner("kubeovn-operator"), client.DryRunAll); err != nil {
+		return objPlan, fmt.Errorf("error during dry run apply: %w", err)
+	}
+
+	existing, err := runtime.DefaultUnstructuredConverter.ToUnstructured(existingObj)
+	if err != nil {
+		return objPlan, fmt.Errorf("error converting existing object %s to unstructured object %v", existingObj.GetName(), err)
+	}
+	objPlan.ChangedFields = diffFieldPaths(existing, unstructuredObj.Object, "")
+	if len(objPlan.ChangedFields) == 0 {
+		objPlan.Action = kubeovniov1.PlanActionUnchanged
+	} else {
+		objPlan.Action = kubeovniov1.PlanActionChange
+	}
+	return objPlan, nil
+}
+
+// planPhases renders objects for all phases and records the changes a server side apply would make.
+// phases are not gated on health, as no objects are modified
+func (r *ConfigurationReconciler) planPhases(ctx context.Context, config *kubeovniov1.Configuration, fakeNSObj *corev1.Namespace, version string, caCert string) error {
+	tlsSecret, tlsChecksum, err := r.ovnTLSSecret(ctx, config, true)
+	if err != nil {
+		return err
+	}
+
+	var plan []kubeovniov1.ObjectPlan
+	for _, phase := range templates.OrderedPhases {
+		objs, err := r.renderPhase(config, phase, fakeNSObj, version, caCert, tlsSecret, tlsChecksum)
+		// a partial plan would report objects in later phases as deleted, so no plan is recorded until
+		// cert-manager is installed
+		if errors.Is(err, errCertManagerNotInstalled) {
+			config.Status.PhaseMessage = err.Error()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, obj := range objs {
+			objPlan, err := r.planObject(ctx, obj)
+			if err != nil {
+				return fmt.Errorf("error planning object %s/%s: %v", obj.GetNamespace(), obj.GetName(), err)
+			}
+			plan = append(plan, objPlan)
+		}
+	}
+	r.recordPlan(config, plan)
+	return nil
+}
+
+// recordPlan adds objects which would be pruned to the plan and records it in the configuration status
+func (r *ConfigurationReconciler) recordPlan(config *kubeovniov1.Configuration, plan []kubeovniov1.ObjectPlan) {
+	if config.Spec.PruneMode != kubeovniov1.PruneModeReportOnly {
+		current := make([]kubeovniov1.ObjectReference, 0, len(plan))
+		for _, v := range plan {
+			current = append(current, kubeovniov1.ObjectReference{GVK: v.GVK, Namespace: v.Namespace, Name: v.Name})
+		}
+		previousObjects := slices.Concat(config.Status.ManagedObjects, config.Status.OrphanedObjects)
+		for _, ref := range findOrphanedObjects(previousObjects, current) {
+			plan = append(plan, kubeovniov1.ObjectPlan{GVK: ref.GVK, Namespace: ref.Namespace, Name: ref.Name, Action: kubeovniov1.PlanActionDelete})
+		}
+	}
+
+	sort.SliceStable(plan, func(i, j int) bool {
+		return objectPlanKey(plan[i]) < objectPlanKey(plan[j])
+	})
+	config.Status.Plan = plan
+
+	message := planSummary(plan)
+	setConditionIfChanged(config, kubeovniov1.PlannedCondition, metav1.ConditionTrue, message, kubeovniov1.PlanGeneratedReason)
+	r.Log.WithValues("name", config.Name).Info("generated plan", "summary", message)
+}
+
+// planSummary returns a count of objects for each action in the plan
+func planSummary(plan []kubeovniov1.ObjectPlan) string {
+	counts := make(map[string]int)
+	for _, v := range plan {
+		counts[v.Action]++
+	}
+	actions := []string{kubeovniov1.PlanActionCreate, kubeovniov1.PlanActionChange, kubeovniov1.PlanActionDelete,
+		kubeovniov1.PlanActionUnchanged, kubeovniov1.PlanActionUnmanaged}
+	summary := make([]string, 0, len(actions))
+	for _, action := range actions {
+		summary = append(summary, fmt.Sprintf("%d %s", counts[action], strings.ToLower(action)))
+	}
+	return strings.Join(summary, ", ")
+}
+
+func objectPlanKey(plan kubeovniov1.ObjectPlan) string {
+	return objectReferenceKey(kubeovniov1.ObjectReference{GVK: plan.GVK, Namespace: plan.Namespace, Name: plan.Name})
+}
+
+// diffFieldPaths recursively compares two unstructured objects and returns the paths of all fields which differ.
+// lists of equal length are compared element by element, otherwise the list itself is reported as changed
+func diffFieldPaths(existing, desired any, path string) []string {
+	if slices.Contains(ignoredPlanFields, path) {
+		return nil
+	}
+
+	switch desiredValue := desired.(type) {
+	case map[string]any:
+		existingValue, ok := existing.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(desiredValue)+len(existingValue))
+		for k := range desiredValue {
+			keys = append(keys, k)
+		}
+		for k := range existingValue {
+			if _, ok := desiredValue[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		var changed []string
+		for _, k := range keys {
+			changed = append(changed, diffFieldPaths(existingValue[k], desiredValue[k], joinFieldPath(path, k))...)
+		}
+		return changed
+	case []any:
+		existingValue, ok := existing.([]any)
+		if !ok || len(existingValue) != len(desiredValue) {
+			break
+		}
+		var changed []string
+		for i := range desiredValue {
+			changed = append(changed, diffFieldPaths(existingValue[i], desiredValue[i], fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return changed
+	}
+
+	if reflect.DeepEqual(existing, desired) {
+		return nil
+	}
+	return []string{path}
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
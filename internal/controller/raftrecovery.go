@@ -0,0 +1,448 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/backup"
+	"github.com/harvester/kubeovn-operator/internal/executor"
+	"github.com/harvester/kubeovn-operator/internal/ovsdb"
+	"github.com/harvester/kubeovn-operator/internal/render"
+)
+
+const (
+	defaultRaftRecoveryGracePeriod = 10 * time.Minute
+	// raftRecoveryRequeueInterval is how often a recovery in progress is rechecked
+	raftRecoveryRequeueInterval = 30 * time.Second
+	// raftRejoinTimeout is how long a member has to rejoin the cluster after ovn-central is restarted. This needs
+	// to be longer than the healthcheck interval, as membership is read from the status recorded by the healthcheck
+	raftRejoinTimeout = 30 * time.Minute
+	// corruptDatabaseLogLines is the number of lines of the previous ovn-central container logs searched for
+	// corrupt database signatures
+	corruptDatabaseLogLines = 200
+	raftRecoveryJobPrefix   = "ovn-raft-recovery-"
+	raftRecoveryNodeLabel   = "kubeovn.io/raft-recovery-node"
+)
+
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+
+// reconcileRaftRecovery runs the kube-ovn rebuild member procedure for master nodes whose raft members are
+// disconnected from the leader, or whose ovn-central pod is crash looping because of a corrupt database.
+// Only one node is recovered at a time, and recoveries in progress are rechecked on every reconcile. Returns
+// true while a recovery is in progress
+func (r *HealthCheckReconciler) reconcileRaftRecovery(ctx context.Context, config *kubeovniov1.Configuration) (bool, error) {
+	policy := config.Spec.Maintenance.AutoRecoverRaftMembers
+	if !policy.Enabled {
+		config.Status.RaftRecovery = nil
+		return false, nil
+	}
+	// recovery is not safe during manual intervention or while databases are being restored
+	if config.Spec.Paused {
+		return false, nil
+	}
+
+	nodes, err := masterNodes(ctx, r.Client, config)
+	if err != nil {
+		return false, err
+	}
+	detected, err := r.detectBrokenMembers(ctx, config, nodes)
+	if err != nil {
+		return false, err
+	}
+	now := metav1.Now()
+	recoveries := ovsdb.MergeRaftRecoveries(config.Status.RaftRecovery, detected, now)
+	for _, recovery := range recoveries {
+		if recovery.Phase == kubeovniov1.RaftRecoveryPhaseDetected && !slices.ContainsFunc(config.Status.RaftRecovery, func(p kubeovniov1.RaftMemberRecoveryStatus) bool {
+			return p.Node == recovery.Node
+		}) {
+			r.EventRecorder.Event(config, corev1.EventTypeWarning, "RaftMemberBroken", fmt.Sprintf("%s raft members on node %s: %s",
+				recovery.Reason, recovery.Node, strings.Join(recovery.Databases, ",")))
+		}
+	}
+
+	gracePeriod := durationOrDefault(policy.GracePeriod, defaultRaftRecoveryGracePeriod)
+	approved := approvedNodes(config)
+	var consumedApprovals []string
+	inProgress := false
+	for i := range recoveries {
+		recovery := &recoveries[i]
+		switch recovery.Phase {
+		case kubeovniov1.RaftRecoveryPhaseDetected, kubeovniov1.RaftRecoveryPhaseAwaitingApproval:
+			if recovery.Reason == kubeovniov1.RaftRecoveryDisconnectedReason && now.Time.Before(recovery.DetectedTime.Add(gracePeriod)) {
+				continue
+			}
+			if slices.ContainsFunc(recoveries, func(other kubeovniov1.RaftMemberRecoveryStatus) bool {
+				return other.Phase == kubeovniov1.RaftRecoveryPhaseRecovering || other.Phase == kubeovniov1.RaftRecoveryPhaseRejoining
+			}) {
+				continue
+			}
+			if policy.RequireApproval && !slices.Contains(approved, recovery.Node) {
+				if recovery.Phase != kubeovniov1.RaftRecoveryPhaseAwaitingApproval {
+					setRaftRecoveryPhase(recovery, kubeovniov1.RaftRecoveryPhaseAwaitingApproval,
+						fmt.Sprintf("waiting for node %s to be listed in annotation %s", recovery.Node, kubeovniov1.RaftRecoveryApprovalAnnotation))
+					r.EventRecorder.Event(config, corev1.EventTypeNormal, "RaftRecoveryAwaitingApproval", recovery.Message)
+				}
+				continue
+			}
+			if slices.Contains(approved, recovery.Node) {
+				consumedApprovals = append(consumedApprovals, recovery.Node)
+			}
+			if err := r.startRaftRecovery(ctx, config, recovery); err != nil {
+				r.Log.Error(err, "error starting raft member recovery", "node", recovery.Node)
+				recovery.Message = err.Error()
+				continue
+			}
+			r.EventRecorder.Event(config, corev1.EventTypeNormal, "RaftRecoveryStarted", recovery.Message)
+		case kubeovniov1.RaftRecoveryPhaseRecovering:
+			r.checkRaftRecoveryJob(ctx, config, recovery)
+		case kubeovniov1.RaftRecoveryPhaseRejoining:
+			if r.memberRejoined(config, recovery) {
+				r.EventRecorder.Event(config, corev1.EventTypeNormal, "RaftMemberRecovered",
+					fmt.Sprintf("raft members on node %s rejoined the cluster", recovery.Node))
+				recovery.Phase = ""
+				continue
+			}
+			if now.Time.After(recovery.LastTransitionTime.Add(raftRejoinTimeout)) {
+				setRaftRecoveryPhase(recovery, kubeovniov1.RaftRecoveryPhaseFailed,
+					fmt.Sprintf("raft members on node %s did not rejoin the cluster within %s", recovery.Node, raftRejoinTimeout))
+				r.EventRecorder.Event(config, corev1.EventTypeWarning, "RaftRecoveryFailed", recovery.Message)
+			}
+		}
+		if recovery.Phase == kubeovniov1.RaftRecoveryPhaseRecovering || recovery.Phase == kubeovniov1.RaftRecoveryPhaseRejoining {
+			inProgress = true
+		}
+	}
+
+	config.Status.RaftRecovery = slices.DeleteFunc(recoveries, func(recovery kubeovniov1.RaftMemberRecoveryStatus) bool {
+		return recovery.Phase == ""
+	})
+	// the status recording started recoveries is patched even if the annotation cannot be updated, otherwise
+	// the members would be kicked again on the next reconcile
+	if err := r.removeApprovals(ctx, config, consumedApprovals); err != nil {
+		r.Log.Error(err, "error removing raft recovery approvals")
+	}
+	return inProgress, nil
+}
+
+// detectBrokenMembers returns the master nodes with members disconnected from the leader in the status recorded
+// by the healthcheck, or with an ovn-central pod crash looping with a corrupt database signature in its logs
+func (r *HealthCheckReconciler) detectBrokenMembers(ctx context.Context, config *kubeovniov1.Configuration, nodes []corev1.Node) ([]kubeovniov1.RaftMemberRecoveryStatus, error) {
+	var detected []kubeovniov1.RaftMemberRecoveryStatus
+	add := func(node corev1.Node, database string, reason string) {
+		idx := slices.IndexFunc(detected, func(d kubeovniov1.RaftMemberRecoveryStatus) bool {
+			return d.Node == node.Name
+		})
+		if idx == -1 {
+			detected = append(detected, kubeovniov1.RaftMemberRecoveryStatus{Node: node.Name, Address: nodeInternalIP(node)})
+			idx = len(detected) - 1
+		}
+		if !slices.Contains(detected[idx].Databases, database) {
+			detected[idx].Databases = append(detected[idx].Databases, database)
+			slices.Sort(detected[idx].Databases)
+		}
+		// a corrupt database is the more specific reason, and is recovered without a grace period
+		if detected[idx].Reason != kubeovniov1.RaftRecoveryCorruptDatabaseReason {
+			detected[idx].Reason = reason
+		}
+	}
+
+	for _, db := range restoreDatabases {
+		status := databaseStatus(config, db.name)
+		if status == nil {
+			continue
+		}
+		for _, member := range status.Members {
+			if member.Connection != kubeovniov1.RaftConnectionDisconnected {
+				continue
+			}
+			idx := slices.IndexFunc(nodes, func(node corev1.Node) bool {
+				return nodeInternalIP(node) == ovsdb.AddressIP(member.Address)
+			})
+			if idx != -1 {
+				add(nodes[idx], db.name, kubeovniov1.RaftRecoveryDisconnectedReason)
+			}
+		}
+	}
+
+	pods, err := podList(ctx, kubeovniov1.OVNCentralLabel, r.Client, r.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching ovn-central pods: %v", err)
+	}
+	for _, pod := range pods.Items {
+		idx := slices.IndexFunc(nodes, func(node corev1.Node) bool {
+			return node.Name == pod.Spec.NodeName
+		})
+		if idx == -1 || !crashLooping(pod) {
+			continue
+		}
+		logs, err := r.previousLogs(ctx, pod)
+		if err != nil {
+			r.Log.Error(err, "error fetching ovn-central logs", "pod", pod.GetName())
+			continue
+		}
+		for _, database := range ovsdb.CorruptDatabases(logs) {
+			add(nodes[idx], database, kubeovniov1.RaftRecoveryCorruptDatabaseReason)
+		}
+	}
+	return detected, nil
+}
+
+// startRaftRecovery kicks the members on the node from the clusters by the server ids recorded by the healthcheck,
+// and creates the job moving the database files on the node aside
+func (r *HealthCheckReconciler) startRaftRecovery(ctx context.Context, config *kubeovniov1.Configuration, recovery *kubeovniov1.RaftMemberRecoveryStatus) error {
+	var files []string
+	for _, db := range restoreDatabases {
+		if !slices.Contains(recovery.Databases, db.name) {
+			continue
+		}
+		result, err := kickRaftMember(ctx, r.Client, r.CommandRunner, r.Namespace, db, databaseStatus(config, db.name), recovery.Address)
+		if err != nil && !errors.Is(err, ovsdb.ErrMemberNotFound) {
+			return err
+		}
+		if err != nil {
+			result = err.Error()
+		}
+		r.Log.WithValues("node", recovery.Node, "database", db.name).Info(result)
+		files = append(files, db.file)
+	}
+
+	job, err := newMoveDatabasesJob(config, r.Namespace, raftRecoveryJobPrefix, recovery.Node, files, fmt.Sprintf("broken-%d", time.Now().Unix()))
+	if err != nil {
+		return err
+	}
+	if err := r.Create(ctx, job); err != nil {
+		return fmt.Errorf("error creating raft recovery job for node %s: %v", recovery.Node, err)
+	}
+	recovery.JobName = job.GetName()
+	setRaftRecoveryPhase(recovery, kubeovniov1.RaftRecoveryPhaseRecovering,
+		fmt.Sprintf("kicked members of %s on node %s, moving database files aside using job %s", strings.Join(recovery.Databases, ","), recovery.Node, job.GetName()))
+	return nil
+}
+
+// checkRaftRecoveryJob restarts ovn-central on the node once the job has moved the database files aside, so that
+// ovn-central joins the cluster again with empty databases
+func (r *HealthCheckReconciler) checkRaftRecoveryJob(ctx context.Context, config *kubeovniov1.Configuration, recovery *kubeovniov1.RaftMemberRecoveryStatus) {
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, types.NamespacedName{Name: recovery.JobName, Namespace: r.Namespace}, job); err != nil {
+		if apierrors.IsNotFound(err) {
+			setRaftRecoveryPhase(recovery, kubeovniov1.RaftRecoveryPhaseFailed, fmt.Sprintf("raft recovery job %s not found", recovery.JobName))
+			r.EventRecorder.Event(config, corev1.EventTypeWarning, "RaftRecoveryFailed", recovery.Message)
+		} else {
+			r.Log.Error(err, "error fetching raft recovery job", "job", recovery.JobName)
+		}
+		return
+	}
+
+	switch {
+	case job.Status.Succeeded > 0:
+		pods, err := podList(ctx, kubeovniov1.OVNCentralLabel, r.Client, r.Namespace)
+		if err != nil {
+			r.Log.Error(err, "error fetching ovn-central pods")
+			return
+		}
+		for _, pod := range pods.Items {
+			if pod.Spec.NodeName != recovery.Node {
+				continue
+			}
+			if err := r.Delete(ctx, &pod); err != nil && !apierrors.IsNotFound(err) {
+				r.Log.Error(err, "error restarting ovn-central", "pod", pod.GetName())
+				return
+			}
+		}
+		setRaftRecoveryPhase(recovery, kubeovniov1.RaftRecoveryPhaseRejoining,
+			fmt.Sprintf("restarted ovn-central on node %s, waiting for members to rejoin", recovery.Node))
+	case job.Status.Failed > 0 && job.Status.Active == 0:
+		setRaftRecoveryPhase(recovery, kubeovniov1.RaftRecoveryPhaseFailed,
+			fmt.Sprintf("raft recovery job %s failed, database files on node %s need to be removed manually", job.GetName(), recovery.Node))
+		r.EventRecorder.Event(config, corev1.EventTypeWarning, "RaftRecoveryFailed", recovery.Message)
+	}
+}
+
+// memberRejoined checks the member on the node is connected to the leader of each recovered database
+func (r *HealthCheckReconciler) memberRejoined(config *kubeovniov1.Configuration, recovery *kubeovniov1.RaftMemberRecoveryStatus) bool {
+	for _, database := range recovery.Databases {
+		status := databaseStatus(config, database)
+		if status == nil || status.LastUpdateTime.Before(&recovery.LastTransitionTime) {
+			return false
+		}
+		if !slices.ContainsFunc(status.Members, func(member kubeovniov1.OVNDatabaseMemberStatus) bool {
+			return ovsdb.AddressIP(member.Address) == recovery.Address && member.Connection != kubeovniov1.RaftConnectionDisconnected
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// newMoveDatabasesJob returns a job moving the database files in the ovn directory of a node aside, so
+// ovn-central on the node joins the clusters again with empty databases when it is restarted
+func newMoveDatabasesJob(config *kubeovniov1.Configuration, namespace string, prefix string, nodeName string, files []string, suffix string) (*batchv1.Job, error) {
+	script, err := render.GenerateMoveDatabasesScript(backup.HostMountPath, files, suffix)
+	if err != nil {
+		return nil, err
+	}
+	ovnDir := config.Spec.OVNDir
+	if ovnDir == "" {
+		ovnDir = defaultOVNDir
+	}
+	var pullSecrets []corev1.LocalObjectReference
+	for _, name := range config.Spec.Global.Registry.ImagePullSecrets {
+		pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: name})
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: prefix,
+			Namespace:    namespace,
+			Labels:       map[string]string{raftRecoveryNodeLabel: nodeName},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            ptr.To(int32(2)),
+			TTLSecondsAfterFinished: ptr.To(int32(raftRejoinTimeout.Seconds())),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:    corev1.RestartPolicyNever,
+					NodeName:         nodeName,
+					Tolerations:      []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+					ImagePullSecrets: pullSecrets,
+					Containers: []corev1.Container{
+						{
+							Name:    "recovery",
+							Image:   config.Status.Images[render.OVNCentralImageKey],
+							Command: []string{"/bin/sh", "-c", script},
+							SecurityContext: &corev1.SecurityContext{
+								Privileged: ptr.To(true),
+								RunAsUser:  ptr.To(int64(0)),
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "host-config-ovn", MountPath: backup.HostMountPath},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "host-config-ovn",
+							VolumeSource: corev1.VolumeSource{
+								HostPath: &corev1.HostPathVolumeSource{Path: ovnDir},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// kickRaftMember kicks the member of a database whose ip is exactly address, using the server id found in the
+// cluster status reported by the leader. ovsdb.ErrMemberNotFound is returned if the address is not a member
+func kickRaftMember(ctx context.Context, k8sClient client.Client, runner executor.CommandRunner, namespace string, db restoreDatabase, status *kubeovniov1.OVNDatabaseStatus, address string) (string, error) {
+	if status == nil {
+		return "", fmt.Errorf("no %s cluster status found to kick member %s", db.name, address)
+	}
+	serverID, err := ovsdb.MemberServerID(status, address)
+	if err != nil {
+		return "", err
+	}
+	var script string
+	if db.name == kubeovniov1.NBDatabase {
+		script, err = render.GenerateNorthBoundKickScript(serverID)
+	} else {
+		script, err = render.GenerateSouthBoundKickScript(serverID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error generating %s kick script for server id %s: %v", db.name, serverID, err)
+	}
+	result, err := executeOVNCentralCommand(ctx, script, db.leaderLabel, k8sClient, runner, namespace)
+	if err != nil {
+		return "", fmt.Errorf("error kicking %s member %s with address %s from the cluster %s: %v", db.name, serverID, address, string(result), err)
+	}
+	return fmt.Sprintf("kicked %s member %s with address %s: %s", db.name, serverID, address, string(result)), nil
+}
+
+// previousLogs returns the last lines logged by the previous ovn-central container of a pod
+func (r *HealthCheckReconciler) previousLogs(ctx context.Context, pod corev1.Pod) ([]byte, error) {
+	clientset, err := kubernetes.NewForConfig(r.RestConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error generating clientset: %v", err)
+	}
+	return clientset.CoreV1().Pods(pod.GetNamespace()).GetLogs(pod.GetName(), &corev1.PodLogOptions{
+		Container: kubeovniov1.OVNCentralContainerName,
+		Previous:  true,
+		TailLines: ptr.To(int64(corruptDatabaseLogLines)),
+	}).DoRaw(ctx)
+}
+
+// removeApprovals removes nodes whose recovery has started from the approval annotation, so a later failure
+// on the same node needs to be approved again
+func (r *HealthCheckReconciler) removeApprovals(ctx context.Context, config *kubeovniov1.Configuration, nodes []string) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	remaining := slices.DeleteFunc(approvedNodes(config), func(node string) bool {
+		return slices.Contains(nodes, node)
+	})
+	// the annotation is updated using a copy, so the status computed by the healthcheck is not overwritten
+	// by the response of the patch
+	annotated := config.DeepCopy()
+	patched := config.DeepCopy()
+	if len(remaining) == 0 {
+		delete(patched.Annotations, kubeovniov1.RaftRecoveryApprovalAnnotation)
+	} else {
+		patched.Annotations[kubeovniov1.RaftRecoveryApprovalAnnotation] = strings.Join(remaining, ",")
+	}
+	if err := r.Patch(ctx, patched, client.MergeFrom(annotated)); err != nil {
+		return fmt.Errorf("error updating annotation %s: %v", kubeovniov1.RaftRecoveryApprovalAnnotation, err)
+	}
+	return nil
+}
+
+// approvedNodes returns the nodes listed in the approval annotation
+func approvedNodes(config *kubeovniov1.Configuration) []string {
+	var nodes []string
+	for _, node := range strings.Split(config.GetAnnotations()[kubeovniov1.RaftRecoveryApprovalAnnotation], ",") {
+		if node = strings.TrimSpace(node); node != "" {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+func setRaftRecoveryPhase(recovery *kubeovniov1.RaftMemberRecoveryStatus, phase string, message string) {
+	recovery.Phase = phase
+	recovery.Message = message
+	recovery.LastTransitionTime = metav1.Now()
+}
+
+// crashLooping returns true when the ovn-central container of a pod is waiting to be restarted after crashing
+func crashLooping(pod corev1.Pod) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == kubeovniov1.OVNCentralContainerName && status.State.Waiting != nil &&
+			status.State.Waiting.Reason == "CrashLoopBackOff" {
+			return true
+		}
+	}
+	return false
+}
+
+// databaseStatus returns the raft status of a database recorded by the healthcheck
+func databaseStatus(config *kubeovniov1.Configuration, database string) *kubeovniov1.OVNDatabaseStatus {
+	if database == kubeovniov1.NBDatabase {
+		return config.Status.OVNDatabases.Northbound
+	}
+	return config.Status.OVNDatabases.Southbound
+}
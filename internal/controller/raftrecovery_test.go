@@ -0,0 +1,321 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/render"
+)
+
+const testMasterNodesLabel = "node-role.kubernetes.io/control-plane=true"
+
+// fakeExec is the CommandRunner used in tests, recording the scripts run and returning the output configured
+// for each script
+type fakeExec struct {
+	scripts []string
+	outputs map[string]string
+	err     error
+}
+
+func newFakeExec() *fakeExec {
+	return &fakeExec{outputs: make(map[string]string)}
+}
+
+func (f *fakeExec) Run(ctx context.Context, pod *corev1.Pod, containerName string, script string) ([]byte, error) {
+	f.scripts = append(f.scripts, script)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []byte(f.outputs[script]), nil
+}
+
+func (f *fakeExec) Stream(ctx context.Context, pod *corev1.Pod, containerName string, script string, output io.Writer) ([]byte, error) {
+	result, err := f.Run(ctx, pod, containerName, script)
+	if err != nil {
+		return nil, err
+	}
+	_, err = output.Write(result)
+	return nil, err
+}
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	testScheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	require.NoError(t, kubeovniov1.AddToScheme(testScheme))
+	return testScheme
+}
+
+func newTestMasterNode(name string, address string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"node-role.kubernetes.io/control-plane": "true"}},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: address}},
+		},
+	}
+}
+
+// newTestOVNCentralPod returns an ovn-central pod, which is labelled as the leader of both databases if leader is set
+func newTestOVNCentralPod(name string, nodeName string, leader bool) *corev1.Pod {
+	labels := map[string]string{"app": "ovn-central"}
+	if leader {
+		labels["ovn-nb-leader"] = "true"
+		labels["ovn-sb-leader"] = "true"
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: defaultKubeovnNamespace, Labels: labels},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func newTestConfiguration() *kubeovniov1.Configuration {
+	return &kubeovniov1.Configuration{
+		ObjectMeta: metav1.ObjectMeta{Name: kubeovniov1.DefaultConfigurationName, Namespace: defaultKubeovnNamespace},
+		Spec:       kubeovniov1.ConfigurationSpec{MasterNodesLabel: testMasterNodesLabel},
+		Status: kubeovniov1.ConfigurationStatus{
+			Images: map[string]string{render.OVNCentralImageKey: "docker.io/kubeovn/kube-ovn:v1.14.0"},
+		},
+	}
+}
+
+// testRaftObjects returns three master nodes, where 10.0.0.2 is a prefix of the address of node3, and an
+// ovn-central pod on each node with the pod on node1 leading both databases
+func testRaftObjects() []client.Object {
+	return []client.Object{
+		newTestMasterNode("node1", "10.0.0.1"),
+		newTestMasterNode("node2", "10.0.0.2"),
+		newTestMasterNode("node3", "10.0.0.21"),
+		newTestOVNCentralPod("ovn-central-1", "node1", true),
+		newTestOVNCentralPod("ovn-central-2", "node2", false),
+		newTestOVNCentralPod("ovn-central-3", "node3", false),
+	}
+}
+
+// testDisconnectedStatus returns the status of a database reported by node1, where the member on node2 is
+// disconnected from the leader
+func testDisconnectedStatus(updated metav1.Time) *kubeovniov1.OVNDatabaseStatus {
+	return &kubeovniov1.OVNDatabaseStatus{
+		ServerID: "e2d5",
+		Role:     "leader",
+		Members: []kubeovniov1.OVNDatabaseMemberStatus{
+			{ServerID: "e2d5", Address: "tcp:[10.0.0.1]:6643", Connection: kubeovniov1.RaftConnectionSelf},
+			{ServerID: "a8b1", Address: "tcp:[10.0.0.2]:6643", Connection: kubeovniov1.RaftConnectionDisconnected},
+			{ServerID: "c9d0", Address: "tcp:[10.0.0.21]:6643", Connection: kubeovniov1.RaftConnectionConnected},
+		},
+		LastUpdateTime: updated,
+	}
+}
+
+func newRaftRecoveryTestReconciler(t *testing.T, config *kubeovniov1.Configuration, exec *fakeExec) (*HealthCheckReconciler, *record.FakeRecorder) {
+	recorder := record.NewFakeRecorder(100)
+	objs := append(testRaftObjects(), config)
+	return &HealthCheckReconciler{
+		Client: fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(objs...).
+			WithStatusSubresource(&kubeovniov1.Configuration{}, &batchv1.Job{}).Build(),
+		CommandRunner: exec,
+		EventRecorder: recorder,
+		Namespace:     defaultKubeovnNamespace,
+		Log:           logr.Discard(),
+	}, recorder
+}
+
+// newRaftRecoveryTestConfiguration returns a configuration with recovery of raft members requiring approval, and
+// a member on node2 detected as disconnected for longer than the grace period
+func newRaftRecoveryTestConfiguration() *kubeovniov1.Configuration {
+	config := newTestConfiguration()
+	config.Spec.Maintenance.AutoRecoverRaftMembers = kubeovniov1.RaftMemberRecoverySpec{Enabled: true, RequireApproval: true}
+	detected := metav1.NewTime(time.Now().Add(-time.Hour))
+	config.Status.OVNDatabases.Northbound = testDisconnectedStatus(metav1.Now())
+	config.Status.RaftRecovery = []kubeovniov1.RaftMemberRecoveryStatus{
+		{Node: "node2", Address: "10.0.0.2", Databases: []string{kubeovniov1.NBDatabase}, Reason: kubeovniov1.RaftRecoveryDisconnectedReason,
+			Phase: kubeovniov1.RaftRecoveryPhaseDetected, DetectedTime: detected, LastTransitionTime: detected},
+	}
+	return config
+}
+
+// reconcileRaftRecoveryStatus runs raft recovery against the stored configuration, and patches the resulting
+// status as the healthcheck does
+func reconcileRaftRecoveryStatus(t *testing.T, r *HealthCheckReconciler) (*kubeovniov1.Configuration, bool) {
+	ctx := context.TODO()
+	config := &kubeovniov1.Configuration{}
+	require.NoError(t, r.Get(ctx, types.NamespacedName{Name: kubeovniov1.DefaultConfigurationName, Namespace: defaultKubeovnNamespace}, config))
+	original := config.DeepCopy()
+	inProgress, err := r.reconcileRaftRecovery(ctx, config)
+	require.NoError(t, err)
+	require.NoError(t, r.Status().Patch(ctx, config, client.MergeFrom(original)))
+	require.NoError(t, r.Get(ctx, client.ObjectKeyFromObject(config), config))
+	return config, inProgress
+}
+
+func setJobStatus(t *testing.T, k8sClient client.Client, name string, status batchv1.JobStatus) {
+	job := &batchv1.Job{}
+	require.NoError(t, k8sClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: defaultKubeovnNamespace}, job))
+	job.Status = status
+	require.NoError(t, k8sClient.Status().Update(context.TODO(), job))
+}
+
+func requireEvent(t *testing.T, recorder *record.FakeRecorder, reason string) {
+	for {
+		select {
+		case event := <-recorder.Events:
+			if strings.Contains(event, reason) {
+				return
+			}
+		default:
+			t.Fatalf("expected event with reason %s", reason)
+		}
+	}
+}
+
+func Test_RaftRecoveryPhases(t *testing.T) {
+	assert := require.New(t)
+	exec := newFakeExec()
+	r, recorder := newRaftRecoveryTestReconciler(t, newRaftRecoveryTestConfiguration(), exec)
+
+	// members are not kicked until the node is approved
+	config, inProgress := reconcileRaftRecoveryStatus(t, r)
+	assert.False(inProgress)
+	assert.Len(config.Status.RaftRecovery, 1)
+	assert.Equal(kubeovniov1.RaftRecoveryPhaseAwaitingApproval, config.Status.RaftRecovery[0].Phase)
+	assert.Empty(exec.scripts)
+	requireEvent(t, recorder, "RaftRecoveryAwaitingApproval")
+
+	config.Annotations = map[string]string{kubeovniov1.RaftRecoveryApprovalAnnotation: "node2,node3"}
+	assert.NoError(r.Update(context.TODO(), config))
+
+	// the member is kicked by its exact server id, the job is created and the approval is consumed
+	config, inProgress = reconcileRaftRecoveryStatus(t, r)
+	assert.True(inProgress)
+	recovery := config.Status.RaftRecovery[0]
+	assert.Equal(kubeovniov1.RaftRecoveryPhaseRecovering, recovery.Phase)
+	assert.Equal([]string{"ovs-appctl -t /var/run/ovn/ovnnb_db.ctl cluster/kick OVN_Northbound a8b1"}, exec.scripts)
+	assert.Equal("node3", config.Annotations[kubeovniov1.RaftRecoveryApprovalAnnotation])
+	requireEvent(t, recorder, "RaftRecoveryStarted")
+
+	job := &batchv1.Job{}
+	assert.NoError(r.Get(context.TODO(), types.NamespacedName{Name: recovery.JobName, Namespace: defaultKubeovnNamespace}, job))
+	assert.Equal("node2", job.Spec.Template.Spec.NodeName)
+	assert.Contains(job.Spec.Template.Spec.Containers[0].Command[2], "ovnnb_db.db")
+	assert.NotContains(job.Spec.Template.Spec.Containers[0].Command[2], "ovnsb_db.db")
+
+	// nothing happens while the job is running
+	config, inProgress = reconcileRaftRecoveryStatus(t, r)
+	assert.True(inProgress)
+	assert.Equal(kubeovniov1.RaftRecoveryPhaseRecovering, config.Status.RaftRecovery[0].Phase)
+	assert.Len(exec.scripts, 1)
+
+	// ovn-central on the node is restarted once the job succeeds
+	setJobStatus(t, r.Client, recovery.JobName, batchv1.JobStatus{Succeeded: 1})
+	config, inProgress = reconcileRaftRecoveryStatus(t, r)
+	assert.True(inProgress)
+	assert.Equal(kubeovniov1.RaftRecoveryPhaseRejoining, config.Status.RaftRecovery[0].Phase)
+	err := r.Get(context.TODO(), types.NamespacedName{Name: "ovn-central-2", Namespace: defaultKubeovnNamespace}, &corev1.Pod{})
+	assert.True(apierrors.IsNotFound(err), "expected ovn-central on node2 to be deleted")
+	assert.NoError(r.Get(context.TODO(), types.NamespacedName{Name: "ovn-central-3", Namespace: defaultKubeovnNamespace}, &corev1.Pod{}))
+
+	// the recovery completes once the healthcheck reports the member as connected
+	status := testDisconnectedStatus(metav1.NewTime(time.Now().Add(time.Minute)))
+	status.Members[1].Connection = kubeovniov1.RaftConnectionConnected
+	config.Status.OVNDatabases.Northbound = status
+	assert.NoError(r.Status().Update(context.TODO(), config))
+	config, inProgress = reconcileRaftRecoveryStatus(t, r)
+	assert.False(inProgress)
+	assert.Empty(config.Status.RaftRecovery)
+	requireEvent(t, recorder, "RaftMemberRecovered")
+	assert.Len(exec.scripts, 1)
+}
+
+func Test_RaftRecoveryFailures(t *testing.T) {
+	assert := require.New(t)
+	exec := newFakeExec()
+	config := newRaftRecoveryTestConfiguration()
+	config.Spec.Maintenance.AutoRecoverRaftMembers.RequireApproval = false
+	r, recorder := newRaftRecoveryTestReconciler(t, config, exec)
+
+	// a failed kick is retried, and no job is created
+	exec.err = errors.New("connection refused")
+	config, inProgress := reconcileRaftRecoveryStatus(t, r)
+	assert.False(inProgress)
+	assert.Equal(kubeovniov1.RaftRecoveryPhaseDetected, config.Status.RaftRecovery[0].Phase)
+	assert.Contains(config.Status.RaftRecovery[0].Message, "connection refused")
+	jobs := &batchv1.JobList{}
+	assert.NoError(r.List(context.TODO(), jobs))
+	assert.Empty(jobs.Items)
+
+	// a failed job fails the recovery and leaves ovn-central running
+	exec.err = nil
+	config, _ = reconcileRaftRecoveryStatus(t, r)
+	assert.Equal(kubeovniov1.RaftRecoveryPhaseRecovering, config.Status.RaftRecovery[0].Phase)
+	setJobStatus(t, r.Client, config.Status.RaftRecovery[0].JobName, batchv1.JobStatus{Failed: 3})
+	config, inProgress = reconcileRaftRecoveryStatus(t, r)
+	assert.False(inProgress)
+	assert.Equal(kubeovniov1.RaftRecoveryPhaseFailed, config.Status.RaftRecovery[0].Phase)
+	requireEvent(t, recorder, "RaftRecoveryFailed")
+	assert.NoError(r.Get(context.TODO(), types.NamespacedName{Name: "ovn-central-2", Namespace: defaultKubeovnNamespace}, &corev1.Pod{}))
+
+	// a failed recovery is not retried while the member is still broken
+	kicks := len(exec.scripts)
+	config, _ = reconcileRaftRecoveryStatus(t, r)
+	assert.Equal(kubeovniov1.RaftRecoveryPhaseFailed, config.Status.RaftRecovery[0].Phase)
+	assert.Len(exec.scripts, kicks)
+}
+
+func Test_RaftRecoveryMissingJob(t *testing.T) {
+	assert := require.New(t)
+	exec := newFakeExec()
+	config := newRaftRecoveryTestConfiguration()
+	config.Status.RaftRecovery[0].Phase = kubeovniov1.RaftRecoveryPhaseRecovering
+	config.Status.RaftRecovery[0].JobName = "ovn-raft-recovery-missing"
+	r, recorder := newRaftRecoveryTestReconciler(t, config, exec)
+
+	config, inProgress := reconcileRaftRecoveryStatus(t, r)
+	assert.False(inProgress)
+	assert.Equal(kubeovniov1.RaftRecoveryPhaseFailed, config.Status.RaftRecovery[0].Phase)
+	requireEvent(t, recorder, "RaftRecoveryFailed")
+}
+
+func Test_RaftRecoveryRejoinTimeout(t *testing.T) {
+	assert := require.New(t)
+	exec := newFakeExec()
+	config := newRaftRecoveryTestConfiguration()
+	config.Status.RaftRecovery[0].Phase = kubeovniov1.RaftRecoveryPhaseRejoining
+	config.Status.RaftRecovery[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-raftRejoinTimeout - time.Minute))
+	r, recorder := newRaftRecoveryTestReconciler(t, config, exec)
+
+	config, inProgress := reconcileRaftRecoveryStatus(t, r)
+	assert.False(inProgress)
+	assert.Equal(kubeovniov1.RaftRecoveryPhaseFailed, config.Status.RaftRecovery[0].Phase)
+	requireEvent(t, recorder, "RaftRecoveryFailed")
+}
+
+func Test_RaftRecoveryPaused(t *testing.T) {
+	assert := require.New(t)
+	exec := newFakeExec()
+	config := newRaftRecoveryTestConfiguration()
+	config.Spec.Maintenance.AutoRecoverRaftMembers.RequireApproval = false
+	config.Spec.Paused = true
+	r, _ := newRaftRecoveryTestReconciler(t, config, exec)
+
+	config, inProgress := reconcileRaftRecoveryStatus(t, r)
+	assert.False(inProgress)
+	assert.Equal(kubeovniov1.RaftRecoveryPhaseDetected, config.Status.RaftRecovery[0].Phase)
+	assert.Empty(exec.scripts)
+}
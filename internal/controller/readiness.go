@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/templates"
+)
+
+// phaseRequeueInterval is how often the configuration is requeued while a phase is waiting to become healthy
+const phaseRequeueInterval = 15 * time.Second
+
+const certManagerGroup = "cert-manager.io"
+
+// phaseReady checks if all objects applied in a phase are healthy. The ovn-central phase
+// additionally waits for the northbound and southbound raft leaders to be elected
+func (r *ConfigurationReconciler) phaseReady(ctx context.Context, phase templates.Phase, objs []*unstructured.Unstructured) (bool, string, error) {
+	for _, obj := range objs {
+		ready, message, err := objectReady(obj)
+		if err != nil || !ready {
+			return ready, message, err
+		}
+	}
+
+	if phase.Name != templates.PhaseOVNCentral {
+		return true, "", nil
+	}
+
+	for _, label := range []string{kubeovniov1.NBLeaderLabel, kubeovniov1.SBLeaderLabel} {
+		pods, err := podList(ctx, label, r.Client, r.Namespace)
+		if err != nil {
+			return false, "", err
+		}
+		if len(pods.Items) == 0 {
+			return false, fmt.Sprintf("waiting for ovn-central pod with label %s", label), nil
+		}
+	}
+	return true, "", nil
+}
+
+// waitForRollout records what the current phase is waiting on. The configuration is only reported as Deploying
+// until it has been installed once, as the healthcheck and backups need to keep running during later rollouts
+func waitForRollout(config *kubeovniov1.Configuration, message string) {
+	config.Status.PhaseMessage = message
+	if !installed(config) {
+		config.Status.Status = kubeovniov1.ConfigurationStatusDeploying
+	}
+}
+
+// rolloutInProgress returns true while phases are still being applied
+func rolloutInProgress(config *kubeovniov1.Configuration) bool {
+	if config.Status.Status == kubeovniov1.ConfigurationStatusDeploying {
+		return true
+	}
+	return !config.Spec.Paused && config.Spec.ApplyMode != kubeovniov1.ApplyModePlan &&
+		config.Status.Phase != "" && config.Status.Phase != templates.PhaseComplete
+}
+
+// objectReady checks the status of an applied object. Objects without a meaningful status
+// are considered ready once they have been applied
+func objectReady(obj *unstructured.Unstructured) (bool, string, error) {
+	switch obj.GetKind() {
+	case "CustomResourceDefinition":
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, crd); err != nil {
+			return false, "", fmt.Errorf("error converting crd %s: %v", obj.GetName(), err)
+		}
+		for _, condition := range crd.Status.Conditions {
+			if condition.Type == apiextensionsv1.Established && condition.Status == apiextensionsv1.ConditionTrue {
+				return true, "", nil
+			}
+		}
+		return false, fmt.Sprintf("waiting for crd %s to be established", obj.GetName()), nil
+	case "Deployment":
+		deployment := &appsv1.Deployment{}
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment)
+		if err != nil {
+			return false, "", fmt.Errorf("error converting deployment %s: %v", obj.GetName(), err)
+		}
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		// unavailable replicas are tolerated up to maxUnavailable, matching what the rollout itself tolerates
+		var maxUnavailable int
+		if deployment.Spec.Strategy.RollingUpdate != nil && deployment.Spec.Strategy.RollingUpdate.MaxUnavailable != nil {
+			maxUnavailable, err = intstr.GetScaledValueFromIntOrPercent(deployment.Spec.Strategy.RollingUpdate.MaxUnavailable, int(replicas), false)
+			if err != nil {
+				return false, "", fmt.Errorf("error reading maxUnavailable of deployment %s: %v", obj.GetName(), err)
+			}
+		}
+		if deployment.Status.ObservedGeneration < deployment.Generation || deployment.Status.UpdatedReplicas < replicas ||
+			deployment.Status.AvailableReplicas < replicas-int32(maxUnavailable) {
+			return false, fmt.Sprintf("waiting for deployment %s/%s rollout, %d of %d replicas updated and available",
+				deployment.Namespace, deployment.Name, min(deployment.Status.UpdatedReplicas, deployment.Status.AvailableReplicas), replicas), nil
+		}
+		return true, "", nil
+	case "DaemonSet":
+		daemonset := &appsv1.DaemonSet{}
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, daemonset)
+		if err != nil {
+			return false, "", fmt.Errorf("error converting daemonset %s: %v", obj.GetName(), err)
+		}
+		desired := daemonset.Status.DesiredNumberScheduled
+		updated := daemonset.Status.UpdatedNumberScheduled
+		// pods are only updated when deleted, so only availability can be checked
+		if daemonset.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType {
+			updated = desired
+		}
+		// pods on NotReady nodes are tolerated up to maxUnavailable, so a single node does not block later phases
+		var maxUnavailable int
+		if daemonset.Spec.UpdateStrategy.RollingUpdate != nil && daemonset.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable != nil {
+			maxUnavailable, err = intstr.GetScaledValueFromIntOrPercent(daemonset.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable, int(desired), true)
+			if err != nil {
+				return false, "", fmt.Errorf("error reading maxUnavailable of daemonset %s: %v", obj.GetName(), err)
+			}
+		}
+		if daemonset.Status.ObservedGeneration < daemonset.Generation || updated < desired ||
+			daemonset.Status.NumberAvailable < desired-int32(maxUnavailable) {
+			return false, fmt.Sprintf("waiting for daemonset %s/%s rollout, %d of %d pods updated and available",
+				daemonset.Namespace, daemonset.Name, min(updated, daemonset.Status.NumberAvailable), desired), nil
+		}
+		return true, "", nil
+	case "Certificate", "Issuer":
+		if obj.GroupVersionKind().Group != certManagerGroup {
+			return true, "", nil
+		}
+		conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		if err != nil {
+			return false, "", fmt.Errorf("error reading conditions from %s %s: %v", obj.GetKind(), obj.GetName(), err)
+		}
+		for _, v := range conditions {
+			condition, ok := v.(map[string]any)
+			if ok && condition["type"] == "Ready" && condition["status"] == "True" {
+				return true, "", nil
+			}
+		}
+		return false, fmt.Sprintf("waiting for %s %s/%s to be ready", strings.ToLower(obj.GetKind()), obj.GetNamespace(), obj.GetName()), nil
+	}
+	return true, "", nil
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/templates"
+)
+
+func toUnstructured(t *testing.T, obj runtime.Object, kind string) *unstructured.Unstructured {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	require.NoError(t, err)
+	u := &unstructured.Unstructured{Object: content}
+	u.SetKind(kind)
+	return u
+}
+
+func Test_ObjectReadyDaemonSet(t *testing.T) {
+	maxUnavailable := intstr.FromInt32(1)
+	newDaemonSet := func(status appsv1.DaemonSetStatus) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-ovn-cni", Namespace: "kube-system", Generation: 2},
+			Spec: appsv1.DaemonSetSpec{
+				UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+					Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+					RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable},
+				},
+			},
+			Status: status,
+		}
+	}
+	tests := []struct {
+		name   string
+		status appsv1.DaemonSetStatus
+		ready  bool
+	}{
+		{
+			name:   "all pods updated and available",
+			status: appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3},
+			ready:  true,
+		},
+		{
+			name:   "pod on a NotReady node is tolerated",
+			status: appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 2},
+			ready:  true,
+		},
+		{
+			name:   "more pods unavailable than maxUnavailable",
+			status: appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 1},
+		},
+		{
+			name:   "pods not updated",
+			status: appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 2, NumberAvailable: 3},
+		},
+		{
+			name:   "generation not observed",
+			status: appsv1.DaemonSetStatus{ObservedGeneration: 1, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, message, err := objectReady(toUnstructured(t, newDaemonSet(tt.status), "DaemonSet"))
+			require.NoError(t, err)
+			require.Equal(t, tt.ready, ready, message)
+		})
+	}
+}
+
+func Test_ObjectReadyDeployment(t *testing.T) {
+	maxUnavailable := intstr.FromString("25%")
+	newDeployment := func(status appsv1.DeploymentStatus) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-ovn-controller", Namespace: "kube-system", Generation: 2},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(int32(4)),
+				Strategy: appsv1.DeploymentStrategy{
+					Type:          appsv1.RollingUpdateDeploymentStrategyType,
+					RollingUpdate: &appsv1.RollingUpdateDeployment{MaxUnavailable: &maxUnavailable},
+				},
+			},
+			Status: status,
+		}
+	}
+	assert := require.New(t)
+
+	ready, _, err := objectReady(toUnstructured(t, newDeployment(appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 4, AvailableReplicas: 3}), "Deployment"))
+	assert.NoError(err)
+	assert.True(ready, "a single unavailable replica is within maxUnavailable")
+
+	ready, _, err = objectReady(toUnstructured(t, newDeployment(appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 4, AvailableReplicas: 2}), "Deployment"))
+	assert.NoError(err)
+	assert.False(ready)
+
+	ready, _, err = objectReady(toUnstructured(t, newDeployment(appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 3, AvailableReplicas: 4}), "Deployment"))
+	assert.NoError(err)
+	assert.False(ready)
+}
+
+func Test_WaitForRollout(t *testing.T) {
+	assert := require.New(t)
+	config := newTestConfiguration()
+	config.Status.Status = ""
+	config.Status.CurrentRevision = 0
+
+	// the first install is reported as Deploying
+	config.Status.Phase = templates.PhaseOVNCentral
+	waitForRollout(config, "waiting for ovn-central")
+	assert.Equal(kubeovniov1.ConfigurationStatusDeploying, config.Status.Status)
+	assert.True(rolloutInProgress(config))
+
+	// later rollouts only report progress, and remain Deployed
+	config.Status.Status = kubeovniov1.ConfigurationStatusDeployed
+	config.Status.CurrentRevision = 1
+	waitForRollout(config, "waiting for daemonset kube-system/ovs-ovn rollout")
+	assert.Equal(kubeovniov1.ConfigurationStatusDeployed, config.Status.Status)
+	assert.Equal("waiting for daemonset kube-system/ovs-ovn rollout", config.Status.PhaseMessage)
+	assert.True(rolloutInProgress(config))
+
+	config.Status.Phase = templates.PhaseComplete
+	assert.False(rolloutInProgress(config))
+}
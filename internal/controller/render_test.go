@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/yaml"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/templates"
+)
+
+// newRenderTestReconciler returns a reconciler backed by a fake client, whose rest mapper only serves the
+// cert-manager kinds if certManagerInstalled is set
+func newRenderTestReconciler(t *testing.T, certManagerInstalled bool) *ConfigurationReconciler {
+	testScheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	require.NoError(t, kubeovniov1.AddToScheme(testScheme))
+	require.NoError(t, apiextensionsv1.AddToScheme(testScheme))
+
+	mapper := meta.MultiRESTMapper{testrestmapper.TestOnlyStaticRESTMapper(testScheme)}
+	if certManagerInstalled {
+		certManagerGV := schema.GroupVersion{Group: certManagerGroup, Version: "v1"}
+		certManagerMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{certManagerGV})
+		certManagerMapper.Add(certManagerGV.WithKind("Issuer"), meta.RESTScopeNamespace)
+		certManagerMapper.Add(certManagerGV.WithKind("Certificate"), meta.RESTScopeNamespace)
+		mapper = append(mapper, certManagerMapper)
+	}
+	return &ConfigurationReconciler{
+		Client: fake.NewClientBuilder().WithScheme(testScheme).WithRESTMapper(mapper).Build(),
+		Scheme: testScheme,
+	}
+}
+
+func renderTestConfiguration(t *testing.T, certManagerEnabled bool) (*kubeovniov1.Configuration, *corev1.Namespace) {
+	content, err := os.ReadFile("../../config/samples/kubeovn.io_v1_configuration.yaml")
+	require.NoError(t, err)
+	config := &kubeovniov1.Configuration{}
+	require.NoError(t, yaml.Unmarshal(content, config))
+	config.UID = "config-uid"
+	config.Spec.CertManager.Enabled = certManagerEnabled
+	config.Status.MatchingNodeAddresses = []string{"10.0.0.1"}
+	fakeNSObj := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: kubeovniov1.KubeOVNFakeNamespace, UID: "namespace-uid"}}
+	return config, fakeNSObj
+}
+
+func Test_RenderObjectsAllPhases(t *testing.T) {
+	assert := require.New(t)
+	r := newRenderTestReconciler(t, false)
+	config, fakeNSObj := renderTestConfiguration(t, false)
+
+	for _, phase := range templates.OrderedPhases {
+		for _, v := range phase.Objects {
+			objs, err := r.renderObjects(config, v, fakeNSObj, "v1.14.0", "caCertString")
+			assert.NoError(err, "expected no error rendering phase %s", phase.Name)
+			for _, obj := range objs {
+				assert.NotEqual(certManagerGroup, obj.GetObjectKind().GroupVersionKind().Group,
+					"cert-manager objects are not rendered while the integration is disabled")
+				owner := metav1.GetControllerOf(obj)
+				assert.NotNil(owner, "expected controller reference on %s", obj.GetName())
+				if obj.GetNamespace() == "" {
+					assert.Equal(kubeovniov1.KubeOVNFakeNamespace, owner.Name, "cluster scoped %s is owned by the fake namespace", obj.GetName())
+				} else {
+					assert.Equal(config.Name, owner.Name, "namespaced %s is owned by the configuration", obj.GetName())
+				}
+			}
+		}
+	}
+}
+
+func Test_RenderObjectsCertManager(t *testing.T) {
+	assert := require.New(t)
+	config, fakeNSObj := renderTestConfiguration(t, true)
+	phaseIdx := slices.IndexFunc(templates.OrderedPhases, func(phase templates.Phase) bool {
+		return phase.Name == templates.PhaseBaseResources
+	})
+	phase := templates.OrderedPhases[phaseIdx]
+
+	// cert-manager crds are not installed
+	r := newRenderTestReconciler(t, false)
+	_, err := r.renderPhase(config, phase, fakeNSObj, "v1.14.0", "caCertString", nil, "")
+	assert.ErrorIs(err, errCertManagerNotInstalled)
+
+	r = newRenderTestReconciler(t, true)
+	objs, err := r.renderPhase(config, phase, fakeNSObj, "v1.14.0", "caCertString", nil, "")
+	assert.NoError(err)
+	var certificates int
+	for _, obj := range objs {
+		if obj.GetObjectKind().GroupVersionKind().Group != certManagerGroup {
+			continue
+		}
+		certificates++
+		assert.Equal(config.Name, metav1.GetControllerOf(obj).Name, "cert-manager objects are namespaced and owned by the configuration")
+	}
+	assert.NotZero(certificates)
+}
+
+func Test_ReconcileObjectUnmanaged(t *testing.T) {
+	assert := require.New(t)
+	testScheme := runtime.NewScheme()
+	assert.NoError(clientgoscheme.AddToScheme(testScheme))
+	existing := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "kube-ovn-controller",
+			Namespace:   defaultKubeovnNamespace,
+			Annotations: map[string]string{kubeovniov1.UnmanagedAnnotation: "true"},
+		},
+	}
+	// the unmanaged annotation is read using the typed object, which is served from the cache
+	var fetched []client.Object
+	r := &ConfigurationReconciler{
+		Client: interceptor.NewClient(fake.NewClientBuilder().WithScheme(testScheme).WithObjects(existing).Build(), interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				fetched = append(fetched, obj)
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}),
+		Scheme:    testScheme,
+		Namespace: defaultKubeovnNamespace,
+		Log:       logr.Discard(),
+	}
+
+	rendered := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "kube-ovn-controller", Namespace: defaultKubeovnNamespace}}
+	appliedObj, err := r.reconcileObject(context.TODO(), rendered)
+	assert.NoError(err)
+	assert.Len(fetched, 1)
+	assert.IsType(&appsv1.Deployment{}, fetched[0])
+	assert.Equal("Deployment", appliedObj.GetKind(), "expected kind to be set for readiness checks")
+	assert.Equal("true", appliedObj.GetAnnotations()[kubeovniov1.UnmanagedAnnotation])
+}
+
+func Test_ReconcileObjectOutsideCacheNamespace(t *testing.T) {
+	assert := require.New(t)
+	testScheme := runtime.NewScheme()
+	assert.NoError(clientgoscheme.AddToScheme(testScheme))
+	existing := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "kube-ovn-webhook",
+			Namespace:   "kube-system",
+			Annotations: map[string]string{kubeovniov1.UnmanagedAnnotation: "true"},
+		},
+	}
+	// typed reads are served from the cache, which only covers the operator namespace
+	operatorNamespace := "kubeovn-system"
+	r := &ConfigurationReconciler{
+		Client: interceptor.NewClient(fake.NewClientBuilder().WithScheme(testScheme).WithObjects(existing).Build(), interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*unstructured.Unstructured); !ok && key.Namespace != operatorNamespace {
+					return fmt.Errorf("unable to get: %s because of unknown namespace for the cache", key)
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}),
+		Scheme:    testScheme,
+		Namespace: operatorNamespace,
+		Log:       logr.Discard(),
+	}
+
+	rendered := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "kube-ovn-webhook", Namespace: "kube-system"}}
+	appliedObj, err := r.reconcileObject(context.TODO(), rendered)
+	assert.NoError(err)
+	assert.Equal("Deployment", appliedObj.GetKind())
+	assert.Equal("true", appliedObj.GetAnnotations()[kubeovniov1.UnmanagedAnnotation])
+}
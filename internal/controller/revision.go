@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/render"
+	"github.com/harvester/kubeovn-operator/internal/templates"
+)
+
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;patch;delete
+
+const defaultRevisionHistoryLimit = 10
+
+// revisionData is stored in the ControllerRevision, manifests are a gzip compressed yaml stream
+type revisionData struct {
+	Manifests []byte `json:"manifests"`
+}
+
+// recordRevision stores the rendered objects as a new ControllerRevision if they differ from the latest revision,
+// and removes revisions exceeding spec.revisionHistoryLimit
+func (r *ConfigurationReconciler) recordRevision(ctx context.Context, config *kubeovniov1.Configuration, objs []client.Object, version string) error {
+	revisions, err := r.listRevisions(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	// secrets contain key material and are not stored in revisions, as revisions are readable without access to secrets
+	objs = slices.DeleteFunc(slices.Clone(objs), isSecret)
+	manifests, manifestHash, err := render.EncodeManifests(objs)
+	if err != nil {
+		return fmt.Errorf("error encoding manifests for revision: %v", err)
+	}
+
+	var nextRevision int64 = 1
+	if len(revisions) > 0 {
+		latest := revisions[len(revisions)-1]
+		if latest.Annotations[kubeovniov1.RevisionManifestHashAnnotation] == manifestHash {
+			config.Status.CurrentRevision = latest.Revision
+			return r.pruneRevisions(ctx, config, revisions)
+		}
+		nextRevision = latest.Revision + 1
+	}
+
+	specHash, err := objectHash(config.Spec)
+	if err != nil {
+		return fmt.Errorf("error generating spec hash: %v", err)
+	}
+	data, err := json.Marshal(revisionData{Manifests: manifests})
+	if err != nil {
+		return fmt.Errorf("error marshalling revision data: %v", err)
+	}
+
+	revision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", config.Name, nextRevision),
+			Namespace: config.Namespace,
+			Labels: map[string]string{
+				kubeovniov1.RevisionConfigurationLabel: config.Name,
+			},
+			Annotations: map[string]string{
+				kubeovniov1.RevisionSpecHashAnnotation:     specHash,
+				kubeovniov1.RevisionManifestHashAnnotation: manifestHash,
+				kubeovniov1.RevisionVersionAnnotation:      version,
+			},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: nextRevision,
+	}
+	if err := controllerutil.SetControllerReference(config, revision, r.Scheme); err != nil {
+		return fmt.Errorf("error setting controller reference on revision %s: %v", revision.Name, err)
+	}
+	if err := r.Create(ctx, revision); err != nil {
+		return fmt.Errorf("error creating revision %s: %v", revision.Name, err)
+	}
+	r.Log.WithValues("name", config.Name, "revision", nextRevision).Info("recorded new revision of rendered objects")
+	config.Status.CurrentRevision = nextRevision
+	return r.pruneRevisions(ctx, config, append(revisions, *revision))
+}
+
+// pruneRevisions removes the oldest revisions exceeding the history limit. The current revision is always retained
+func (r *ConfigurationReconciler) pruneRevisions(ctx context.Context, config *kubeovniov1.Configuration, revisions []appsv1.ControllerRevision) error {
+	limit := defaultRevisionHistoryLimit
+	if config.Spec.RevisionHistoryLimit != nil {
+		limit = int(*config.Spec.RevisionHistoryLimit)
+	}
+	for i := 0; i < len(revisions)-limit; i++ {
+		if revisions[i].Revision == config.Status.CurrentRevision {
+			continue
+		}
+		if err := r.Delete(ctx, &revisions[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("error deleting revision %s: %v", revisions[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// listRevisions returns all revisions for the configuration sorted by revision number
+func (r *ConfigurationReconciler) listRevisions(ctx context.Context, config *kubeovniov1.Configuration) ([]appsv1.ControllerRevision, error) {
+	revisionList := &appsv1.ControllerRevisionList{}
+	err := r.List(ctx, revisionList, client.InNamespace(config.Namespace), client.MatchingLabels{kubeovniov1.RevisionConfigurationLabel: config.Name})
+	if err != nil {
+		return nil, fmt.Errorf("error listing revisions: %v", err)
+	}
+	sort.Slice(revisionList.Items, func(i, j int) bool {
+		return revisionList.Items[i].Revision < revisionList.Items[j].Revision
+	})
+	return revisionList.Items, nil
+}
+
+func isSecret(obj client.Object) bool {
+	_, ok := obj.(*corev1.Secret)
+	return ok
+}
+
+// rollbackToRevision applies the objects stored in a revision. Objects are applied in the order they were
+// recorded, but phases are not gated on health, as a rollback is used to recover from a broken rollout. A revision
+// is only applied once, so the stored objects are not decoded and reapplied on every reconcile
+func (r *ConfigurationReconciler) rollbackToRevision(ctx context.Context, config *kubeovniov1.Configuration, revisionNumber int64) error {
+	if config.Status.RolledBackRevision == revisionNumber {
+		return nil
+	}
+
+	revisions, err := r.listRevisions(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	var revision *appsv1.ControllerRevision
+	for i := range revisions {
+		if revisions[i].Revision == revisionNumber {
+			revision = &revisions[i]
+		}
+	}
+	if revision == nil {
+		setConditionIfChanged(config, kubeovniov1.RolledBackCondition, metav1.ConditionFalse,
+			fmt.Sprintf("revision %d not found", revisionNumber), kubeovniov1.RevisionNotFoundReason)
+		return nil
+	}
+
+	data := revisionData{}
+	if err := json.Unmarshal(revision.Data.Raw, &data); err != nil {
+		return fmt.Errorf("error unmarshalling revision %s: %v", revision.Name, err)
+	}
+	objs, err := render.DecodeManifests(data.Manifests)
+	if err != nil {
+		return fmt.Errorf("error decoding revision %s: %v", revision.Name, err)
+	}
+
+	var managedObjects []kubeovniov1.ObjectReference
+	for _, obj := range objs {
+		appliedObj, err := r.reconcileObject(ctx, obj)
+		if err != nil {
+			return fmt.Errorf("error reconcilling object %s/%s from revision %s: %v", obj.GetNamespace(), obj.GetName(), revision.Name, err)
+		}
+		objRef, err := generateObjectReference(obj, appliedObj)
+		if err != nil {
+			return fmt.Errorf("error generating object reference for %s/%s: %v", obj.GetNamespace(), obj.GetName(), err)
+		}
+		managedObjects = append(managedObjects, objRef)
+	}
+	// secrets are not stored in revisions, so existing secrets are retained
+	for _, ref := range config.Status.ManagedObjects {
+		if ref.GVK.Group == "" && ref.GVK.Kind == "Secret" {
+			managedObjects = append(managedObjects, ref)
+		}
+	}
+	sortObjectReferences(managedObjects)
+
+	previousObjects := slices.Concat(config.Status.ManagedObjects, config.Status.OrphanedObjects)
+	if err := r.pruneObjects(ctx, config, findOrphanedObjects(previousObjects, managedObjects)); err != nil {
+		return err
+	}
+	config.Status.ManagedObjects = managedObjects
+	config.Status.CurrentRevision = revision.Revision
+	config.Status.RolledBackRevision = revision.Revision
+	if version := revision.Annotations[kubeovniov1.RevisionVersionAnnotation]; version != "" {
+		config.Status.CurrentVersion = version
+	}
+	config.Status.Phase = templates.PhaseComplete
+	config.Status.PhaseMessage = ""
+	config.Status.Status = kubeovniov1.ConfigurationStatusDeployed
+	setConditionIfChanged(config, kubeovniov1.RolledBackCondition, metav1.ConditionTrue,
+		fmt.Sprintf("objects from revision %d have been applied", revision.Revision), kubeovniov1.RolledBackReason)
+	return nil
+}
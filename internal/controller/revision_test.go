@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/render"
+)
+
+func Test_RollbackAppliedOnce(t *testing.T) {
+	assert := require.New(t)
+	config := newTestConfiguration()
+	config.Spec.RollbackTo = ptr.To[int64](1)
+	manifests, _, err := render.EncodeManifests(nil)
+	assert.NoError(err)
+	data, err := json.Marshal(revisionData{Manifests: manifests})
+	assert.NoError(err)
+	revision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.Name + "-1",
+			Namespace: config.Namespace,
+			Labels:    map[string]string{kubeovniov1.RevisionConfigurationLabel: config.Name},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: 1,
+	}
+
+	lists := 0
+	testScheme := newTestScheme(t)
+	r := &ConfigurationReconciler{
+		Client: interceptor.NewClient(fake.NewClientBuilder().WithScheme(testScheme).WithObjects(revision).Build(), interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				lists++
+				return c.List(ctx, list, opts...)
+			},
+		}),
+		Scheme:    testScheme,
+		Namespace: defaultKubeovnNamespace,
+		Log:       logr.Discard(),
+	}
+
+	assert.NoError(r.rollbackToRevision(context.TODO(), config, 1))
+	assert.Equal(1, lists)
+	assert.Equal(int64(1), config.Status.RolledBackRevision)
+	assert.True(config.ConditionTrue(kubeovniov1.RolledBackCondition))
+
+	// the revision has been applied, so later reconciles do not read and reapply it
+	assert.NoError(r.rollbackToRevision(context.TODO(), config, 1))
+	assert.Equal(1, lists)
+
+	// changing spec.rollbackTo applies the requested revision
+	assert.NoError(r.rollbackToRevision(context.TODO(), config, 2))
+	assert.Equal(2, lists)
+	assert.False(config.ConditionTrue(kubeovniov1.RolledBackCondition))
+}
@@ -36,6 +36,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/executor"
 	"github.com/harvester/kubeovn-operator/internal/testinfra"
 	// +kubebuilder:scaffold:imports
 )
@@ -131,6 +132,7 @@ var _ = BeforeSuite(func() {
 		EventRecorder: mgr.GetEventRecorderFor("configuration-controller"),
 		Log:           logf.FromContext(ctx),
 		RestConfig:    mgr.GetConfig(),
+		CommandRunner: executor.NewRemoteCommandRunner(mgr.GetConfig()),
 		Version:       Version,
 	}
 	err = cr.SetupWithManager(mgr)
@@ -143,6 +145,7 @@ var _ = BeforeSuite(func() {
 		EventRecorder: mgr.GetEventRecorderFor("node-controller"),
 		Log:           logf.FromContext(ctx),
 		RestConfig:    mgr.GetConfig(),
+		CommandRunner: executor.NewRemoteCommandRunner(mgr.GetConfig()),
 	}).SetupWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -153,6 +156,7 @@ var _ = BeforeSuite(func() {
 		EventRecorder:       mgr.GetEventRecorderFor("health-check-controller"),
 		Log:                 logf.FromContext(ctx),
 		RestConfig:          mgr.GetConfig(),
+		CommandRunner:       executor.NewRemoteCommandRunner(mgr.GetConfig()),
 		HealthCheckInterval: 300,
 	}).SetupWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/pki"
+)
+
+const (
+	ovnTLSCACertKey = "cacert"
+	ovnTLSCertKey   = "cert"
+	ovnTLSKeyKey    = "key"
+)
+
+// builtInPKIEnabled checks if the operator issues the ovn tls material. cert-manager takes over issuing when enabled
+func builtInPKIEnabled(config *kubeovniov1.Configuration) bool {
+	return config.Spec.Networking.EnableSSL != nil && *config.Spec.Networking.EnableSSL && !config.Spec.CertManager.Enabled
+}
+
+// ovnTLSSecret returns the desired kube-ovn-tls secret and a checksum of its contents. The leaf certificate in the
+// existing secret is retained while it is valid, and is reissued by the operator ca once it is within the renewal
+// window. During a dry run the ca is not persisted and the certificate expiry is not reported
+func (r *ConfigurationReconciler) ovnTLSSecret(ctx context.Context, config *kubeovniov1.Configuration, dryRun bool) (*corev1.Secret, string, error) {
+	if !builtInPKIEnabled(config) {
+		if !dryRun {
+			tlsCertificateExpiry.Reset()
+			if config.ConditionExists(kubeovniov1.TLSCertificateExpiryCondition) {
+				setConditionIfChanged(config, kubeovniov1.TLSCertificateExpiryCondition, metav1.ConditionFalse,
+					"ovn tls material is not issued by the operator", kubeovniov1.BuiltInPKIDisabledReason)
+			}
+		}
+		return nil, "", nil
+	}
+
+	now := time.Now()
+	ca, err := r.ovnCA(ctx, config, now, dryRun)
+	if err != nil {
+		return nil, "", err
+	}
+	caCert, err := pki.ParseCertificate(ca.Cert)
+	if err != nil {
+		return nil, "", fmt.Errorf("error parsing ca from secret %s: %v", kubeovniov1.OVNCASecret, err)
+	}
+
+	existing := &corev1.Secret{}
+	err = r.Get(ctx, types.NamespacedName{Name: kubeovniov1.OVNTLSSecret, Namespace: config.Namespace}, existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, "", fmt.Errorf("error fetching secret %s: %v", kubeovniov1.OVNTLSSecret, err)
+	}
+
+	// unexpired cas from the existing bundle are retained, so certificates issued by a previous ca, including
+	// the ca generated by earlier operator versions, remain trusted while components are restarted
+	caBundle := []*x509.Certificate{caCert}
+	if previous, err := pki.ParseCertificates(existing.Data[ovnTLSCACertKey]); err == nil {
+		for _, cert := range previous {
+			if cert.IsCA && now.Before(cert.NotAfter) {
+				caBundle = append(caBundle, cert)
+			}
+		}
+	}
+
+	leaf := pki.KeyPair{Cert: existing.Data[ovnTLSCertKey], Key: existing.Data[ovnTLSKeyKey]}
+	leafCert, _, err := pki.ParseKeyPair(leaf)
+	// leaf certificates are capped at the ca expiry, and are not reissued in a loop once the ca itself is expiring
+	if err != nil || pki.Verify(leafCert, caBundle, now) != nil || (pki.NeedsRotation(leafCert, now) && leafCert.NotAfter.Before(caCert.NotAfter)) {
+		rotated := leafCert != nil
+		leaf, err = pki.GenerateLeaf(ca, now)
+		if err != nil {
+			return nil, "", fmt.Errorf("error issuing ovn certificate: %v", err)
+		}
+		if leafCert, err = pki.ParseCertificate(leaf.Cert); err != nil {
+			return nil, "", fmt.Errorf("error parsing issued ovn certificate: %v", err)
+		}
+		if rotated && !dryRun {
+			r.EventRecorder.Event(config, corev1.EventTypeNormal, "CertificateRotated",
+				fmt.Sprintf("certificate in secret %s has been reissued, valid until %s", kubeovniov1.OVNTLSSecret, leafCert.NotAfter.UTC().Format(time.RFC3339)))
+		}
+	}
+
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kubeovniov1.OVNTLSSecret,
+			Namespace: config.Namespace,
+		},
+		Data: map[string][]byte{
+			ovnTLSCACertKey: pki.EncodeCertificates(caBundle...),
+			ovnTLSCertKey:   leaf.Cert,
+			ovnTLSKeyKey:    leaf.Key,
+		},
+	}
+	if err := controllerutil.SetControllerReference(config, secret, r.Scheme); err != nil {
+		return nil, "", fmt.Errorf("error setting controller reference on secret %s: %v", secret.Name, err)
+	}
+
+	if !dryRun {
+		tlsCertificateExpiry.WithLabelValues(kubeovniov1.OVNCASecret).Set(float64(caCert.NotAfter.Unix()))
+		tlsCertificateExpiry.WithLabelValues(kubeovniov1.OVNTLSSecret).Set(float64(leafCert.NotAfter.Unix()))
+		// the leaf certificate is only left within the renewal window when the ca expires first
+		if pki.NeedsRotation(leafCert, now) {
+			setConditionIfChanged(config, kubeovniov1.TLSCertificateExpiryCondition, metav1.ConditionTrue,
+				fmt.Sprintf("certificate notAfter %s is due for rotation, but ca notAfter %s expires first, delete secret %s to rotate the ca",
+					leafCert.NotAfter.UTC().Format(time.RFC3339), caCert.NotAfter.UTC().Format(time.RFC3339), kubeovniov1.OVNCASecret),
+				kubeovniov1.CertificateRotationPendingReason)
+		} else {
+			setConditionIfChanged(config, kubeovniov1.TLSCertificateExpiryCondition, metav1.ConditionTrue,
+				fmt.Sprintf("certificate notAfter %s, ca notAfter %s", leafCert.NotAfter.UTC().Format(time.RFC3339), caCert.NotAfter.UTC().Format(time.RFC3339)),
+				kubeovniov1.CertificateValidReason)
+		}
+	}
+
+	checksum := sha256.New()
+	for _, key := range []string{ovnTLSCACertKey, ovnTLSCertKey, ovnTLSKeyKey} {
+		checksum.Write(secret.Data[key])
+	}
+	return secret, fmt.Sprintf("%x", checksum.Sum(nil)), nil
+}
+
+// ovnCA returns the ca used to issue ovn certificates, generating it if it does not exist yet. The ca secret is not
+// a rendered object, so it is retained when ssl is disabled and reused if it is enabled again
+func (r *ConfigurationReconciler) ovnCA(ctx context.Context, config *kubeovniov1.Configuration, now time.Time, dryRun bool) (pki.KeyPair, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: kubeovniov1.OVNCASecret, Namespace: config.Namespace}, secret)
+	if err == nil {
+		ca := pki.KeyPair{Cert: secret.Data[corev1.TLSCertKey], Key: secret.Data[corev1.TLSPrivateKeyKey]}
+		if _, _, err := pki.ParseKeyPair(ca); err != nil {
+			setConditionIfChanged(config, kubeovniov1.TLSCertificateExpiryCondition, metav1.ConditionFalse,
+				fmt.Sprintf("invalid ca in secret %s: %v", kubeovniov1.OVNCASecret, err), kubeovniov1.CertificateInvalidReason)
+			return pki.KeyPair{}, fmt.Errorf("error parsing ca from secret %s: %v", kubeovniov1.OVNCASecret, err)
+		}
+		return ca, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return pki.KeyPair{}, fmt.Errorf("error fetching secret %s: %v", kubeovniov1.OVNCASecret, err)
+	}
+
+	ca, err := pki.GenerateCA(pki.OVNCACommonName, now)
+	if err != nil {
+		return pki.KeyPair{}, err
+	}
+	if dryRun {
+		return ca, nil
+	}
+
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kubeovniov1.OVNCASecret,
+			Namespace: config.Namespace,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       ca.Cert,
+			corev1.TLSPrivateKeyKey: ca.Key,
+		},
+	}
+	if err := controllerutil.SetControllerReference(config, secret, r.Scheme); err != nil {
+		return pki.KeyPair{}, fmt.Errorf("error setting controller reference on secret %s: %v", secret.Name, err)
+	}
+	if err := r.Create(ctx, secret); err != nil {
+		return pki.KeyPair{}, fmt.Errorf("error creating secret %s: %v", secret.Name, err)
+	}
+	r.Log.WithValues("name", config.Name).Info("generated ovn ca")
+	return ca, nil
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/pki"
+	"github.com/harvester/kubeovn-operator/internal/templates"
+)
+
+func newTLSTestReconciler(t *testing.T) (*ConfigurationReconciler, *kubeovniov1.Configuration, *corev1.Namespace) {
+	r := newRenderTestReconciler(t, false)
+	r.EventRecorder = record.NewFakeRecorder(100)
+	config, fakeNSObj := renderTestConfiguration(t, false)
+	config.Spec.Networking.EnableSSL = ptr.To(true)
+	return r, config, fakeNSObj
+}
+
+func Test_OVNTLSSecretRotationPending(t *testing.T) {
+	assert := require.New(t)
+	r, config, _ := newTLSTestReconciler(t)
+
+	_, _, err := r.ovnTLSSecret(context.TODO(), config, false)
+	assert.NoError(err)
+	condition := config.LookupCondition(kubeovniov1.TLSCertificateExpiryCondition)
+	assert.Equal(kubeovniov1.CertificateValidReason, condition.Reason)
+
+	// leaf certificates issued by a ca expiring within the renewal window cannot be rotated
+	ca, err := pki.GenerateCA(pki.OVNCACommonName, time.Now().Add(-pki.CADuration+pki.LeafRenewBefore/2))
+	assert.NoError(err)
+	caSecret := &corev1.Secret{}
+	assert.NoError(r.Get(context.TODO(), client.ObjectKey{Name: kubeovniov1.OVNCASecret, Namespace: config.Namespace}, caSecret))
+	caSecret.Data = map[string][]byte{corev1.TLSCertKey: ca.Cert, corev1.TLSPrivateKeyKey: ca.Key}
+	assert.NoError(r.Update(context.TODO(), caSecret))
+
+	_, _, err = r.ovnTLSSecret(context.TODO(), config, false)
+	assert.NoError(err)
+	condition = config.LookupCondition(kubeovniov1.TLSCertificateExpiryCondition)
+	assert.Equal(metav1.ConditionTrue, condition.Status)
+	assert.Equal(kubeovniov1.CertificateRotationPendingReason, condition.Reason)
+	assert.Contains(condition.Message, kubeovniov1.OVNCASecret)
+}
+
+func Test_OVNTLSSecretOverrides(t *testing.T) {
+	assert := require.New(t)
+	r, config, fakeNSObj := newTLSTestReconciler(t)
+	config.Spec.Overrides = []kubeovniov1.ObjectOverride{
+		{
+			Target: kubeovniov1.OverrideTarget{
+				GroupVersionKind: kubeovniov1.GroupVersionKind{Version: "v1", Kind: "Secret"},
+				Name:             kubeovniov1.OVNTLSSecret,
+			},
+			PatchType: "StrategicMerge",
+			Patch:     "metadata:\n  labels:\n    backup: exclude\n",
+		},
+	}
+	tlsSecret, checksum, err := r.ovnTLSSecret(context.TODO(), config, false)
+	assert.NoError(err)
+
+	phaseIdx := slices.IndexFunc(templates.OrderedPhases, func(phase templates.Phase) bool {
+		return phase.Name == templates.PhaseBaseResources
+	})
+	objs, err := r.renderPhase(config, templates.OrderedPhases[phaseIdx], fakeNSObj, "v1.14.0", "caCertString", tlsSecret, checksum)
+	assert.NoError(err)
+	idx := slices.IndexFunc(objs, func(obj client.Object) bool {
+		_, ok := obj.(*corev1.Secret)
+		return ok && obj.GetName() == kubeovniov1.OVNTLSSecret
+	})
+	assert.NotEqual(-1, idx)
+	assert.Equal("exclude", objs[idx].GetLabels()["backup"], "expected overrides to be applied to the issued secret")
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/upgrade"
+)
+
+// seedCurrentVersion records the version of an existing install which has no current version in its status, so
+// that upgrading the operator does not implicitly upgrade kube-ovn. The version is read from the current revision,
+// falling back to the image tag of the deployed ovn-central. New installs are left without a current version
+func (r *ConfigurationReconciler) seedCurrentVersion(ctx context.Context, config *kubeovniov1.Configuration) error {
+	if config.Status.CurrentVersion != "" {
+		return nil
+	}
+
+	revisions, err := r.listRevisions(ctx, config)
+	if err != nil {
+		return err
+	}
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if config.Status.CurrentRevision != 0 && revisions[i].Revision != config.Status.CurrentRevision {
+			continue
+		}
+		if version := revisions[i].Annotations[kubeovniov1.RevisionVersionAnnotation]; version != "" {
+			r.Log.WithValues("name", config.Name, "revision", revisions[i].Revision).Info("seeding current version from revision", "version", version)
+			config.Status.CurrentVersion = version
+			return nil
+		}
+		break
+	}
+
+	deployment := &appsv1.Deployment{}
+	err = r.Get(ctx, types.NamespacedName{Name: kubeovniov1.OVNCentralDeploymentName, Namespace: r.Namespace}, deployment)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error fetching deployment %s: %v", kubeovniov1.OVNCentralDeploymentName, err)
+	}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name != kubeovniov1.OVNCentralContainerName {
+			continue
+		}
+		if version := upgrade.VersionFromImage(container.Image); version != "" {
+			r.Log.WithValues("name", config.Name).Info("seeding current version from deployed image", "image", container.Image, "version", version)
+			config.Status.CurrentVersion = version
+		}
+	}
+	return nil
+}
+
+// resolveVersion returns the kube-ovn version objects should be rendered with. If the upgrade path from the
+// current version is not supported, the current version is retained and the validation error is returned
+func (r *ConfigurationReconciler) resolveVersion(config *kubeovniov1.Configuration) (string, error) {
+	target := upgrade.TargetVersion(config, r.Version)
+	if err := upgrade.ValidateUpgradePath(config.Status.CurrentVersion, target); err != nil {
+		return config.Status.CurrentVersion, err
+	}
+	return target, nil
+}
+
+// updateUpgradeStatus records the target version and reports the progress of an upgrade in the Upgrading condition
+func (r *ConfigurationReconciler) updateUpgradeStatus(config *kubeovniov1.Configuration, version string, upgradeErr error) {
+	config.Status.TargetVersion = upgrade.TargetVersion(config, r.Version)
+	if upgradeErr != nil {
+		r.Log.WithValues("name", config.Name).Error(upgradeErr, "unsupported upgrade path, retaining current version", "version", version)
+		setConditionIfChanged(config, kubeovniov1.UpgradingCondition, metav1.ConditionFalse, upgradeErr.Error(), kubeovniov1.UpgradeBlockedReason)
+		return
+	}
+
+	if config.Status.CurrentVersion != "" && config.Status.CurrentVersion != version {
+		setConditionIfChanged(config, kubeovniov1.UpgradingCondition, metav1.ConditionTrue,
+			fmt.Sprintf("upgrading from %s to %s", config.Status.CurrentVersion, version), kubeovniov1.UpgradingReason)
+	}
+}
+
+// completeUpgrade records the version once all phases have been rolled out successfully
+func (r *ConfigurationReconciler) completeUpgrade(config *kubeovniov1.Configuration, version string) {
+	if config.ConditionTrue(kubeovniov1.UpgradingCondition) {
+		config.SetCondition(kubeovniov1.UpgradingCondition, metav1.ConditionFalse, fmt.Sprintf("upgrade to %s complete", version), kubeovniov1.UpgradeCompleteReason)
+	}
+	config.Status.CurrentVersion = version
+}
+
+// setConditionIfChanged only updates a condition when its status, message or reason change, or the spec has
+// been updated, to avoid a status update on each reconcile
+func setConditionIfChanged(config *kubeovniov1.Configuration, conditionType string, status metav1.ConditionStatus, message string, reason string) {
+	condition := config.LookupCondition(conditionType)
+	if condition.Status == status && condition.Message == message && condition.Reason == reason && condition.ObservedGeneration == config.Generation {
+		return
+	}
+	config.SetCondition(conditionType, status, message, reason)
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+func newUpgradeTestReconciler(t *testing.T, objs ...client.Object) *ConfigurationReconciler {
+	return &ConfigurationReconciler{
+		Client:    fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(objs...).Build(),
+		Namespace: defaultKubeovnNamespace,
+		Version:   "v1.15.0",
+		Log:       logr.Discard(),
+	}
+}
+
+func newTestOVNCentralDeployment(image string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: kubeovniov1.OVNCentralDeploymentName, Namespace: defaultKubeovnNamespace},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: kubeovniov1.OVNCentralContainerName, Image: image}},
+				},
+			},
+		},
+	}
+}
+
+func newTestRevision(revision int64, version string) *appsv1.ControllerRevision {
+	return &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "kubeovn-" + version,
+			Namespace:   defaultKubeovnNamespace,
+			Labels:      map[string]string{kubeovniov1.RevisionConfigurationLabel: kubeovniov1.DefaultConfigurationName},
+			Annotations: map[string]string{kubeovniov1.RevisionVersionAnnotation: version},
+		},
+		Revision: revision,
+	}
+}
+
+func Test_SeedCurrentVersion(t *testing.T) {
+	assert := require.New(t)
+
+	// new installs default to the operator version
+	r := newUpgradeTestReconciler(t)
+	config := newTestConfiguration()
+	assert.NoError(r.seedCurrentVersion(context.TODO(), config))
+	version, err := r.resolveVersion(config)
+	assert.NoError(err)
+	assert.Equal("v1.15.0", version)
+
+	// existing installs retain the deployed version
+	r = newUpgradeTestReconciler(t, newTestOVNCentralDeployment("docker.io/kubeovn/kube-ovn:v1.14.4"))
+	config = newTestConfiguration()
+	assert.NoError(r.seedCurrentVersion(context.TODO(), config))
+	assert.Equal("v1.14.4", config.Status.CurrentVersion)
+	version, err = r.resolveVersion(config)
+	assert.NoError(err)
+	assert.Equal("v1.14.4", version)
+
+	// the version of the current revision takes precedence over the deployed image
+	r = newUpgradeTestReconciler(t, newTestOVNCentralDeployment("docker.io/kubeovn/kube-ovn:v1.14.4"),
+		newTestRevision(1, "v1.14.2"), newTestRevision(2, "v1.14.3"))
+	config = newTestConfiguration()
+	config.Status.CurrentRevision = 1
+	assert.NoError(r.seedCurrentVersion(context.TODO(), config))
+	assert.Equal("v1.14.2", config.Status.CurrentVersion)
+
+	// images without a version tag are not used
+	r = newUpgradeTestReconciler(t, newTestOVNCentralDeployment("registry.local:5000/kubeovn/kube-ovn:latest"))
+	config = newTestConfiguration()
+	assert.NoError(r.seedCurrentVersion(context.TODO(), config))
+	assert.Empty(config.Status.CurrentVersion)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -15,6 +16,40 @@ import (
 	"k8s.io/kubectl/pkg/scheme"
 )
 
+// CommandRunner runs commands in a container of a pod. Controllers are passed a CommandRunner, so tests can replace
+// it, as the fake client does not support exec
+type CommandRunner interface {
+	Run(ctx context.Context, pod *corev1.Pod, containerName string, cmd string) ([]byte, error)
+	// Stream writes the output of cmd to output as it is produced, and returns the error output if cmd fails
+	Stream(ctx context.Context, pod *corev1.Pod, containerName string, cmd string, output io.Writer) ([]byte, error)
+}
+
+// RemoteCommandRunner is a CommandRunner which runs commands using a RemoteCommandExecutor
+type RemoteCommandRunner struct {
+	cfg *rest.Config
+}
+
+// NewRemoteCommandRunner returns a CommandRunner which runs commands in pods using config
+func NewRemoteCommandRunner(config *rest.Config) *RemoteCommandRunner {
+	return &RemoteCommandRunner{cfg: config}
+}
+
+func (r *RemoteCommandRunner) Run(ctx context.Context, pod *corev1.Pod, containerName string, cmd string) ([]byte, error) {
+	podExecutor, err := NewRemoteCommandExecutor(ctx, r.cfg, pod)
+	if err != nil {
+		return nil, fmt.Errorf("error generating new remote command executor: %v", err)
+	}
+	return podExecutor.Run(containerName, cmd)
+}
+
+func (r *RemoteCommandRunner) Stream(ctx context.Context, pod *corev1.Pod, containerName string, cmd string, output io.Writer) ([]byte, error) {
+	podExecutor, err := NewRemoteCommandExecutor(ctx, r.cfg, pod)
+	if err != nil {
+		return nil, fmt.Errorf("error generating new remote command executor: %v", err)
+	}
+	return podExecutor.Stream(containerName, cmd, output)
+}
+
 type RemoteCommandExecutor struct {
 	client *kubernetes.Clientset
 	pod    *corev1.Pod
@@ -44,13 +79,31 @@ func NewRemoteCommandExecutor(ctx context.Context, config *rest.Config, pod *cor
 }
 
 func (r *RemoteCommandExecutor) Run(containerName string, cmd string) ([]byte, error) {
-	inBuf := &bytes.Buffer{}
+	return r.RunWithInput(containerName, cmd, &bytes.Buffer{})
+}
+
+// RunWithInput runs the command with input streamed to stdin, which allows files to be written to the container
+func (r *RemoteCommandExecutor) RunWithInput(containerName string, cmd string, input io.Reader) ([]byte, error) {
 	outBuf := &bytes.Buffer{}
+	errOutput, err := r.stream(containerName, cmd, input, outBuf)
+	if err != nil {
+		return errOutput, err
+	}
+	return outBuf.Bytes(), nil
+}
+
+// Stream runs the command with stdout written to output as it is produced, which allows large files to be read
+// from the container without buffering them. The error output is returned if the command fails
+func (r *RemoteCommandExecutor) Stream(containerName string, cmd string, output io.Writer) ([]byte, error) {
+	return r.stream(containerName, cmd, &bytes.Buffer{}, output)
+}
+
+func (r *RemoteCommandExecutor) stream(containerName string, cmd string, input io.Reader, output io.Writer) ([]byte, error) {
 	errBuf := &bytes.Buffer{}
 
 	iostreams := genericiooptions.IOStreams{
-		In:     inBuf,
-		Out:    outBuf,
+		In:     input,
+		Out:    output,
 		ErrOut: errBuf,
 	}
 
@@ -76,5 +129,5 @@ func (r *RemoteCommandExecutor) Run(containerName string, cmd string) ([]byte, e
 	if err != nil {
 		return errBuf.Bytes(), fmt.Errorf("error during command execution: %v", err)
 	}
-	return outBuf.Bytes(), nil
+	return nil, nil
 }
@@ -0,0 +1,62 @@
+package ovsdb
+
+import (
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+// MergeAddressChanges compares the master nodes with the nodes whose addresses were last rendered, and combines
+// the changes found with the changes in progress. Nodes are matched by name, so a node keeping its name with a
+// new address is a change, while added and removed nodes are not. Changes which have not started are dropped
+// if the node returns to its previous address, and are updated if the address changes again
+func MergeAddressChanges(rendered, current []ovnoperatorv1.MatchingNode, pending []ovnoperatorv1.NodeAddressChange, now metav1.Time) []ovnoperatorv1.NodeAddressChange {
+	var merged []ovnoperatorv1.NodeAddressChange
+	for _, p := range pending {
+		if p.Phase != ovnoperatorv1.NodeAddressChangePhaseDetected {
+			merged = append(merged, p)
+			continue
+		}
+		idx := slices.IndexFunc(current, func(node ovnoperatorv1.MatchingNode) bool {
+			return node.Name == p.Node
+		})
+		// a removed node still needs its previous address kicked
+		if idx != -1 {
+			if current[idx].Address == p.PreviousAddress {
+				continue
+			}
+			p.Address = current[idx].Address
+		}
+		merged = append(merged, p)
+	}
+
+	for _, node := range current {
+		idx := slices.IndexFunc(rendered, func(r ovnoperatorv1.MatchingNode) bool {
+			return r.Name == node.Name
+		})
+		if idx == -1 || rendered[idx].Address == node.Address || node.Address == "" {
+			continue
+		}
+		if slices.ContainsFunc(merged, func(m ovnoperatorv1.NodeAddressChange) bool {
+			return m.Node == node.Name
+		}) {
+			continue
+		}
+		merged = append(merged, ovnoperatorv1.NodeAddressChange{
+			Node:               node.Name,
+			PreviousAddress:    rendered[idx].Address,
+			Address:            node.Address,
+			Phase:              ovnoperatorv1.NodeAddressChangePhaseDetected,
+			DetectedTime:       now,
+			LastTransitionTime: now,
+		})
+	}
+
+	slices.SortFunc(merged, func(a, b ovnoperatorv1.NodeAddressChange) int {
+		return strings.Compare(a.Node, b.Node)
+	})
+	return merged
+}
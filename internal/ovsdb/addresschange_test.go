@@ -0,0 +1,60 @@
+package ovsdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+func Test_MergeAddressChanges(t *testing.T) {
+	assert := require.New(t)
+	now := metav1.Now()
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	rendered := []ovnoperatorv1.MatchingNode{
+		{Name: "node1", Address: "10.0.0.1"},
+		{Name: "node2", Address: "10.0.0.2"},
+		{Name: "node3", Address: "10.0.0.3"},
+	}
+
+	// node2 is re-addressed, node3 is replaced by node4 which is not an address change
+	current := []ovnoperatorv1.MatchingNode{
+		{Name: "node1", Address: "10.0.0.1"},
+		{Name: "node2", Address: "10.0.1.2"},
+		{Name: "node4", Address: "10.0.0.3"},
+	}
+	merged := MergeAddressChanges(rendered, current, nil, now)
+	assert.Len(merged, 1)
+	assert.Equal("node2", merged[0].Node)
+	assert.Equal("10.0.0.2", merged[0].PreviousAddress)
+	assert.Equal("10.0.1.2", merged[0].Address)
+	assert.Equal(ovnoperatorv1.NodeAddressChangePhaseDetected, merged[0].Phase)
+	assert.Equal(now, merged[0].DetectedTime)
+
+	pending := []ovnoperatorv1.NodeAddressChange{
+		{Node: "node1", PreviousAddress: "10.0.0.1", Address: "10.0.1.1", Phase: ovnoperatorv1.NodeAddressChangePhaseDetected,
+			DetectedTime: earlier, LastTransitionTime: earlier},
+		{Node: "node2", PreviousAddress: "10.0.0.2", Address: "10.0.1.2", Phase: ovnoperatorv1.NodeAddressChangePhaseDetected,
+			DetectedTime: earlier, LastTransitionTime: earlier},
+		{Node: "node3", PreviousAddress: "10.0.0.3", Address: "10.0.1.3", Phase: ovnoperatorv1.NodeAddressChangePhaseRebuilding,
+			JobName: "ovn-address-change-abcde", DetectedTime: earlier, LastTransitionTime: earlier},
+	}
+	// node1 returned to its previous address, node2 changed address again, and node3 has started and is retained
+	current = []ovnoperatorv1.MatchingNode{
+		{Name: "node1", Address: "10.0.0.1"},
+		{Name: "node2", Address: "10.0.2.2"},
+	}
+	merged = MergeAddressChanges(rendered, current, pending, now)
+	assert.Len(merged, 2)
+	assert.Equal("node2", merged[0].Node)
+	assert.Equal("10.0.0.2", merged[0].PreviousAddress)
+	assert.Equal("10.0.2.2", merged[0].Address)
+	assert.Equal(earlier, merged[0].DetectedTime)
+	assert.Equal("node3", merged[1].Node)
+	assert.Equal(ovnoperatorv1.NodeAddressChangePhaseRebuilding, merged[1].Phase)
+
+	assert.Empty(MergeAddressChanges(nil, current, nil, now))
+}
@@ -0,0 +1,93 @@
+package ovsdb
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+// Chassis is a record from the southbound Chassis table
+type Chassis struct {
+	Name     string
+	Hostname string
+}
+
+// ParseChassisList parses the csv output of ovn-sbctl list chassis with the name and hostname columns
+func ParseChassisList(output []byte) ([]Chassis, error) {
+	records, err := csv.NewReader(bytes.NewReader(output)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing chassis list: %w", err)
+	}
+
+	chassis := make([]Chassis, 0, len(records))
+	for _, record := range records {
+		if len(record) != 2 {
+			return nil, fmt.Errorf("expected name and hostname columns in chassis record %v", record)
+		}
+		chassis = append(chassis, Chassis{Name: record[0], Hostname: record[1]})
+	}
+	return chassis, nil
+}
+
+// AuditChassis compares chassis records with the hostnames of nodes, keyed by node name, and the nodes running
+// an ovs-ovn pod. A chassis matches a node if its hostname is either the node name or the node hostname
+func AuditChassis(chassis []Chassis, nodeHostnames map[string]string, ovsNodes []string) ovnoperatorv1.ChassisAuditStatus {
+	known := make(map[string]bool)
+	for name, hostname := range nodeHostnames {
+		known[name] = true
+		known[hostname] = true
+	}
+
+	byHostname := make(map[string][]Chassis)
+	status := ovnoperatorv1.ChassisAuditStatus{}
+	for _, c := range chassis {
+		byHostname[c.Hostname] = append(byHostname[c.Hostname], c)
+		if !known[c.Hostname] {
+			status.StaleChassis = append(status.StaleChassis, ovnoperatorv1.ChassisReference{Name: c.Name, Hostname: c.Hostname})
+		}
+	}
+
+	for _, c := range chassis {
+		if len(byHostname[c.Hostname]) > 1 {
+			status.DuplicateChassis = append(status.DuplicateChassis, ovnoperatorv1.ChassisReference{Name: c.Name, Hostname: c.Hostname})
+		}
+	}
+
+	for _, node := range ovsNodes {
+		if len(byHostname[node]) == 0 && len(byHostname[nodeHostnames[node]]) == 0 {
+			status.NodesWithoutChassis = append(status.NodesWithoutChassis, node)
+		}
+	}
+
+	sortChassisReferences(status.StaleChassis)
+	sortChassisReferences(status.DuplicateChassis)
+	slices.Sort(status.NodesWithoutChassis)
+	return status
+}
+
+// RetainDetectedTime sets the detected time of chassis reported in a previous audit, so that the time a
+// chassis has been stale for is retained across audits. Newly reported chassis are detected at now
+func RetainDetectedTime(previous, current []ovnoperatorv1.ChassisReference, now metav1.Time) {
+	for i := range current {
+		current[i].DetectedTime = now
+		for _, p := range previous {
+			if p.Name == current[i].Name && p.Hostname == current[i].Hostname {
+				current[i].DetectedTime = p.DetectedTime
+				break
+			}
+		}
+	}
+}
+
+func sortChassisReferences(refs []ovnoperatorv1.ChassisReference) {
+	slices.SortFunc(refs, func(a, b ovnoperatorv1.ChassisReference) int {
+		return cmp.Or(strings.Compare(a.Hostname, b.Hostname), strings.Compare(a.Name, b.Name))
+	})
+}
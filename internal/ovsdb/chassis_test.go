@@ -0,0 +1,63 @@
+package ovsdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+const chassisList = `5d4b1f0e-1f3a-4f3e-8f5c-0c2a6b7d9e01,node1
+9a8b7c6d-2e3f-4a5b-8c7d-1e2f3a4b5c6d,node2
+1b2c3d4e-5f6a-4b7c-8d9e-0f1a2b3c4d5e,node2
+7e6d5c4b-3a2f-4e1d-9c8b-7a6f5e4d3c2b,old-node
+`
+
+func Test_ParseChassisList(t *testing.T) {
+	assert := require.New(t)
+	chassis, err := ParseChassisList([]byte(chassisList))
+	assert.NoError(err)
+	assert.Len(chassis, 4)
+	assert.Equal(Chassis{Name: "5d4b1f0e-1f3a-4f3e-8f5c-0c2a6b7d9e01", Hostname: "node1"}, chassis[0])
+
+	chassis, err = ParseChassisList([]byte(""))
+	assert.NoError(err)
+	assert.Empty(chassis)
+
+	_, err = ParseChassisList([]byte("name-only\n"))
+	assert.ErrorContains(err, "expected name and hostname")
+}
+
+func Test_AuditChassis(t *testing.T) {
+	assert := require.New(t)
+	chassis, err := ParseChassisList([]byte(chassisList))
+	assert.NoError(err)
+
+	nodes := map[string]string{"node1": "node1", "node2": "node2", "node3": "node3.example.com"}
+	status := AuditChassis(chassis, nodes, []string{"node1", "node2", "node3"})
+	assert.Equal([]ovnoperatorv1.ChassisReference{{Name: "7e6d5c4b-3a2f-4e1d-9c8b-7a6f5e4d3c2b", Hostname: "old-node"}}, status.StaleChassis)
+	assert.Equal([]ovnoperatorv1.ChassisReference{
+		{Name: "1b2c3d4e-5f6a-4b7c-8d9e-0f1a2b3c4d5e", Hostname: "node2"},
+		{Name: "9a8b7c6d-2e3f-4a5b-8c7d-1e2f3a4b5c6d", Hostname: "node2"},
+	}, status.DuplicateChassis)
+	assert.Equal([]string{"node3"}, status.NodesWithoutChassis)
+
+	// chassis reported using the node hostname instead of the node name
+	chassis = append(chassis, Chassis{Name: "c3", Hostname: "node3.example.com"})
+	status = AuditChassis(chassis, nodes, []string{"node1", "node2", "node3"})
+	assert.Empty(status.NodesWithoutChassis)
+}
+
+func Test_RetainDetectedTime(t *testing.T) {
+	assert := require.New(t)
+	detected := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	now := metav1.Now()
+	previous := []ovnoperatorv1.ChassisReference{{Name: "a", Hostname: "old-node", DetectedTime: detected}}
+	current := []ovnoperatorv1.ChassisReference{{Name: "a", Hostname: "old-node"}, {Name: "b", Hostname: "other-node"}}
+	RetainDetectedTime(previous, current, now)
+	assert.Equal(detected, current[0].DetectedTime)
+	assert.Equal(now, current[1].DetectedTime)
+}
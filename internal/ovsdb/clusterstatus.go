@@ -0,0 +1,176 @@
+package ovsdb
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+// ErrMemberNotFound is returned by MemberServerID if no raft member has the address
+var ErrMemberNotFound = errors.New("raft member not found")
+
+var (
+	logIndexRegex = regexp.MustCompile(`^\[(\d+), (\d+)\]$`)
+	// server entries are of the form "e2d5 (e2d5 at tcp:[172.18.0.2]:6643) (self) next_index=2 match_index=22"
+	serverRegex = regexp.MustCompile(`^([0-9a-f]+) \([0-9a-f]+ at ([^)]+)\)( \(self\))?`)
+)
+
+// ParseClusterStatus parses the output of ovs-appctl cluster/status for a clustered ovsdb-server
+func ParseClusterStatus(output []byte) (*ovnoperatorv1.OVNDatabaseStatus, error) {
+	status := &ovnoperatorv1.OVNDatabaseStatus{}
+	inbound := make(map[string]bool)
+	outbound := make(map[string]bool)
+	var inServers bool
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inServers {
+			if match := serverRegex.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+				member := ovnoperatorv1.OVNDatabaseMemberStatus{ServerID: match[1], Address: match[2]}
+				if match[3] != "" {
+					member.Connection = ovnoperatorv1.RaftConnectionSelf
+				}
+				status.Members = append(status.Members, member)
+				continue
+			}
+			inServers = false
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		var err error
+		switch key {
+		case "Server ID":
+			status.ServerID, _, _ = strings.Cut(value, " ")
+		case "Address":
+			status.Address = value
+		case "Role":
+			status.Role = value
+		case "Term":
+			status.Term, err = strconv.ParseInt(value, 10, 64)
+		case "Leader":
+			status.Leader = value
+		case "Election timer":
+			status.ElectionTimer, err = strconv.ParseInt(value, 10, 64)
+		case "Log":
+			match := logIndexRegex.FindStringSubmatch(value)
+			if match == nil {
+				return nil, fmt.Errorf("unexpected log index format %s", value)
+			}
+			status.LogStartIndex, _ = strconv.ParseInt(match[1], 10, 64)
+			status.LogEndIndex, _ = strconv.ParseInt(match[2], 10, 64)
+			status.LogLength = status.LogEndIndex - status.LogStartIndex
+		case "Connections":
+			for _, conn := range strings.Fields(value) {
+				if sid, ok := strings.CutPrefix(conn, "->"); ok {
+					outbound[sid] = true
+				} else if sid, ok := strings.CutPrefix(conn, "<-"); ok {
+					inbound[sid] = true
+				}
+			}
+		case "Servers":
+			inServers = true
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", key, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading cluster status: %w", err)
+	}
+
+	if status.ServerID == "" {
+		return nil, fmt.Errorf("no server id found in cluster status")
+	}
+
+	for i := range status.Members {
+		member := &status.Members[i]
+		if member.Connection == ovnoperatorv1.RaftConnectionSelf {
+			continue
+		}
+		member.Connection = memberConnection(inbound[member.ServerID], outbound[member.ServerID])
+	}
+	return status, nil
+}
+
+func memberConnection(inbound, outbound bool) string {
+	switch {
+	case inbound && outbound:
+		return ovnoperatorv1.RaftConnectionConnected
+	case inbound:
+		return ovnoperatorv1.RaftConnectionInbound
+	case outbound:
+		return ovnoperatorv1.RaftConnectionOutbound
+	default:
+		return ovnoperatorv1.RaftConnectionDisconnected
+	}
+}
+
+// CompareMembers records the node addresses which are not raft members, and raft members which do not
+// belong to one of the node addresses
+func CompareMembers(status *ovnoperatorv1.OVNDatabaseStatus, nodeAddresses []string) {
+	var memberAddresses []string
+	for _, member := range status.Members {
+		memberAddresses = append(memberAddresses, AddressIP(member.Address))
+	}
+
+	status.MissingAddresses = nil
+	for _, address := range nodeAddresses {
+		if !slices.Contains(memberAddresses, address) {
+			status.MissingAddresses = append(status.MissingAddresses, address)
+		}
+	}
+
+	status.UnexpectedAddresses = nil
+	for _, address := range memberAddresses {
+		if !slices.Contains(nodeAddresses, address) {
+			status.UnexpectedAddresses = append(status.UnexpectedAddresses, address)
+		}
+	}
+}
+
+// AddressIP extracts the ip from an ovsdb address of the form tcp:172.18.0.2:6643 or ssl:[fd00::2]:6643
+func AddressIP(address string) string {
+	_, hostPort, found := strings.Cut(address, ":")
+	if !found {
+		return address
+	}
+	host, _, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return hostPort
+	}
+	return host
+}
+
+// MemberServerID returns the server id of the raft member whose ip is exactly address, so that members can be
+// kicked by server id. An error is returned unless exactly one member matches, and the reporting server is never
+// returned as it cannot kick itself
+func MemberServerID(status *ovnoperatorv1.OVNDatabaseStatus, address string) (string, error) {
+	var matches []ovnoperatorv1.OVNDatabaseMemberStatus
+	for _, member := range status.Members {
+		if AddressIP(member.Address) == address {
+			matches = append(matches, member)
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		return "", fmt.Errorf("%w with address %s", ErrMemberNotFound, address)
+	case len(matches) > 1:
+		return "", fmt.Errorf("found %d raft members with address %s", len(matches), address)
+	case matches[0].Connection == ovnoperatorv1.RaftConnectionSelf || matches[0].ServerID == status.ServerID:
+		return "", fmt.Errorf("raft member %s with address %s is the reporting server", matches[0].ServerID, address)
+	}
+	return matches[0].ServerID, nil
+}
@@ -0,0 +1,117 @@
+package ovsdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+const leaderStatus = `e2d5
+Name: OVN_Northbound
+Cluster ID: 6b5b (6b5b2cbe-5d65-4c1c-a2a4-5a7bbd3b9e6c)
+Server ID: e2d5 (e2d5ff61-5b8c-4c9f-9c0c-2bd0e54c8d7b)
+Address: tcp:[172.18.0.2]:6643
+Status: cluster member
+Role: leader
+Term: 3
+Leader: self
+Vote: self
+
+Last Election started 3522 ms ago, reason: timeout
+Last Election won: 3522 ms ago
+Election timer: 1000
+Log: [2, 23]
+Entries not yet committed: 0
+Entries not yet applied: 0
+Connections: ->5f3c <-5f3c <-a8b1
+Disconnections: 1
+Servers:
+    e2d5 (e2d5 at tcp:[172.18.0.2]:6643) (self) next_index=2 match_index=22
+    5f3c (5f3c at tcp:[172.18.0.3]:6643) next_index=23 match_index=22 last msg 102 ms ago
+    a8b1 (a8b1 at tcp:[172.18.0.4]:6643) next_index=23 match_index=22 last msg 102 ms ago
+    c9d0 (c9d0 at tcp:[172.18.0.9]:6643) next_index=23 match_index=0 last msg 86211 ms ago
+`
+
+func Test_ParseClusterStatus(t *testing.T) {
+	assert := require.New(t)
+	status, err := ParseClusterStatus([]byte(leaderStatus))
+	assert.NoError(err)
+	assert.Equal("e2d5", status.ServerID)
+	assert.Equal("tcp:[172.18.0.2]:6643", status.Address)
+	assert.Equal("leader", status.Role)
+	assert.Equal(int64(3), status.Term)
+	assert.Equal("self", status.Leader)
+	assert.Equal(int64(1000), status.ElectionTimer)
+	assert.Equal(int64(2), status.LogStartIndex)
+	assert.Equal(int64(23), status.LogEndIndex)
+	assert.Equal(int64(21), status.LogLength)
+	assert.Equal([]ovnoperatorv1.OVNDatabaseMemberStatus{
+		{ServerID: "e2d5", Address: "tcp:[172.18.0.2]:6643", Connection: ovnoperatorv1.RaftConnectionSelf},
+		{ServerID: "5f3c", Address: "tcp:[172.18.0.3]:6643", Connection: ovnoperatorv1.RaftConnectionConnected},
+		{ServerID: "a8b1", Address: "tcp:[172.18.0.4]:6643", Connection: ovnoperatorv1.RaftConnectionInbound},
+		{ServerID: "c9d0", Address: "tcp:[172.18.0.9]:6643", Connection: ovnoperatorv1.RaftConnectionDisconnected},
+	}, status.Members)
+}
+
+func Test_ParseClusterStatusInvalid(t *testing.T) {
+	assert := require.New(t)
+	_, err := ParseClusterStatus([]byte("2025-01-01T00:00:00Z|00001|unixctl|WARN|failed to connect to /var/run/ovn/ovnnb_db.ctl"))
+	assert.ErrorContains(err, "no server id")
+
+	_, err = ParseClusterStatus([]byte("Server ID: e2d5 (e2d5ff61)\nTerm: three\n"))
+	assert.ErrorContains(err, "error parsing Term")
+}
+
+func Test_CompareMembers(t *testing.T) {
+	assert := require.New(t)
+	status, err := ParseClusterStatus([]byte(leaderStatus))
+	assert.NoError(err)
+	CompareMembers(status, []string{"172.18.0.2", "172.18.0.3", "172.18.0.4", "172.18.0.5"})
+	assert.Equal([]string{"172.18.0.5"}, status.MissingAddresses)
+	assert.Equal([]string{"172.18.0.9"}, status.UnexpectedAddresses)
+}
+
+func Test_AddressIP(t *testing.T) {
+	assert := require.New(t)
+	assert.Equal("172.18.0.2", AddressIP("tcp:[172.18.0.2]:6643"))
+	assert.Equal("172.18.0.2", AddressIP("ssl:172.18.0.2:6643"))
+	assert.Equal("fd00::2", AddressIP("tcp:[fd00::2]:6643"))
+}
+
+func Test_MemberServerID(t *testing.T) {
+	assert := require.New(t)
+	status := &ovnoperatorv1.OVNDatabaseStatus{
+		ServerID: "e2d5",
+		Members: []ovnoperatorv1.OVNDatabaseMemberStatus{
+			{ServerID: "e2d5", Address: "tcp:[10.0.0.2]:6643", Connection: ovnoperatorv1.RaftConnectionSelf},
+			{ServerID: "5f3c", Address: "tcp:[10.0.0.12]:6643", Connection: ovnoperatorv1.RaftConnectionConnected},
+			{ServerID: "a8b1", Address: "tcp:[10.0.0.1]:6643", Connection: ovnoperatorv1.RaftConnectionDisconnected},
+			{ServerID: "c9d0", Address: "ssl:[10.0.100.1]:6643", Connection: ovnoperatorv1.RaftConnectionConnected},
+		},
+	}
+
+	// 10.0.0.1 is a prefix of 10.0.0.12, and matches 10.0.100.1 as a regular expression
+	id, err := MemberServerID(status, "10.0.0.1")
+	assert.NoError(err)
+	assert.Equal("a8b1", id)
+	id, err = MemberServerID(status, "10.0.0.12")
+	assert.NoError(err)
+	assert.Equal("5f3c", id)
+
+	_, err = MemberServerID(status, "10.0.0.3")
+	assert.ErrorIs(err, ErrMemberNotFound)
+	_, err = MemberServerID(status, "10.0.0")
+	assert.ErrorIs(err, ErrMemberNotFound)
+
+	// the reporting server cannot kick itself
+	_, err = MemberServerID(status, "10.0.0.2")
+	assert.Error(err)
+	assert.NotErrorIs(err, ErrMemberNotFound)
+
+	status.Members = append(status.Members, ovnoperatorv1.OVNDatabaseMemberStatus{ServerID: "f00d", Address: "tcp:[10.0.0.1]:6643"})
+	_, err = MemberServerID(status, "10.0.0.1")
+	assert.Error(err)
+	assert.NotErrorIs(err, ErrMemberNotFound)
+}
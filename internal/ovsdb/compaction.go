@@ -0,0 +1,40 @@
+package ovsdb
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+// DefaultCompactionMinInterval is the minimum time between compactions triggered by thresholds when
+// spec.maintenance.compaction.minInterval is not set
+const DefaultCompactionMinInterval = time.Hour
+
+// CompactionReason returns why a database needs to be compacted based on the thresholds in policy, or an
+// empty string if no threshold is exceeded or the database was compacted within the minimum interval
+func CompactionReason(policy ovnoperatorv1.CompactionSpec, status *ovnoperatorv1.OVNDatabaseStatus, now metav1.Time) string {
+	if status == nil {
+		return ""
+	}
+
+	minInterval := policy.MinInterval.Duration
+	if minInterval <= 0 {
+		minInterval = DefaultCompactionMinInterval
+	}
+	if status.LastCompactionTime != nil && now.Before(&metav1.Time{Time: status.LastCompactionTime.Add(minInterval)}) {
+		return ""
+	}
+
+	if policy.FileSizeThreshold != nil && !policy.FileSizeThreshold.IsZero() && status.FileSize > policy.FileSizeThreshold.Value() {
+		return fmt.Sprintf("file size %s exceeds threshold %s",
+			resource.NewQuantity(status.FileSize, resource.BinarySI).String(), policy.FileSizeThreshold.String())
+	}
+	if policy.LogLengthThreshold > 0 && status.LogLength > policy.LogLengthThreshold {
+		return fmt.Sprintf("raft log length %d exceeds threshold %d", status.LogLength, policy.LogLengthThreshold)
+	}
+	return ""
+}
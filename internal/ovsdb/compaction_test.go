@@ -0,0 +1,74 @@
+package ovsdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+func Test_CompactionReason(t *testing.T) {
+	now := metav1.Now()
+	policy := ovnoperatorv1.CompactionSpec{
+		FileSizeThreshold:  ptr.To(resource.MustParse("1Gi")),
+		LogLengthThreshold: 10000,
+	}
+	tests := []struct {
+		name     string
+		policy   ovnoperatorv1.CompactionSpec
+		status   *ovnoperatorv1.OVNDatabaseStatus
+		expected string
+	}{
+		{
+			name:   "no status",
+			policy: policy,
+		},
+		{
+			name:   "no thresholds",
+			status: &ovnoperatorv1.OVNDatabaseStatus{FileSize: 2 << 30, LogLength: 20000},
+		},
+		{
+			name:   "below thresholds",
+			policy: policy,
+			status: &ovnoperatorv1.OVNDatabaseStatus{FileSize: 512 << 20, LogLength: 100},
+		},
+		{
+			name:     "file size exceeded",
+			policy:   policy,
+			status:   &ovnoperatorv1.OVNDatabaseStatus{FileSize: 2 << 30, LogLength: 100},
+			expected: "file size 2Gi exceeds threshold 1Gi",
+		},
+		{
+			name:     "log length exceeded",
+			policy:   policy,
+			status:   &ovnoperatorv1.OVNDatabaseStatus{FileSize: 512 << 20, LogLength: 20000},
+			expected: "raft log length 20000 exceeds threshold 10000",
+		},
+		{
+			name:   "compacted within default min interval",
+			policy: policy,
+			status: &ovnoperatorv1.OVNDatabaseStatus{FileSize: 2 << 30,
+				LastCompactionTime: &metav1.Time{Time: now.Add(-30 * time.Minute)}},
+		},
+		{
+			name: "compacted before min interval",
+			policy: ovnoperatorv1.CompactionSpec{
+				FileSizeThreshold: policy.FileSizeThreshold,
+				MinInterval:       metav1.Duration{Duration: 10 * time.Minute},
+			},
+			status: &ovnoperatorv1.OVNDatabaseStatus{FileSize: 2 << 30,
+				LastCompactionTime: &metav1.Time{Time: now.Add(-30 * time.Minute)}},
+			expected: "file size 2Gi exceeds threshold 1Gi",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, CompactionReason(tc.policy, tc.status, now))
+		})
+	}
+}
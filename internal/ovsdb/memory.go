@@ -0,0 +1,36 @@
+package ovsdb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseMemoryShow parses the output of ovs-appctl memory/show, which reports usage as space separated
+// key:value pairs such as "atoms:4426 cells:5235 monitors:4 raft-log:1270". Values which are not integers
+// are ignored
+func ParseMemoryShow(output []byte) (map[string]int64, error) {
+	memory := make(map[string]int64)
+	for _, field := range strings.Fields(string(output)) {
+		key, value, found := strings.Cut(field, ":")
+		if !found {
+			continue
+		}
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			memory[key] = v
+		}
+	}
+	if len(memory) == 0 {
+		return nil, fmt.Errorf("no memory usage found in output %q", strings.TrimSpace(string(output)))
+	}
+	return memory, nil
+}
+
+// ParseFileSize parses the output of stat -c %s
+func ParseFileSize(output []byte) (int64, error) {
+	size, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing file size %q: %v", strings.TrimSpace(string(output)), err)
+	}
+	return size, nil
+}
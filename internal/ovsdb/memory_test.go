@@ -0,0 +1,29 @@
+package ovsdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseMemoryShow(t *testing.T) {
+	assert := require.New(t)
+	memory, err := ParseMemoryShow([]byte("atoms:4426 cells:5235 monitors:4 n-weak-refs:0 raft-backlog-buffer:0 raft-connections:4 raft-log:1270 sessions:3 txn-history:100 txn-history-atoms:1863\n"))
+	assert.NoError(err)
+	assert.Equal(int64(5235), memory["cells"])
+	assert.Equal(int64(1270), memory["raft-log"])
+	assert.Len(memory, 10)
+
+	_, err = ParseMemoryShow([]byte("ovs-appctl: cannot connect to \"/var/run/ovn/ovnsb_db.ctl\""))
+	assert.Error(err)
+}
+
+func Test_ParseFileSize(t *testing.T) {
+	assert := require.New(t)
+	size, err := ParseFileSize([]byte("1048576\n"))
+	assert.NoError(err)
+	assert.Equal(int64(1048576), size)
+
+	_, err = ParseFileSize([]byte("stat: cannot stat '/etc/ovn/ovnsb_db.db': No such file or directory"))
+	assert.Error(err)
+}
@@ -0,0 +1,17 @@
+package ovsdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseNorthdStatus parses the output of ovn-appctl -t ovn-northd status, and returns the state of the
+// northd instance which is one of active, standby or paused
+func ParseNorthdStatus(output []byte) (string, error) {
+	for _, line := range strings.Split(string(output), "\n") {
+		if value, found := strings.CutPrefix(strings.TrimSpace(line), "Status:"); found {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	return "", fmt.Errorf("no status found in northd output %q", strings.TrimSpace(string(output)))
+}
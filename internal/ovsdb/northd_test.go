@@ -0,0 +1,21 @@
+package ovsdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseNorthdStatus(t *testing.T) {
+	assert := require.New(t)
+	status, err := ParseNorthdStatus([]byte("Status: active\n"))
+	assert.NoError(err)
+	assert.Equal("active", status)
+
+	status, err = ParseNorthdStatus([]byte("Status: standby\n"))
+	assert.NoError(err)
+	assert.Equal("standby", status)
+
+	_, err = ParseNorthdStatus([]byte("ovn-appctl: cannot connect to \"/var/run/ovn/ovn-northd.1.ctl\"\n"))
+	assert.ErrorContains(err, "no status found")
+}
@@ -0,0 +1,90 @@
+package ovsdb
+
+import (
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+// corruptDatabaseSignatures are logged by ovsdb-server and ovsdb-tool when a database file cannot be read
+var corruptDatabaseSignatures = []string{
+	"cannot identify file type",
+	"have SHA-1 hash",
+	"syntax error",
+	"unexpected end of file",
+	"corrupt",
+}
+
+// databaseFiles maps the database file names found in ovn-central logs to database names
+var databaseFiles = map[string]string{
+	"ovnnb_db": ovnoperatorv1.NBDatabase,
+	"ovnsb_db": ovnoperatorv1.SBDatabase,
+}
+
+// CorruptDatabases returns the databases with a corrupt database signature in the logs of ovn-central. Both
+// databases are returned when a signature does not identify the database file
+func CorruptDatabases(logs []byte) []string {
+	var databases []string
+	for _, line := range strings.Split(string(logs), "\n") {
+		if !slices.ContainsFunc(corruptDatabaseSignatures, func(signature string) bool {
+			return strings.Contains(line, signature)
+		}) {
+			continue
+		}
+		identified := false
+		for file, database := range databaseFiles {
+			if strings.Contains(line, file) {
+				identified = true
+				databases = append(databases, database)
+			}
+		}
+		if !identified {
+			databases = append(databases, ovnoperatorv1.NBDatabase, ovnoperatorv1.SBDatabase)
+		}
+	}
+	slices.Sort(databases)
+	return slices.Compact(databases)
+}
+
+// MergeRaftRecoveries combines the recoveries in progress with the broken members detected by the latest
+// healthcheck. Recoveries which have started are retained until they complete, while members detected
+// previously which are no longer broken are dropped. Newly detected members start in the Detected phase
+func MergeRaftRecoveries(previous, detected []ovnoperatorv1.RaftMemberRecoveryStatus, now metav1.Time) []ovnoperatorv1.RaftMemberRecoveryStatus {
+	var merged []ovnoperatorv1.RaftMemberRecoveryStatus
+	for _, p := range previous {
+		if p.Phase == ovnoperatorv1.RaftRecoveryPhaseRecovering || p.Phase == ovnoperatorv1.RaftRecoveryPhaseRejoining {
+			merged = append(merged, p)
+			continue
+		}
+		idx := slices.IndexFunc(detected, func(d ovnoperatorv1.RaftMemberRecoveryStatus) bool {
+			return d.Node == p.Node
+		})
+		if idx == -1 {
+			continue
+		}
+		p.Address = detected[idx].Address
+		p.Databases = detected[idx].Databases
+		p.Reason = detected[idx].Reason
+		merged = append(merged, p)
+	}
+
+	for _, d := range detected {
+		if slices.ContainsFunc(merged, func(m ovnoperatorv1.RaftMemberRecoveryStatus) bool {
+			return m.Node == d.Node
+		}) {
+			continue
+		}
+		d.Phase = ovnoperatorv1.RaftRecoveryPhaseDetected
+		d.DetectedTime = now
+		d.LastTransitionTime = now
+		merged = append(merged, d)
+	}
+
+	slices.SortFunc(merged, func(a, b ovnoperatorv1.RaftMemberRecoveryStatus) int {
+		return strings.Compare(a.Node, b.Node)
+	})
+	return merged
+}
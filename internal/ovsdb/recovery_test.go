@@ -0,0 +1,57 @@
+package ovsdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+func Test_CorruptDatabases(t *testing.T) {
+	assert := require.New(t)
+	logs := []byte(`2025-01-01T00:00:00Z|00001|ovsdb_server|INFO|ovsdb-server (Open vSwitch) 3.3.2
+ovsdb-server: ovsdb error: /etc/ovn/ovnsb_db.db: cannot identify file type
+`)
+	assert.Equal([]string{ovnoperatorv1.SBDatabase}, CorruptDatabases(logs))
+
+	logs = []byte(`ovsdb-server: ovsdb error: 1024 bytes starting at offset 4096 have SHA-1 hash 7f3a but should have hash 3b1c`)
+	assert.Equal([]string{ovnoperatorv1.NBDatabase, ovnoperatorv1.SBDatabase}, CorruptDatabases(logs))
+
+	assert.Empty(CorruptDatabases([]byte("ovn-northd is running\novsdb-server is running")))
+}
+
+func Test_MergeRaftRecoveries(t *testing.T) {
+	assert := require.New(t)
+	now := metav1.Now()
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	previous := []ovnoperatorv1.RaftMemberRecoveryStatus{
+		{Node: "node1", Address: "10.0.0.1", Databases: []string{ovnoperatorv1.NBDatabase}, Reason: ovnoperatorv1.RaftRecoveryDisconnectedReason,
+			Phase: ovnoperatorv1.RaftRecoveryPhaseAwaitingApproval, DetectedTime: earlier, LastTransitionTime: earlier},
+		{Node: "node2", Address: "10.0.0.2", Databases: []string{ovnoperatorv1.SBDatabase}, Reason: ovnoperatorv1.RaftRecoveryDisconnectedReason,
+			Phase: ovnoperatorv1.RaftRecoveryPhaseDetected, DetectedTime: earlier, LastTransitionTime: earlier},
+		{Node: "node3", Address: "10.0.0.3", Databases: []string{ovnoperatorv1.SBDatabase}, Reason: ovnoperatorv1.RaftRecoveryCorruptDatabaseReason,
+			Phase: ovnoperatorv1.RaftRecoveryPhaseRejoining, DetectedTime: earlier, LastTransitionTime: earlier},
+	}
+	detected := []ovnoperatorv1.RaftMemberRecoveryStatus{
+		{Node: "node4", Address: "10.0.0.4", Databases: []string{ovnoperatorv1.NBDatabase}, Reason: ovnoperatorv1.RaftRecoveryDisconnectedReason},
+		{Node: "node1", Address: "10.0.0.1", Databases: []string{ovnoperatorv1.NBDatabase, ovnoperatorv1.SBDatabase}, Reason: ovnoperatorv1.RaftRecoveryCorruptDatabaseReason},
+	}
+
+	merged := MergeRaftRecoveries(previous, detected, now)
+	assert.Len(merged, 3)
+	// still broken, approval is retained
+	assert.Equal("node1", merged[0].Node)
+	assert.Equal(ovnoperatorv1.RaftRecoveryPhaseAwaitingApproval, merged[0].Phase)
+	assert.Equal(earlier, merged[0].DetectedTime)
+	assert.Equal(ovnoperatorv1.RaftRecoveryCorruptDatabaseReason, merged[0].Reason)
+	assert.Len(merged[0].Databases, 2)
+	// node2 recovered on its own and is dropped, rejoining node3 is retained
+	assert.Equal("node3", merged[1].Node)
+	assert.Equal(ovnoperatorv1.RaftRecoveryPhaseRejoining, merged[1].Phase)
+	assert.Equal("node4", merged[2].Node)
+	assert.Equal(ovnoperatorv1.RaftRecoveryPhaseDetected, merged[2].Phase)
+	assert.Equal(now, merged[2].DetectedTime)
+}
@@ -0,0 +1,201 @@
+package pki
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	// CADuration matches the validity of the CA previously generated by the kube-ovn-tls template
+	CADuration = 10 * 365 * 24 * time.Hour
+	// LeafDuration is the validity of certificates issued by the CA
+	LeafDuration = 365 * 24 * time.Hour
+	// LeafRenewBefore is the time before expiry at which leaf certificates are rotated
+	LeafRenewBefore = 30 * 24 * time.Hour
+
+	// OVNCACommonName matches the CA previously generated by the kube-ovn-tls template
+	OVNCACommonName = "ovn-ca"
+	OVNCommonName   = "ovn"
+
+	rsaKeySize = 2048
+)
+
+// KeyPair is a PEM encoded certificate and private key
+type KeyPair struct {
+	Cert []byte
+	Key  []byte
+}
+
+// GenerateCA generates a self signed CA
+func GenerateCA(commonName string, now time.Time) (KeyPair, error) {
+	key, err := rsa.GenerateKey(rand.Reader, rsaKeySize)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("error generating ca key: %w", err)
+	}
+	template, err := certificateTemplate(commonName, now, CADuration)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+	return signCertificate(template, template, key, key)
+}
+
+// GenerateLeaf generates a certificate signed by the CA, which is used by ovn components as both
+// server and client certificate
+func GenerateLeaf(ca KeyPair, now time.Time) (KeyPair, error) {
+	return issueCertificate(ca, OVNCommonName, nil, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}, now)
+}
+
+// GenerateServingCert generates a server certificate signed by the CA for the given dns names
+func GenerateServingCert(ca KeyPair, dnsNames []string, now time.Time) (KeyPair, error) {
+	if len(dnsNames) == 0 {
+		return KeyPair{}, errors.New("at least one dns name is needed for a serving certificate")
+	}
+	return issueCertificate(ca, dnsNames[0], dnsNames, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, now)
+}
+
+func issueCertificate(ca KeyPair, commonName string, dnsNames []string, usages []x509.ExtKeyUsage, now time.Time) (KeyPair, error) {
+	caCert, caKey, err := ParseKeyPair(ca)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("error parsing ca: %w", err)
+	}
+	key, err := rsa.GenerateKey(rand.Reader, rsaKeySize)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("error generating key for %s: %w", commonName, err)
+	}
+	template, err := certificateTemplate(commonName, now, LeafDuration)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	// leaf certificates never outlive the issuing ca
+	if template.NotAfter.After(caCert.NotAfter) {
+		template.NotAfter = caCert.NotAfter
+	}
+	template.DNSNames = dnsNames
+	template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	template.ExtKeyUsage = usages
+	return signCertificate(template, caCert, key, caKey)
+}
+
+// ParseKeyPair decodes a PEM encoded certificate and RSA private key
+func ParseKeyPair(pair KeyPair) (*x509.Certificate, crypto.Signer, error) {
+	cert, err := ParseCertificate(pair.Cert)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, _ := pem.Decode(pair.Key)
+	if block == nil {
+		return nil, nil, errors.New("no pem block found in private key")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing private key: %w", err)
+	}
+	return cert, key, nil
+}
+
+// ParseCertificate decodes the first certificate in a PEM encoded bundle
+func ParseCertificate(data []byte) (*x509.Certificate, error) {
+	certs, err := ParseCertificates(data)
+	if err != nil {
+		return nil, err
+	}
+	return certs[0], nil
+}
+
+// ParseCertificates decodes all certificates in a PEM encoded bundle
+func ParseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found in pem data")
+	}
+	return certs, nil
+}
+
+// Verify checks the leaf certificate was issued by one of the CAs in the bundle and is valid at the given time
+func Verify(leaf *x509.Certificate, caBundle []*x509.Certificate, now time.Time) error {
+	roots := x509.NewCertPool()
+	for _, ca := range caBundle {
+		roots.AddCert(ca)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
+
+// NeedsRotation checks if the certificate expires within the renewal window
+func NeedsRotation(cert *x509.Certificate, now time.Time) bool {
+	return now.Add(LeafRenewBefore).After(cert.NotAfter)
+}
+
+// EncodeCertificates returns the PEM encoded bundle of certificates, skipping duplicates
+func EncodeCertificates(certs ...*x509.Certificate) []byte {
+	var bundle bytes.Buffer
+	for i, cert := range certs {
+		duplicate := false
+		for _, previous := range certs[:i] {
+			if previous.Equal(cert) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			_ = pem.Encode(&bundle, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+		}
+	}
+	return bundle.Bytes()
+}
+
+func certificateTemplate(commonName string, now time.Time, duration time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("error generating serial number: %w", err)
+	}
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		// allow for clock skew between nodes
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(duration),
+	}, nil
+}
+
+func signCertificate(template, parent *x509.Certificate, key *rsa.PrivateKey, parentKey crypto.Signer) (KeyPair, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("error signing certificate %s: %w", template.Subject.CommonName, err)
+	}
+	return KeyPair{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+	}, nil
+}
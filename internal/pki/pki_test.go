@@ -0,0 +1,71 @@
+package pki
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GenerateLeaf(t *testing.T) {
+	assert := require.New(t)
+	now := time.Now()
+	ca, err := GenerateCA(OVNCACommonName, now)
+	assert.NoError(err)
+	leaf, err := GenerateLeaf(ca, now)
+	assert.NoError(err)
+
+	caCert, _, err := ParseKeyPair(ca)
+	assert.NoError(err)
+	leafCert, _, err := ParseKeyPair(leaf)
+	assert.NoError(err)
+	assert.True(caCert.IsCA)
+	assert.Equal("ovn", leafCert.Subject.CommonName)
+	assert.NoError(Verify(leafCert, []*x509.Certificate{caCert}, now))
+	assert.False(NeedsRotation(leafCert, now))
+	assert.True(NeedsRotation(leafCert, now.Add(LeafDuration-LeafRenewBefore+time.Hour)))
+
+	otherCA, err := GenerateCA(OVNCACommonName, now)
+	assert.NoError(err)
+	otherCACert, err := ParseCertificate(otherCA.Cert)
+	assert.NoError(err)
+	assert.Error(Verify(leafCert, []*x509.Certificate{otherCACert}, now), "expected leaf to be rejected by an unrelated ca")
+	assert.NoError(Verify(leafCert, []*x509.Certificate{otherCACert, caCert}, now), "expected leaf to be accepted by a ca bundle")
+}
+
+func Test_EncodeCertificates(t *testing.T) {
+	assert := require.New(t)
+	now := time.Now()
+	var certs []*x509.Certificate
+	for range 2 {
+		ca, err := GenerateCA(OVNCACommonName, now)
+		assert.NoError(err)
+		cert, err := ParseCertificate(ca.Cert)
+		assert.NoError(err)
+		certs = append(certs, cert)
+	}
+
+	bundle := EncodeCertificates(certs[0], certs[1], certs[0])
+	decoded, err := ParseCertificates(bundle)
+	assert.NoError(err)
+	assert.Len(decoded, 2, "expected duplicate certificates to be removed from the bundle")
+	assert.True(decoded[0].Equal(certs[0]))
+	assert.True(decoded[1].Equal(certs[1]))
+}
+
+func Test_GenerateServingCert(t *testing.T) {
+	assert := require.New(t)
+	now := time.Now()
+	ca, err := GenerateCA("webhook-ca", now)
+	assert.NoError(err)
+	serving, err := GenerateServingCert(ca, []string{"webhook.kube-system.svc", "webhook.kube-system"}, now)
+	assert.NoError(err)
+	cert, err := ParseCertificate(serving.Cert)
+	assert.NoError(err)
+	assert.NoError(cert.VerifyHostname("webhook.kube-system.svc"))
+	assert.Error(cert.VerifyHostname("other.kube-system.svc"))
+
+	_, err = GenerateServingCert(ca, nil, now)
+	assert.Error(err, "expected dns names to be required")
+}
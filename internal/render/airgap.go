@@ -0,0 +1,91 @@
+package render
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+const (
+	// natGatewayConfigMapName contains the image used for vpc nat gateway pods created by kube-ovn-controller
+	natGatewayConfigMapName = "ovn-vpc-nat-config"
+	imageArgPrefix          = "--image="
+)
+
+// ApplyAirgap rewrites images in rendered objects onto the registry address and attaches the registry image
+// pull secrets to all service accounts. Objects are returned unchanged if airgap mode is not enabled
+func ApplyAirgap(objs []client.Object, config *ovnoperatorv1.Configuration) []client.Object {
+	if !config.Spec.Airgap.Enabled {
+		return objs
+	}
+
+	rewrite := func(image string) string {
+		return rewriteImage(image, config.Spec.Global.Registry.Address, config.Spec.Airgap.PathMappings)
+	}
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *appsv1.Deployment:
+			rewritePodSpecImages(&o.Spec.Template.Spec, rewrite)
+		case *appsv1.DaemonSet:
+			rewritePodSpecImages(&o.Spec.Template.Spec, rewrite)
+		case *corev1.ConfigMap:
+			if image, ok := o.Data["image"]; ok && o.GetName() == natGatewayConfigMapName {
+				o.Data["image"] = rewrite(image)
+			}
+		case *corev1.ServiceAccount:
+			for _, secret := range config.Spec.Global.Registry.ImagePullSecrets {
+				ref := corev1.LocalObjectReference{Name: secret}
+				if secret != "" && !slices.Contains(o.ImagePullSecrets, ref) {
+					o.ImagePullSecrets = append(o.ImagePullSecrets, ref)
+				}
+			}
+		}
+	}
+	return objs
+}
+
+// ApplyAirgapImages rewrites resolved images the same way as images in rendered objects, so the images recorded in
+// the status match the images being run. Images are returned unchanged if airgap mode is not enabled
+func ApplyAirgapImages(images map[string]string, config *ovnoperatorv1.Configuration) map[string]string {
+	if !config.Spec.Airgap.Enabled {
+		return images
+	}
+	for k, image := range images {
+		images[k] = rewriteImage(image, config.Spec.Global.Registry.Address, config.Spec.Airgap.PathMappings)
+	}
+	return images
+}
+
+func rewritePodSpecImages(podSpec *corev1.PodSpec, rewrite func(string) string) {
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			containers[i].Image = rewrite(containers[i].Image)
+			// kube-ovn-controller is passed the image used for pods it creates
+			for j, arg := range containers[i].Args {
+				if strings.HasPrefix(arg, imageArgPrefix) {
+					containers[i].Args[j] = imageArgPrefix + rewrite(strings.TrimPrefix(arg, imageArgPrefix))
+				}
+			}
+		}
+	}
+}
+
+// rewriteImage applies the first matching path mapping. Images without a matching mapping which are not
+// already on the registry address are moved onto it, retaining only the image name and tag or digest
+func rewriteImage(image, address string, mappings []ovnoperatorv1.ImagePathMapping) string {
+	for _, mapping := range mappings {
+		if mapping.Source != "" && strings.HasPrefix(image, mapping.Source) {
+			return mapping.Target + strings.TrimPrefix(image, mapping.Source)
+		}
+	}
+	if image == "" || strings.HasPrefix(image, strings.TrimSuffix(address, "/")+"/") {
+		return image
+	}
+	return strings.TrimSuffix(address, "/") + "/" + path.Base(image)
+}
@@ -0,0 +1,84 @@
+package render
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/templates"
+)
+
+func Test_RewriteImage(t *testing.T) {
+	assert := require.New(t)
+	mappings := []ovnoperatorv1.ImagePathMapping{
+		{Source: "docker.io/kubeovn/", Target: "registry.local/mirror/kubeovn/"},
+	}
+	assert.Equal("registry.local/mirror/kubeovn/kube-ovn:v1.14.0",
+		rewriteImage("docker.io/kubeovn/kube-ovn:v1.14.0", "registry.local", mappings))
+	assert.Equal("registry.local/kube-ovn:v1.14.0",
+		rewriteImage("registry.local/kube-ovn:v1.14.0", "registry.local", mappings))
+	assert.Equal("registry.local/vpc-nat-gateway:v1.14.0",
+		rewriteImage("quay.io/other/vpc-nat-gateway:v1.14.0", "registry.local/", nil))
+}
+
+func Test_AirgapRendering(t *testing.T) {
+	assert := require.New(t)
+	c, err := generateConfigObject()
+	assert.NoError(err, "expected no error while generating config object")
+	c.Spec.Global.Registry.Address = "registry.local/kubeovn"
+	c.Spec.Global.Registry.ImagePullSecrets = []string{"registry-creds"}
+	c.Spec.Global.Images.Components.OVNCentral.Repository = "docker.io/kubeovn/kube-ovn"
+	c.Spec.Airgap.Enabled = true
+
+	objs, err := GenerateObjects(templates.DaemonsetList, c, &appsv1.DaemonSet{}, nil, "v1.14.0", "caCertString")
+	assert.NoError(err)
+	objs = ApplyAirgap(objs, c)
+	for _, obj := range objs {
+		daemonset := obj.(*appsv1.DaemonSet)
+		for _, container := range slices.Concat(daemonset.Spec.Template.Spec.InitContainers, daemonset.Spec.Template.Spec.Containers) {
+			assert.True(strings.HasPrefix(container.Image, "registry.local/kubeovn/"), "unexpected image %s", container.Image)
+			if container.Name == "pinger" {
+				assert.Contains(container.Args, "--external-address=")
+				assert.Contains(container.Args, "--external-dns=")
+			}
+		}
+	}
+
+	objs, err = GenerateObjects(templates.ServiceAccountList, c, &corev1.ServiceAccount{}, nil, "v1.14.0", "caCertString")
+	assert.NoError(err)
+	objs = ApplyAirgap(objs, c)
+	for _, obj := range objs {
+		sa := obj.(*corev1.ServiceAccount)
+		assert.Equal([]corev1.LocalObjectReference{{Name: "registry-creds"}}, sa.ImagePullSecrets, "unexpected pull secrets on %s", sa.Name)
+	}
+}
+
+func Test_AirgapImages(t *testing.T) {
+	assert := require.New(t)
+	c, err := generateConfigObject()
+	assert.NoError(err, "expected no error while generating config object")
+	c.Spec.Airgap.PathMappings = []ovnoperatorv1.ImagePathMapping{
+		{Source: "docker.io/kubeovn/", Target: "registry.local/mirror/kubeovn/"},
+	}
+
+	// images are only rewritten in airgap mode
+	images := ApplyAirgapImages(ResolveImages(c, "v1.14.0"), c)
+	assert.Equal("docker.io/kubeovn/kube-ovn:v1.14.0", images[OVNCentralImageKey])
+
+	// the recorded images match the images in rendered objects
+	c.Spec.Airgap.Enabled = true
+	images = ApplyAirgapImages(ResolveImages(c, "v1.14.0"), c)
+	assert.Equal("registry.local/mirror/kubeovn/kube-ovn:v1.14.0", images[OVNCentralImageKey])
+	objs, err := GenerateObjects(templates.DeploymentList, c, &appsv1.Deployment{}, nil, "v1.14.0", "caCertString")
+	assert.NoError(err)
+	for _, obj := range ApplyAirgap(objs, c) {
+		if deployment := obj.(*appsv1.Deployment); deployment.Name == "ovn-central" {
+			assert.Equal(images[OVNCentralImageKey], deployment.Spec.Template.Spec.Containers[0].Image)
+		}
+	}
+}
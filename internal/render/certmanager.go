@@ -0,0 +1,45 @@
+package render
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+const ovnTLSSecretName = "kube-ovn-tls"
+
+// ovnTLSKeys maps the keys written by cert-manager onto the file names kube-ovn expects in /var/run/tls
+var ovnTLSKeys = []corev1.KeyToPath{
+	{Key: corev1.ServiceAccountRootCAKey, Path: "cacert"},
+	{Key: corev1.TLSCertKey, Path: "cert"},
+	{Key: corev1.TLSPrivateKeyKey, Path: "key"},
+}
+
+// ApplyCertManager updates pod volumes mounting the kube-ovn-tls secret to read the keys issued by cert-manager.
+// Objects are returned unchanged if cert-manager integration is not enabled
+func ApplyCertManager(objs []client.Object, config *ovnoperatorv1.Configuration) []client.Object {
+	if !config.Spec.CertManager.Enabled {
+		return objs
+	}
+
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *appsv1.Deployment:
+			mapOVNTLSKeys(&o.Spec.Template.Spec)
+		case *appsv1.DaemonSet:
+			mapOVNTLSKeys(&o.Spec.Template.Spec)
+		}
+	}
+	return objs
+}
+
+func mapOVNTLSKeys(podSpec *corev1.PodSpec) {
+	for i := range podSpec.Volumes {
+		secret := podSpec.Volumes[i].Secret
+		if secret != nil && secret.SecretName == ovnTLSSecretName {
+			secret.Items = ovnTLSKeys
+		}
+	}
+}
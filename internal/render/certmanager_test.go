@@ -0,0 +1,66 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/templates"
+)
+
+func Test_CertManagerRendering(t *testing.T) {
+	assert := require.New(t)
+	c, err := generateConfigObject()
+	assert.NoError(err, "expected no error while generating config object")
+	c.Namespace = "kube-system"
+	c.Spec.Networking.EnableSSL = ptr.To(true)
+	c.Spec.CertManager.Enabled = true
+
+	objs, err := GenerateObjects(templates.CertificateList, c, &unstructured.Unstructured{}, nil, "v1.14.0", "caCertString")
+	assert.NoError(err)
+	var names []string
+	for _, obj := range objs {
+		names = append(names, obj.GetObjectKind().GroupVersionKind().Kind+"/"+obj.GetName())
+	}
+	assert.Equal([]string{"Issuer/kube-ovn-selfsigned", "Certificate/kube-ovn-ca", "Issuer/kube-ovn-ca",
+		"Certificate/kube-ovn-tls", "Certificate/kube-ovn-webhook"}, names)
+
+	// an existing issuer replaces the self signed ca
+	c.Spec.CertManager.IssuerRef = &ovnoperatorv1.CertManagerIssuerReference{Name: "cluster-ca", Kind: "ClusterIssuer", Group: "cert-manager.io"}
+	objs, err = GenerateObjects(templates.CertificateList, c, &unstructured.Unstructured{}, nil, "v1.14.0", "caCertString")
+	assert.NoError(err)
+	assert.Len(objs, 2)
+	for _, obj := range objs {
+		issuer, _, err := unstructured.NestedStringMap(obj.(*unstructured.Unstructured).Object, "spec", "issuerRef")
+		assert.NoError(err)
+		assert.Equal(map[string]string{"name": "cluster-ca", "kind": "ClusterIssuer", "group": "cert-manager.io"}, issuer)
+	}
+
+	objs, err = GenerateObjects(templates.DeploymentList, c, &appsv1.Deployment{}, nil, "v1.14.0", "caCertString")
+	assert.NoError(err)
+	objs = ApplyCertManager(objs, c)
+	for _, obj := range objs {
+		for _, volume := range obj.(*appsv1.Deployment).Spec.Template.Spec.Volumes {
+			if volume.Secret == nil {
+				continue
+			}
+			switch volume.Secret.SecretName {
+			case "kube-ovn-tls":
+				assert.Equal(ovnTLSKeys, volume.Secret.Items)
+			case "webhook-certs":
+				assert.Fail("expected kube-ovn-webhook to use the cert-manager issued certificate")
+			}
+		}
+	}
+
+	objs, err = GenerateObjects(templates.ValidatingWebhookConfigurationList, c, &admissionregistrationv1.ValidatingWebhookConfiguration{}, nil, "v1.14.0", "caCertString")
+	assert.NoError(err)
+	assert.Len(objs, 1)
+	assert.Equal("kube-system/kube-ovn-webhook", objs[0].GetAnnotations()["cert-manager.io/inject-ca-from"])
+	assert.Empty(objs[0].(*admissionregistrationv1.ValidatingWebhookConfiguration).Webhooks[0].ClientConfig.CABundle)
+}
@@ -0,0 +1,60 @@
+package render
+
+import (
+	"fmt"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+// keys used for resolved images in templates and status.images
+const (
+	KubeOVNImageKey           = "kubeovn"
+	OVNCentralImageKey        = "ovnCentral"
+	OVSOVNImageKey            = "ovsOVN"
+	KubeOVNControllerImageKey = "kubeOvnController"
+	KubeOVNCNIImageKey        = "kubeOvnCNI"
+	KubeOVNPingerImageKey     = "kubeOvnPinger"
+	KubeOVNMonitorImageKey    = "kubeOvnMonitor"
+	NATGatewayImageKey        = "natGateway"
+)
+
+// ResolveImages returns the full image reference for each component. Component overrides take precedence
+// over the kube-ovn image, and a digest takes precedence over a tag. The kube-ovn image is always tagged with version,
+// so that the images rolled out match the version recorded in the status
+func ResolveImages(config *ovnoperatorv1.Configuration, version string) map[string]string {
+	images := config.Spec.Global.Images
+	kubeovnTag := version
+	natGatewayTag := images.NATGatewayImage.Tag
+	if natGatewayTag == "" {
+		natGatewayTag = kubeovnTag
+	}
+
+	address := config.Spec.Global.Registry.Address
+	defaultImage := ovnoperatorv1.ComponentImage{Repository: images.KubeOVNImage.Repository, Tag: kubeovnTag}
+	natGatewayImage := ovnoperatorv1.ComponentImage{Repository: images.NATGatewayImage.Repository, Tag: natGatewayTag}
+	return map[string]string{
+		KubeOVNImageKey:           imageReference(address, ovnoperatorv1.ComponentImage{}, defaultImage),
+		OVNCentralImageKey:        imageReference(address, images.Components.OVNCentral, defaultImage),
+		OVSOVNImageKey:            imageReference(address, images.Components.OVSOVN, defaultImage),
+		KubeOVNControllerImageKey: imageReference(address, images.Components.KubeOVNController, defaultImage),
+		KubeOVNCNIImageKey:        imageReference(address, images.Components.KubeOVNCNI, defaultImage),
+		KubeOVNPingerImageKey:     imageReference(address, images.Components.KubeOVNPinger, defaultImage),
+		KubeOVNMonitorImageKey:    imageReference(address, images.Components.KubeOVNMonitor, defaultImage),
+		NATGatewayImageKey:        imageReference(address, images.Components.NATGateway, natGatewayImage),
+	}
+}
+
+func imageReference(address string, image, defaultImage ovnoperatorv1.ComponentImage) string {
+	repository := image.Repository
+	if repository == "" {
+		repository = defaultImage.Repository
+	}
+	if image.Digest != "" {
+		return fmt.Sprintf("%s/%s@%s", address, repository, image.Digest)
+	}
+	tag := image.Tag
+	if tag == "" {
+		tag = defaultImage.Tag
+	}
+	return fmt.Sprintf("%s/%s:%s", address, repository, tag)
+}
@@ -0,0 +1,69 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// EncodeManifests serialises objects into a gzip compressed multi document yaml stream. The sha256 checksum
+// of the uncompressed manifests is returned to allow identical manifest sets to be identified
+func EncodeManifests(objs []client.Object) ([]byte, string, error) {
+	var manifests bytes.Buffer
+	for _, obj := range objs {
+		content, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, "", fmt.Errorf("error marshalling object %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+		}
+		manifests.WriteString("---\n")
+		manifests.Write(content)
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(manifests.Bytes()); err != nil {
+		return nil, "", fmt.Errorf("error compressing manifests: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("error compressing manifests: %w", err)
+	}
+	return compressed.Bytes(), fmt.Sprintf("%x", sha256.Sum256(manifests.Bytes())), nil
+}
+
+// DecodeManifests reverses EncodeManifests and returns the objects in the order they were encoded
+func DecodeManifests(data []byte) ([]*unstructured.Unstructured, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing manifests: %w", err)
+	}
+	defer reader.Close()
+
+	var objs []*unstructured.Unstructured
+	yamlReader := utilyaml.NewYAMLReader(bufio.NewReader(reader))
+	for {
+		doc, err := yamlReader.Read()
+		if errors.Is(err, io.EOF) {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading manifests: %w", err)
+		}
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(doc, &obj.Object); err != nil {
+			return nil, fmt.Errorf("error unmarshalling manifest: %w", err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+}
@@ -0,0 +1,29 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ManifestEncoding(t *testing.T) {
+	assert := require.New(t)
+	objs := generateDeployments(t)
+
+	data, checksum, err := EncodeManifests(objs)
+	assert.NoError(err)
+	assert.NotEmpty(checksum)
+
+	_, sameChecksum, err := EncodeManifests(objs)
+	assert.NoError(err)
+	assert.Equal(checksum, sameChecksum, "expected checksum to be stable for identical objects")
+
+	decodedObjs, err := DecodeManifests(data)
+	assert.NoError(err)
+	assert.Len(decodedObjs, len(objs))
+	for i := range objs {
+		assert.Equal(objs[i].GetName(), decodedObjs[i].GetName())
+		assert.Equal(objs[i].GetNamespace(), decodedObjs[i].GetNamespace())
+		assert.Equal("Deployment", decodedObjs[i].GetKind())
+	}
+}
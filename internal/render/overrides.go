@@ -0,0 +1,103 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+// ApplyOverrides applies user defined patches from the configuration to matching rendered objects.
+// objects which are not targeted by any override are returned unchanged
+func ApplyOverrides(objs []client.Object, overrides []ovnoperatorv1.ObjectOverride) ([]client.Object, error) {
+	if len(overrides) == 0 {
+		return objs, nil
+	}
+
+	returnedObjects := make([]client.Object, 0, len(objs))
+	for _, obj := range objs {
+		patchedObj := obj
+		for _, override := range overrides {
+			if !overrideMatches(patchedObj, override.Target) {
+				continue
+			}
+			var err error
+			patchedObj, err = applyOverride(patchedObj, override)
+			if err != nil {
+				return nil, fmt.Errorf("error applying %s override to object %s/%s: %w", override.PatchType, obj.GetNamespace(), obj.GetName(), err)
+			}
+		}
+		returnedObjects = append(returnedObjects, patchedObj)
+	}
+	return returnedObjects, nil
+}
+
+func overrideMatches(obj client.Object, target ovnoperatorv1.OverrideTarget) bool {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if gvk.Group != target.Group || gvk.Version != target.Version || gvk.Kind != target.Kind {
+		return false
+	}
+	if target.Namespace != "" && target.Namespace != obj.GetNamespace() {
+		return false
+	}
+	return obj.GetName() == target.Name
+}
+
+func applyOverride(obj client.Object, override ovnoperatorv1.ObjectOverride) (client.Object, error) {
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling object: %w", err)
+	}
+
+	patch, err := yaml.YAMLToJSON([]byte(override.Patch))
+	if err != nil {
+		return nil, fmt.Errorf("error converting patch to json: %w", err)
+	}
+
+	var patched []byte
+	switch override.PatchType {
+	case ovnoperatorv1.JSON6902PatchType:
+		jsonPatch, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding json6902 patch: %w", err)
+		}
+		patched, err = jsonPatch.Apply(original)
+		if err != nil {
+			return nil, err
+		}
+	case ovnoperatorv1.StrategicMergePatchType, "":
+		// unstructured objects, such as cert-manager kinds, have no schema to merge lists with, so a json merge
+		// patch is applied instead
+		if _, ok := obj.(*unstructured.Unstructured); ok {
+			patched, err = jsonpatch.MergePatch(original, patch)
+		} else {
+			patched, err = strategicpatch.StrategicMergePatch(original, patch, obj)
+		}
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported patch type %s", override.PatchType)
+	}
+
+	newObj := InitialiseNewObject(obj)
+	if newObj == nil {
+		return nil, fmt.Errorf("could not initialise new object for type: %T", obj)
+	}
+	if err := json.Unmarshal(patched, newObj); err != nil {
+		return nil, fmt.Errorf("error unmarshalling patched object: %w", err)
+	}
+
+	// patches should not be able to move an object outside the scope of the override
+	if newObj.GetName() != obj.GetName() || newObj.GetNamespace() != obj.GetNamespace() ||
+		newObj.GetObjectKind().GroupVersionKind() != obj.GetObjectKind().GroupVersionKind() {
+		return nil, fmt.Errorf("patch must not change the apiVersion, kind, name or namespace of the object")
+	}
+	return newObj, nil
+}
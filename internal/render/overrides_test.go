@@ -0,0 +1,156 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/templates"
+)
+
+func generateDeployments(t *testing.T) []client.Object {
+	c, err := generateConfigObject()
+	require.NoError(t, err, "expected no error while generating config object")
+	objs, err := GenerateObjects(templates.DeploymentList, c, &appsv1.Deployment{}, nil, "v1.14.0", "caCertString")
+	require.NoError(t, err)
+	return objs
+}
+
+func Test_StrategicMergeOverride(t *testing.T) {
+	assert := require.New(t)
+	objs := generateDeployments(t)
+
+	overrides := []ovnoperatorv1.ObjectOverride{
+		{
+			Target: ovnoperatorv1.OverrideTarget{
+				GroupVersionKind: ovnoperatorv1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+				Name:             "kube-ovn-controller",
+			},
+			PatchType: ovnoperatorv1.StrategicMergePatchType,
+			Patch: `metadata:
+  annotations:
+    example.com/patched: "true"
+spec:
+  template:
+    spec:
+      containers:
+        - name: kube-ovn-controller
+          env:
+            - name: EXTRA_ENV
+              value: extra`,
+		},
+	}
+
+	patchedObjs, err := ApplyOverrides(objs, overrides)
+	assert.NoError(err)
+	assert.Len(patchedObjs, len(objs))
+	var found bool
+	for _, obj := range patchedObjs {
+		deployment, ok := obj.(*appsv1.Deployment)
+		assert.True(ok)
+		if deployment.GetName() != "kube-ovn-controller" {
+			assert.NotContains(deployment.GetAnnotations(), "example.com/patched")
+			continue
+		}
+		found = true
+		assert.Equal("true", deployment.GetAnnotations()["example.com/patched"])
+		// existing annotations are retained by the strategic merge
+		assert.Contains(deployment.GetAnnotations(), "kubernetes.io/description")
+		container := deployment.Spec.Template.Spec.Containers[0]
+		assert.Equal("kube-ovn-controller", container.Name)
+		assert.Greater(len(container.Env), 1, "expected existing env vars to be merged with the patch")
+		assert.Equal("EXTRA_ENV", container.Env[0].Name)
+	}
+	assert.True(found, "expected to find kube-ovn-controller deployment")
+}
+
+func Test_JSON6902Override(t *testing.T) {
+	assert := require.New(t)
+	objs := generateDeployments(t)
+
+	overrides := []ovnoperatorv1.ObjectOverride{
+		{
+			Target: ovnoperatorv1.OverrideTarget{
+				GroupVersionKind: ovnoperatorv1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+				Name:             "ovn-central",
+				Namespace:        "kube-system",
+			},
+			PatchType: ovnoperatorv1.JSON6902PatchType,
+			Patch: `- op: add
+  path: /spec/template/spec/volumes/-
+  value:
+    name: extra
+    emptyDir: {}`,
+		},
+	}
+
+	patchedObjs, err := ApplyOverrides(objs, overrides)
+	assert.NoError(err)
+	for _, obj := range patchedObjs {
+		deployment := obj.(*appsv1.Deployment)
+		if deployment.GetName() != "ovn-central" {
+			continue
+		}
+		volumes := deployment.Spec.Template.Spec.Volumes
+		assert.Equal("extra", volumes[len(volumes)-1].Name)
+	}
+}
+
+func Test_OverrideCannotRenameObject(t *testing.T) {
+	assert := require.New(t)
+	objs := generateDeployments(t)
+
+	overrides := []ovnoperatorv1.ObjectOverride{
+		{
+			Target: ovnoperatorv1.OverrideTarget{
+				GroupVersionKind: ovnoperatorv1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+				Name:             "ovn-central",
+			},
+			PatchType: ovnoperatorv1.JSON6902PatchType,
+			Patch:     `[{"op": "replace", "path": "/metadata/name", "value": "renamed"}]`,
+		},
+	}
+	_, err := ApplyOverrides(objs, overrides)
+	assert.Error(err)
+}
+
+func Test_StrategicMergeOverrideUnstructured(t *testing.T) {
+	assert := require.New(t)
+	c, err := generateConfigObject()
+	assert.NoError(err, "expected no error while generating config object")
+	c.Namespace = "kube-system"
+	c.Spec.Networking.EnableSSL = ptr.To(true)
+	c.Spec.CertManager.Enabled = true
+	objs, err := GenerateObjects(templates.CertificateList, c, &unstructured.Unstructured{}, nil, "v1.14.0", "caCertString")
+	assert.NoError(err)
+
+	overrides := []ovnoperatorv1.ObjectOverride{
+		{
+			Target: ovnoperatorv1.OverrideTarget{
+				GroupVersionKind: ovnoperatorv1.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"},
+				Name:             "kube-ovn-tls",
+			},
+			Patch: `spec:
+  duration: 720h`,
+		},
+	}
+	patchedObjs, err := ApplyOverrides(objs, overrides)
+	assert.NoError(err)
+	var found bool
+	for _, obj := range patchedObjs {
+		if obj.GetName() != "kube-ovn-tls" {
+			continue
+		}
+		found = true
+		spec, _, err := unstructured.NestedMap(obj.(*unstructured.Unstructured).Object, "spec")
+		assert.NoError(err)
+		assert.Equal("720h", spec["duration"])
+		assert.Contains(spec, "issuerRef", "expected existing fields to be retained by the merge")
+	}
+	assert.True(found, "expected to find kube-ovn-tls certificate")
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"text/template"
 
@@ -29,7 +30,9 @@ type values struct {
 func GenerateObjects(templates []string, config *ovnoperatorv1.Configuration, object client.Object, restConfig *rest.Config, version string, caCert string) ([]client.Object, error) {
 	var returnedObjects []client.Object
 
-	// patch version supplied by the controller
+	// image tag is always the kube-ovn version being rolled out, as the version is validated against the upgrade
+	// path and recorded in the status
+	config = config.DeepCopy()
 	config.Spec.Global.Images.KubeOVNImage.Tag = version
 
 	valsObj, err := generateMap(config)
@@ -140,7 +143,13 @@ func generateIncludeValues(config *ovnoperatorv1.Configuration) map[string]inter
 		runAsUser = int64(0)
 	}
 
+	images := make(map[string]interface{})
+	for k, v := range ResolveImages(config, config.Spec.Global.Images.KubeOVNImage.Tag) {
+		images[k] = v
+	}
+
 	return map[string]interface{}{
+		"images":    images,
 		"nodeCount": int64(len(config.Status.MatchingNodeAddresses)),
 		"nodeIPs":   nodeIPS,
 		"ovs-ovn":   upgradeStratergyMap,
@@ -186,6 +195,8 @@ func InitialiseNewObject(object client.Object) client.Object {
 		return &corev1.Service{}
 	case *admissionregistrationv1.ValidatingWebhookConfiguration:
 		return &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	case *unstructured.Unstructured:
+		return &unstructured.Unstructured{}
 	}
 
 	return nil
@@ -215,6 +226,35 @@ func generateScript(nodeAddress, script string) (string, error) {
 	return result.String(), nil
 }
 
+// serverIDRegex matches the server ids reported by ovs-appctl cluster/status
+var serverIDRegex = regexp.MustCompile(`^[0-9a-f]+$`)
+
+// GenerateNorthBoundKickScript kicks a member of the northbound cluster by its exact server id
+func GenerateNorthBoundKickScript(serverID string) (string, error) {
+	return generateKickScript(serverID, templates.KickNBMember)
+}
+
+// GenerateSouthBoundKickScript kicks a member of the southbound cluster by its exact server id
+func GenerateSouthBoundKickScript(serverID string) (string, error) {
+	return generateKickScript(serverID, templates.KickSBMember)
+}
+
+func generateKickScript(serverID, script string) (string, error) {
+	if !serverIDRegex.MatchString(serverID) {
+		return "", fmt.Errorf("invalid raft server id %q", serverID)
+	}
+	tmpl, err := template.New("script").Parse(script)
+	if err != nil {
+		return "", fmt.Errorf("error parsing template %s: %v", script, err)
+	}
+	var result bytes.Buffer
+	err = tmpl.Execute(&result, map[string]string{"ServerID": serverID})
+	if err != nil {
+		return "", fmt.Errorf("error during template execution %s using server id %s: %v", script, serverID, err)
+	}
+	return result.String(), nil
+}
+
 func GenerateChassisCleanupScript(hostname string) (string, error) {
 	values := map[string]string{
 		"Hostname": hostname,
@@ -231,6 +271,67 @@ func GenerateChassisCleanupScript(hostname string) (string, error) {
 	return result.String(), nil
 }
 
+// chassisNameRegex matches the chassis names set by ovs-ovn, which are the system-id of the node or its hostname
+var chassisNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// GenerateChassisDeleteScript deletes a chassis from the southbound database by its exact name
+func GenerateChassisDeleteScript(name string) (string, error) {
+	if !chassisNameRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid chassis name %q", name)
+	}
+	values := map[string]string{
+		"Name": name,
+	}
+	tmpl, err := template.New("script").Parse(templates.DeleteChassis)
+	if err != nil {
+		return "", fmt.Errorf("error parsing chassis delete template %s: %v", templates.DeleteChassis, err)
+	}
+	var result bytes.Buffer
+	err = tmpl.Execute(&result, values)
+	if err != nil {
+		return "", fmt.Errorf("error during template execution %s using values %v: %v", templates.DeleteChassis, values, err)
+	}
+	return result.String(), nil
+}
+
+func GenerateMoveDatabasesScript(dir string, files []string, suffix string) (string, error) {
+	values := map[string]interface{}{
+		"Dir":    dir,
+		"Files":  files,
+		"Suffix": suffix,
+	}
+	tmpl, err := template.New("script").Funcs(template.FuncMap{"join": strings.Join}).Parse(templates.MoveDatabases)
+	if err != nil {
+		return "", fmt.Errorf("error parsing move databases template %s: %v", templates.MoveDatabases, err)
+	}
+	var result bytes.Buffer
+	err = tmpl.Execute(&result, values)
+	if err != nil {
+		return "", fmt.Errorf("error during template execution %s using values %v: %v", templates.MoveDatabases, values, err)
+	}
+	return result.String(), nil
+}
+
+func GenerateCreateClusterScript(dir string, file string, protocol string, address string, port int) (string, error) {
+	values := map[string]interface{}{
+		"Dir":      dir,
+		"File":     file,
+		"Protocol": protocol,
+		"Address":  address,
+		"Port":     port,
+	}
+	tmpl, err := template.New("script").Parse(templates.CreateCluster)
+	if err != nil {
+		return "", fmt.Errorf("error parsing create cluster template %s: %v", templates.CreateCluster, err)
+	}
+	var result bytes.Buffer
+	err = tmpl.Execute(&result, values)
+	if err != nil {
+		return "", fmt.Errorf("error during template execution %s using values %v: %v", templates.CreateCluster, values, err)
+	}
+	return result.String(), nil
+}
+
 func generateMasterNodeAffinity(config *ovnoperatorv1.Configuration) (string, error) {
 
 	// if there are no MasterNodeLabels return empty string
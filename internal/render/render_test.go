@@ -3,11 +3,15 @@ package render
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"testing"
 
 	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
 	"github.com/harvester/kubeovn-operator/internal/templates"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/yaml"
 )
 
@@ -25,15 +29,34 @@ func Test_ObjectRendering(t *testing.T) {
 	assert := require.New(t)
 	c, err := generateConfigObject()
 	assert.NoError(err, "expected no error while generating config object")
-	for objectType, objectList := range templates.OrderedObjectList {
-		returnedObjects, err := GenerateObjects(objectList, c, objectType, nil, "v1.14.0", "caCertString")
-		assert.NoError(err, "expected no error while generating object", objectType)
-		for _, object := range returnedObjects {
-			assert.NotEmpty(object.GetName())
+	for _, phase := range templates.OrderedPhases {
+		for _, v := range phase.Objects {
+			returnedObjects, err := GenerateObjects(v.Templates, c, v.ObjectType, nil, "v1.14.0", "caCertString")
+			assert.NoError(err, "expected no error while generating object", phase.Name, v.ObjectType)
+			for _, object := range returnedObjects {
+				assert.NotEmpty(object.GetName())
+			}
 		}
 	}
 }
 
+func Test_PhasesContainAllTemplates(t *testing.T) {
+	assert := require.New(t)
+	objectLists := [][]string{templates.CRDList, templates.ServiceAccountList, templates.ClusterRoleList, templates.ClusterRoleBindingList,
+		templates.RoleList, templates.RoleBindingList, templates.ConfigMapList, templates.ServicesList,
+		templates.DeploymentList, templates.DaemonsetList, templates.ValidatingWebhookConfigurationList, templates.CertificateList}
+	var expected, phaseTemplates []string
+	for _, v := range objectLists {
+		expected = append(expected, v...)
+	}
+	for _, phase := range templates.OrderedPhases {
+		for _, v := range phase.Objects {
+			phaseTemplates = append(phaseTemplates, v.Templates...)
+		}
+	}
+	assert.ElementsMatch(expected, phaseTemplates, "expected each template to be applied in exactly one phase")
+}
+
 func Test_CleanupRendering(t *testing.T) {
 	assert := require.New(t)
 	nodeIP := "192.168.1.128"
@@ -41,6 +64,38 @@ func Test_CleanupRendering(t *testing.T) {
 		_, err := generateScript(nodeIP, v)
 		assert.NoError(err)
 	}
+
+	script, err := GenerateNorthBoundKickScript("a8b1")
+	assert.NoError(err)
+	assert.Equal("ovs-appctl -t /var/run/ovn/ovnnb_db.ctl cluster/kick OVN_Northbound a8b1", script)
+	script, err = GenerateSouthBoundKickScript("a8b1")
+	assert.NoError(err)
+	assert.Equal("ovs-appctl -t /var/run/ovn/ovnsb_db.ctl cluster/kick OVN_Southbound a8b1", script)
+	_, err = GenerateNorthBoundKickScript("a8b1; rm -rf /")
+	assert.Error(err)
+
+	script, err = GenerateChassisDeleteScript("5d4b1f0e-1f3a-4f3e-8f5c-0c2a6b7d9e01")
+	assert.NoError(err)
+	assert.Equal("ovn-sbctl chassis-del '5d4b1f0e-1f3a-4f3e-8f5c-0c2a6b7d9e01'", script)
+	script, err = GenerateChassisDeleteScript("node-1.example.com")
+	assert.NoError(err)
+	assert.Equal("ovn-sbctl chassis-del 'node-1.example.com'", script)
+	for _, name := range []string{"", "chassis; rm -rf /", "chassis' && reboot '", "$(id)", "-h"} {
+		_, err = GenerateChassisDeleteScript(name)
+		assert.Error(err, "expected chassis name %q to be rejected", name)
+	}
+}
+
+func Test_RestoreRendering(t *testing.T) {
+	assert := require.New(t)
+	script, err := GenerateMoveDatabasesScript("/etc/ovn", []string{"ovnnb_db.db", "ovnsb_db.db"}, "restore-20250101")
+	assert.NoError(err)
+	assert.Contains(script, "for db in ovnnb_db.db ovnsb_db.db")
+	assert.Contains(script, `mv "$db" "$db.restore-20250101"`)
+
+	script, err = GenerateCreateClusterScript("/etc/ovn", "ovnnb_db.db", "tcp", "fd00::10", 6643)
+	assert.NoError(err)
+	assert.Contains(script, "ovsdb-tool create-cluster ovnnb_db.db ovnnb_db.db.standalone tcp:[fd00::10]:6643")
 }
 
 func Test_MasterNodeAffinityRendering(t *testing.T) {
@@ -63,3 +118,132 @@ func Test_MasterNodeAffinityRenderingWithEmptyLabel(t *testing.T) {
 	assert.NoError(err)
 	assert.Len(result, 0, "expected empty result when MasterNodesLabel is not set")
 }
+
+func Test_PlacementRendering(t *testing.T) {
+	assert := require.New(t)
+	c, err := generateConfigObject()
+	assert.NoError(err, "expected no error while generating config object")
+	gpuToleration := corev1.Toleration{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	storageAffinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "node-role/storage", Operator: corev1.NodeSelectorOpDoesNotExist},
+						},
+					},
+				},
+			},
+		},
+	}
+	c.Spec.Placement.OVNCentral = ovnoperatorv1.ComponentPlacement{
+		NodeSelector:      map[string]string{"zone": "a"},
+		Tolerations:       []corev1.Toleration{gpuToleration},
+		PriorityClassName: "ovn-critical",
+	}
+	c.Spec.Placement.KubeOVNCNI = ovnoperatorv1.ComponentPlacement{
+		Tolerations: []corev1.Toleration{gpuToleration},
+		Affinity:    storageAffinity,
+	}
+
+	deployments, err := GenerateObjects(templates.DeploymentList, c, &appsv1.Deployment{}, nil, "v1.14.0", "caCertString")
+	assert.NoError(err)
+	for _, obj := range deployments {
+		deployment, ok := obj.(*appsv1.Deployment)
+		assert.True(ok)
+		if deployment.GetName() != "ovn-central" {
+			continue
+		}
+		podSpec := deployment.Spec.Template.Spec
+		assert.Equal("linux", podSpec.NodeSelector["kubernetes.io/os"])
+		assert.Equal("a", podSpec.NodeSelector["zone"])
+		assert.Contains(podSpec.Tolerations, gpuToleration)
+		assert.Equal("ovn-critical", podSpec.PriorityClassName)
+		assert.NotNil(podSpec.Affinity.NodeAffinity, "expected master node affinity to be retained")
+		assert.NotNil(podSpec.Affinity.PodAntiAffinity, "expected default pod anti affinity to be retained")
+	}
+
+	daemonsets, err := GenerateObjects(templates.DaemonsetList, c, &appsv1.DaemonSet{}, nil, "v1.14.0", "caCertString")
+	assert.NoError(err)
+	for _, obj := range daemonsets {
+		daemonset, ok := obj.(*appsv1.DaemonSet)
+		assert.True(ok)
+		podSpec := daemonset.Spec.Template.Spec
+		switch daemonset.GetName() {
+		case "kube-ovn-cni":
+			assert.Contains(podSpec.Tolerations, gpuToleration)
+			assert.Equal(storageAffinity, podSpec.Affinity)
+			assert.Equal("system-node-critical", podSpec.PriorityClassName)
+		case "ovs-ovn":
+			assert.NotContains(podSpec.Tolerations, gpuToleration)
+			assert.Nil(podSpec.Affinity)
+		}
+	}
+}
+
+func Test_ComponentImageRendering(t *testing.T) {
+	assert := require.New(t)
+	c, err := generateConfigObject()
+	assert.NoError(err, "expected no error while generating config object")
+	digest := "sha256:" + strings.Repeat("a", 64)
+	c.Spec.Global.Images.Components.OVNCentral.Digest = digest
+	c.Spec.Global.Images.Components.KubeOVNCNI.Tag = "v1.14.1-hotfix"
+	c.Spec.Global.Images.NATGatewayImage.Repository = "vpc-nat-gateway"
+
+	deployments, err := GenerateObjects(templates.DeploymentList, c, &appsv1.Deployment{}, nil, "v1.14.0", "caCertString")
+	assert.NoError(err)
+	daemonsets, err := GenerateObjects(templates.DaemonsetList, c, &appsv1.DaemonSet{}, nil, "v1.14.0", "caCertString")
+	assert.NoError(err)
+
+	images := ResolveImages(c, "v1.14.0")
+	assert.Equal("docker.io/kubeovn/kube-ovn@"+digest, images[OVNCentralImageKey])
+	assert.Equal("docker.io/kubeovn/kube-ovn:v1.14.1-hotfix", images[KubeOVNCNIImageKey])
+	assert.Equal("docker.io/kubeovn/kube-ovn:v1.14.0", images[KubeOVNControllerImageKey])
+	assert.Equal("docker.io/kubeovn/vpc-nat-gateway:v1.14.0", images[NATGatewayImageKey])
+
+	expectedImages := map[string]string{
+		"ovn-central":         images[OVNCentralImageKey],
+		"kube-ovn-controller": images[KubeOVNControllerImageKey],
+		"kube-ovn-cni":        images[KubeOVNCNIImageKey],
+	}
+	for _, obj := range append(deployments, daemonsets...) {
+		expected, ok := expectedImages[obj.GetName()]
+		if !ok {
+			continue
+		}
+		var podSpec corev1.PodSpec
+		switch o := obj.(type) {
+		case *appsv1.Deployment:
+			podSpec = o.Spec.Template.Spec
+		case *appsv1.DaemonSet:
+			podSpec = o.Spec.Template.Spec
+		}
+		for _, container := range slices.Concat(podSpec.InitContainers, podSpec.Containers) {
+			assert.Equal(expected, container.Image, "unexpected image for container %s in %s", container.Name, obj.GetName())
+		}
+	}
+}
+
+func Test_VersionDrivesImageTag(t *testing.T) {
+	assert := require.New(t)
+	c, err := generateConfigObject()
+	assert.NoError(err, "expected no error while generating config object")
+
+	// an explicit tag is ignored when no version is pinned, as it is not validated against the upgrade path
+	c.Spec.Global.Images.KubeOVNImage.Tag = "v1.13.0"
+	assert.Equal("docker.io/kubeovn/kube-ovn:v1.14.0", ResolveImages(c, "v1.14.0")[OVNCentralImageKey])
+
+	// spec.version drives the tag, so the images match the version recorded in the status
+	c.Spec.Version = "v1.14.0"
+	assert.Equal("docker.io/kubeovn/kube-ovn:v1.14.0", ResolveImages(c, "v1.14.0")[OVNCentralImageKey])
+	deployments, err := GenerateObjects(templates.DeploymentList, c, &appsv1.Deployment{}, nil, "v1.14.0", "caCertString")
+	assert.NoError(err)
+	for _, obj := range deployments {
+		deployment, ok := obj.(*appsv1.Deployment)
+		if !ok || deployment.GetName() != "ovn-central" {
+			continue
+		}
+		assert.Equal("docker.io/kubeovn/kube-ovn:v1.14.0", deployment.Spec.Template.Spec.Containers[0].Image)
+	}
+}
@@ -0,0 +1,42 @@
+package render
+
+import (
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+// tlsWorkloads load the ovn tls material at startup, and need to be restarted to use rotated certificates
+var tlsWorkloads = []string{"ovn-central", "kube-ovn-controller", "ovs-ovn", "ovs-ovn-dpdk"}
+
+// ApplyTLSChecksum annotates the pod templates of workloads using the ovn tls material with a checksum of
+// the material, so a rotation triggers a rolling restart. Objects are returned unchanged if checksum is empty
+func ApplyTLSChecksum(objs []client.Object, checksum string) []client.Object {
+	if checksum == "" {
+		return objs
+	}
+
+	for _, obj := range objs {
+		if !slices.Contains(tlsWorkloads, obj.GetName()) {
+			continue
+		}
+		switch o := obj.(type) {
+		case *appsv1.Deployment:
+			annotatePodTemplate(&o.Spec.Template, checksum)
+		case *appsv1.DaemonSet:
+			annotatePodTemplate(&o.Spec.Template, checksum)
+		}
+	}
+	return objs
+}
+
+func annotatePodTemplate(template *corev1.PodTemplateSpec, checksum string) {
+	if template.Annotations == nil {
+		template.Annotations = make(map[string]string)
+	}
+	template.Annotations[ovnoperatorv1.TLSChecksumAnnotation] = checksum
+}
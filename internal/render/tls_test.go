@@ -0,0 +1,24 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+func Test_ApplyTLSChecksum(t *testing.T) {
+	assert := require.New(t)
+	objs := ApplyTLSChecksum(generateDeployments(t), "abc123")
+	for _, obj := range objs {
+		annotations := obj.(*appsv1.Deployment).Spec.Template.Annotations
+		switch obj.GetName() {
+		case "ovn-central", "kube-ovn-controller":
+			assert.Equal("abc123", annotations[ovnoperatorv1.TLSChecksumAnnotation], "expected %s to be restarted on rotation", obj.GetName())
+		default:
+			assert.NotContains(annotations, ovnoperatorv1.TLSChecksumAnnotation, "unexpected restart of %s on rotation", obj.GetName())
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package templates
+
+var (
+	kube_ovn_selfsigned_issuer = `{{- if and .Values.certManager.enabled (not .Values.certManager.issuerRef) }}
+apiVersion: cert-manager.io/v1
+kind: Issuer
+metadata:
+  name: kube-ovn-selfsigned
+  namespace: {{ .Values.namespace }}
+spec:
+  selfSigned: {}
+{{- end }}`
+
+	kube_ovn_ca_certificate = `{{- if and .Values.certManager.enabled (not .Values.certManager.issuerRef) }}
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: kube-ovn-ca
+  namespace: {{ .Values.namespace }}
+spec:
+  isCA: true
+  commonName: ovn-ca
+  secretName: kube-ovn-ca
+  duration: 87600h
+  privateKey:
+    algorithm: ECDSA
+    size: 256
+  issuerRef:
+    group: cert-manager.io
+    kind: Issuer
+    name: kube-ovn-selfsigned
+{{- end }}`
+
+	kube_ovn_ca_issuer = `{{- if and .Values.certManager.enabled (not .Values.certManager.issuerRef) }}
+apiVersion: cert-manager.io/v1
+kind: Issuer
+metadata:
+  name: kube-ovn-ca
+  namespace: {{ .Values.namespace }}
+spec:
+  ca:
+    secretName: kube-ovn-ca
+{{- end }}`
+
+	// kube-ovn reads the cacert, cert and key keys from kube-ovn-tls, the cert-manager keys are
+	// mapped onto these paths in the pod volumes by render.ApplyCertManager
+	kube_ovn_tls_certificate = `{{- if and .Values.certManager.enabled .Values.networking.enableSSL }}
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: kube-ovn-tls
+  namespace: {{ .Values.namespace }}
+spec:
+  commonName: ovn
+  secretName: kube-ovn-tls
+  {{- with .Values.certManager.duration }}
+  duration: {{ . }}
+  {{- end }}
+  {{- with .Values.certManager.renewBefore }}
+  renewBefore: {{ . }}
+  {{- end }}
+  usages:
+    - server auth
+    - client auth
+  issuerRef:
+  {{- with .Values.certManager.issuerRef }}
+    group: {{ .group }}
+    kind: {{ .kind }}
+    name: {{ .name }}
+  {{- else }}
+    group: cert-manager.io
+    kind: Issuer
+    name: kube-ovn-ca
+  {{- end }}
+{{- end }}`
+
+	kube_ovn_webhook_certificate = `{{- if .Values.certManager.enabled }}
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: kube-ovn-webhook
+  namespace: {{ .Values.namespace }}
+spec:
+  secretName: kube-ovn-webhook-certs
+  dnsNames:
+    - kube-ovn-webhook
+    - kube-ovn-webhook.{{ .Values.namespace }}
+    - kube-ovn-webhook.{{ .Values.namespace }}.svc
+    - kube-ovn-webhook.{{ .Values.namespace }}.svc.cluster.local
+  {{- with .Values.certManager.duration }}
+  duration: {{ . }}
+  {{- end }}
+  {{- with .Values.certManager.renewBefore }}
+  renewBefore: {{ . }}
+  {{- end }}
+  usages:
+    - server auth
+  issuerRef:
+  {{- with .Values.certManager.issuerRef }}
+    group: {{ .group }}
+    kind: {{ .kind }}
+    name: {{ .name }}
+  {{- else }}
+    group: cert-manager.io
+    kind: Issuer
+    name: kube-ovn-ca
+  {{- end }}
+{{- end }}`
+
+	// CertificateList is ordered so issuers are applied before the certificates referencing them
+	CertificateList = []string{kube_ovn_selfsigned_issuer, kube_ovn_ca_certificate, kube_ovn_ca_issuer, kube_ovn_tls_certificate, kube_ovn_webhook_certificate}
+)
@@ -26,9 +26,15 @@ then
   ovs-appctl -t /var/run/ovn/ovnsb_db.ctl cluster/status OVN_Southbound
 fi`
 
+var KickNBMember = `ovs-appctl -t /var/run/ovn/ovnnb_db.ctl cluster/kick OVN_Northbound {{ .ServerID }}`
+
+var KickSBMember = `ovs-appctl -t /var/run/ovn/ovnsb_db.ctl cluster/kick OVN_Southbound {{ .ServerID }}`
+
 var CleanupChassis = `chassis=$(ovn-sbctl --columns=name find  chassis hostname={{ .Hostname }} | awk -F ":" '{print $2}' | tr -d '"')
 if [ -n "$chassis" ]
 then
   ovn-sbctl chassis-del $chassis
 fi
 ovn-sbctl show`
+
+var DeleteChassis = `ovn-sbctl chassis-del '{{ .Name }}'`
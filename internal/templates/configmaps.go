@@ -10,7 +10,7 @@ metadata:
     kubernetes.io/description: |
       kube-ovn vpc-nat common config
 data:
-  image: {{ .Values.global.registry.address }}/{{ .Values.global.images.natgateway.repository }}:{{ or .Values.global.images.natgateway.tag .Values.global.images.kubeovn.tag }}`
+  image: {{ include "kubeovn.images.natGateway" . }}`
 
 	ovn_vpc_nat_gw_config = `kind: ConfigMap
 apiVersion: v1
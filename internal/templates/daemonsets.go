@@ -28,7 +28,13 @@ spec:
     spec:
       tolerations:
       - operator: Exists
-      priorityClassName: system-node-critical
+      {{- range .Values.placement.ovsOVN.tolerations }}
+      - {{ toJson . }}
+      {{- end }}
+      {{- with .Values.placement.ovsOVN.affinity }}
+      affinity: {{ toJson . }}
+      {{- end }}
+      priorityClassName: {{ .Values.placement.ovsOVN.priorityClassName | default "system-node-critical" }}
       serviceAccountName: ovn-ovs
       automountServiceAccountToken: true
       hostNetwork: true
@@ -124,6 +130,9 @@ spec:
       nodeSelector:
         kubernetes.io/os: "linux"
         ovn.kubernetes.io/ovs_dp_type: "userspace"
+        {{- range $key, $value := .Values.placement.ovsOVN.nodeSelector }}
+        {{ $key | quote }}: {{ $value | quote }}
+        {{- end }}
       volumes:
         - name: host-config-ovs
           hostPath:
@@ -195,7 +204,13 @@ spec:
           operator: Exists
         - key: CriticalAddonsOnly
           operator: Exists
-      priorityClassName: system-node-critical
+        {{- range .Values.placement.kubeOvnCNI.tolerations }}
+        - {{ toJson . }}
+        {{- end }}
+      {{- with .Values.placement.kubeOvnCNI.affinity }}
+      affinity: {{ toJson . }}
+      {{- end }}
+      priorityClassName: {{ .Values.placement.kubeOvnCNI.priorityClassName | default "system-node-critical" }}
       serviceAccountName: kube-ovn-cni
       automountServiceAccountToken: true
       hostNetwork: true
@@ -205,7 +220,7 @@ spec:
           type: RuntimeDefault
       initContainers:
       - name: hostpath-init
-        image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+        image: {{ include "kubeovn.images.kubeOvnCNI" . }}
         imagePullPolicy: {{ .Values.imagePullPolicy }}
         command:
           - sh
@@ -233,7 +248,7 @@ spec:
           - name: kube-ovn-log
             mountPath: /var/log/kube-ovn
       - name: install-cni
-        image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+        image: {{ include "kubeovn.images.kubeOvnCNI" . }}
         imagePullPolicy: {{ .Values.imagePullPolicy }}
         command:
           - /kube-ovn/install-cni.sh
@@ -259,7 +274,7 @@ spec:
           {{- end }}
       containers:
       - name: cni-server
-        image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+        image: {{ include "kubeovn.images.kubeOvnCNI" . }}
         imagePullPolicy: {{ .Values.imagePullPolicy }}
         command:
           - bash
@@ -402,6 +417,9 @@ spec:
             ephemeral-storage: {{ index .Values "kubeOvnCNI" "limits" "ephemeralStorage" }}
       nodeSelector:
         kubernetes.io/os: "linux"
+        {{- range $key, $value := .Values.placement.kubeOvnCNI.nodeSelector }}
+        {{ $key | quote }}: {{ $value | quote }}
+        {{- end }}
       volumes:
         - name: usr-local-sbin
           emptyDir: {}
@@ -496,7 +514,13 @@ spec:
           operator: Exists
         - key: CriticalAddonsOnly
           operator: Exists
-      priorityClassName: system-node-critical
+        {{- range .Values.placement.ovsOVN.tolerations }}
+        - {{ toJson . }}
+        {{- end }}
+      {{- with .Values.placement.ovsOVN.affinity }}
+      affinity: {{ toJson . }}
+      {{- end }}
+      priorityClassName: {{ .Values.placement.ovsOVN.priorityClassName | default "system-node-critical" }}
       serviceAccountName: ovn-ovs
       automountServiceAccountToken: true
       hostNetwork: true
@@ -506,7 +530,7 @@ spec:
           type: RuntimeDefault
       initContainers:
         - name: hostpath-init
-          image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+          image: {{ include "kubeovn.images.ovsOVN" . }}
           imagePullPolicy: {{ .Values.imagePullPolicy }}
           command:
             - sh
@@ -547,7 +571,7 @@ spec:
               name: host-log-ovs
       containers:
         - name: openvswitch
-          image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+          image: {{ include "kubeovn.images.ovsOVN" . }}
           imagePullPolicy: {{ .Values.imagePullPolicy }}
           command: ["/kube-ovn/start-ovs.sh"]
           securityContext:
@@ -640,6 +664,9 @@ spec:
               ephemeral-storage: {{ index .Values "ovsOVN" "limits" "ephemeralStorage" }}
       nodeSelector:
         kubernetes.io/os: "linux"
+        {{- range $key, $value := .Values.placement.ovsOVN.nodeSelector }}
+        {{ $key | quote }}: {{ $value | quote }}
+        {{- end }}
       volumes:
         - name: usr-local-sbin
           emptyDir: {}
@@ -693,7 +720,10 @@ spec:
         component: network
         type: infra
     spec:
-      priorityClassName: system-node-critical
+      {{- with .Values.placement.kubeOvnPinger.affinity }}
+      affinity: {{ toJson . }}
+      {{- end }}
+      priorityClassName: {{ .Values.placement.kubeOvnPinger.priorityClassName | default "system-node-critical" }}
       tolerations:
         - effect: NoSchedule
           operator: Exists
@@ -701,6 +731,9 @@ spec:
           operator: Exists
         - key: CriticalAddonsOnly
           operator: Exists
+        {{- range .Values.placement.kubeOvnPinger.tolerations }}
+        - {{ toJson . }}
+        {{- end }}
       serviceAccountName: kube-ovn-app
       automountServiceAccountToken: true
       hostPID: false
@@ -709,7 +742,7 @@ spec:
           type: RuntimeDefault
       initContainers:
         - name: hostpath-init
-          image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+          image: {{ include "kubeovn.images.kubeOvnPinger" . }}
           imagePullPolicy: {{ .Values.imagePullPolicy }}
           command:
             - sh
@@ -727,12 +760,13 @@ spec:
               mountPath: /var/log/kube-ovn
       containers:
         - name: pinger
-          image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+          image: {{ include "kubeovn.images.kubeOvnPinger" . }}
           command:
           - /kube-ovn/kube-ovn-pinger
           args:
           - --external-address=
-          {{- if eq .Values.networking.netStack "dual_stack" -}}
+          {{- if .Values.airgap.enabled -}}
+          {{- else if eq .Values.networking.netStack "dual_stack" -}}
           {{ .Values.dualStack.pingerExternalAddress }}
           {{- else if eq .Values.networking.netStack "ipv4" -}}
           {{ .Values.ipv4.pingerExternalAddress }}
@@ -740,7 +774,8 @@ spec:
           {{ .Values.ipv6.pingerExternalAddress }}
           {{- end }}
           - --external-dns=
-          {{- if eq .Values.networking.netStack "dual_stack" -}}
+          {{- if .Values.airgap.enabled -}}
+          {{- else if eq .Values.networking.netStack "dual_stack" -}}
           {{ .Values.dualStack.pingerExternalDomain }}
           {{- else if eq .Values.networking.netStack "ipv4" -}}
           {{ .Values.ipv4.pingerExternalDomain }}
@@ -825,6 +860,9 @@ spec:
             periodSeconds: 10
       nodeSelector:
         kubernetes.io/os: "linux"
+        {{- range $key, $value := .Values.placement.kubeOvnPinger.nodeSelector }}
+        {{ $key | quote }}: {{ $value | quote }}
+        {{- end }}
       volumes:
         - name: host-run-ovs
           hostPath:
@@ -33,15 +33,30 @@ spec:
           operator: Exists
         - key: CriticalAddonsOnly
           operator: Exists
+        {{- range .Values.placement.ovnCentral.tolerations }}
+        - {{ toJson . }}
+        {{- end }}
       affinity:
+        {{- $affinity := .Values.placement.ovnCentral.affinity | default dict }}
+        {{- if $affinity.podAntiAffinity }}
+        podAntiAffinity: {{ toJson $affinity.podAntiAffinity }}
+        {{- else }}
         podAntiAffinity:
           requiredDuringSchedulingIgnoredDuringExecution:
             - labelSelector:
                 matchLabels:
                   app: ovn-central
               topologyKey: kubernetes.io/hostname
-        {{- include "kubeovn.masterNodeAffinity" . | nindent 8 }}      
-      priorityClassName: system-cluster-critical
+        {{- end }}
+        {{- if $affinity.nodeAffinity }}
+        nodeAffinity: {{ toJson $affinity.nodeAffinity }}
+        {{- else }}
+        {{- include "kubeovn.masterNodeAffinity" . | nindent 8 }}
+        {{- end }}
+        {{- with $affinity.podAffinity }}
+        podAffinity: {{ toJson . }}
+        {{- end }}
+      priorityClassName: {{ .Values.placement.ovnCentral.priorityClassName | default "system-cluster-critical" }}
       serviceAccountName: ovn-ovs
       automountServiceAccountToken: true
       hostNetwork: true
@@ -50,7 +65,7 @@ spec:
           type: RuntimeDefault
       initContainers:
         - name: hostpath-init
-          image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+          image: {{ include "kubeovn.images.ovnCentral" . }}
           imagePullPolicy: {{ .Values.imagePullPolicy }}
           command:
             - sh
@@ -72,7 +87,7 @@ spec:
               name: host-log-ovn
       containers:
         - name: ovn-central
-          image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+          image: {{ include "kubeovn.images.ovnCentral" . }}
           imagePullPolicy: {{ .Values.imagePullPolicy }}
           command:
           - bash
@@ -157,6 +172,9 @@ spec:
             timeoutSeconds: 45
       nodeSelector:
         kubernetes.io/os: "linux"
+        {{- range $key, $value := .Values.placement.ovnCentral.nodeSelector }}
+        {{ $key | quote }}: {{ $value | quote }}
+        {{- end }}
       volumes:
         - name: host-run-ovn
           hostPath:
@@ -205,7 +223,14 @@ spec:
           operator: Exists
         - key: CriticalAddonsOnly
           operator: Exists
+        {{- range .Values.placement.kubeOvnController.tolerations }}
+        - {{ toJson . }}
+        {{- end }}
       affinity:
+        {{- $affinity := .Values.placement.kubeOvnController.affinity | default dict }}
+        {{- if $affinity.nodeAffinity }}
+        nodeAffinity: {{ toJson $affinity.nodeAffinity }}
+        {{- else }}
         nodeAffinity:
           preferredDuringSchedulingIgnoredDuringExecution:
             - preference:
@@ -215,13 +240,21 @@ spec:
                     values:
                       - "true"
               weight: 100
+        {{- end }}
+        {{- if $affinity.podAntiAffinity }}
+        podAntiAffinity: {{ toJson $affinity.podAntiAffinity }}
+        {{- else }}
         podAntiAffinity:
           requiredDuringSchedulingIgnoredDuringExecution:
             - labelSelector:
                 matchLabels:
                   app: kube-ovn-controller
               topologyKey: kubernetes.io/hostname
-      priorityClassName: system-cluster-critical
+        {{- end }}
+        {{- with $affinity.podAffinity }}
+        podAffinity: {{ toJson . }}
+        {{- end }}
+      priorityClassName: {{ .Values.placement.kubeOvnController.priorityClassName | default "system-cluster-critical" }}
       serviceAccountName: ovn
       automountServiceAccountToken: true
       hostNetwork: true
@@ -230,7 +263,7 @@ spec:
           type: RuntimeDefault
       initContainers:
         - name: hostpath-init
-          image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+          image: {{ include "kubeovn.images.kubeOvnController" . }}
           imagePullPolicy: {{ .Values.imagePullPolicy }}
           command:
             - sh
@@ -248,7 +281,7 @@ spec:
               mountPath: /var/log/kube-ovn
       containers:
         - name: kube-ovn-controller
-          image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+          image: {{ include "kubeovn.images.kubeOvnController" . }}
           imagePullPolicy: {{ .Values.imagePullPolicy }}
           args:
           - /kube-ovn/start-controller.sh
@@ -331,7 +364,7 @@ spec:
           - --np-enforcement={{- .Values.components.npEnforcement }}
           - --enable-live-migration-optimize={{- .Values.components.enableLiveMigrationOptimize }}
           - --enable-ovn-lb-prefer-local={{- .Values.components.enableOVNLBPreferLocal }}
-          - --image={{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+          - --image={{ include "kubeovn.images.kubeovn" . }}
           - --skip-conntrack-dst-cidrs={{- .Values.networking.skipConnTrackDstCIDRs | default ""}}
           - --enable-dns-name-resolver={{- .Values.components.enableDNSNameResolver }}
           securityContext:
@@ -405,6 +438,9 @@ spec:
               ephemeral-storage: {{ index .Values "kubeOvnController" "limits" "ephemeralStorage" }}
       nodeSelector:
         kubernetes.io/os: "linux"
+        {{- range $key, $value := .Values.placement.kubeOvnController.nodeSelector }}
+        {{ $key | quote }}: {{ $value | quote }}
+        {{- end }}
       volumes:
         - name: localtime
           hostPath:
@@ -453,15 +489,30 @@ spec:
           operator: Exists
         - key: CriticalAddonsOnly
           operator: Exists
+        {{- range .Values.placement.ovnICController.tolerations }}
+        - {{ toJson . }}
+        {{- end }}
       affinity:
+        {{- $affinity := .Values.placement.ovnICController.affinity | default dict }}
+        {{- if $affinity.podAntiAffinity }}
+        podAntiAffinity: {{ toJson $affinity.podAntiAffinity }}
+        {{- else }}
         podAntiAffinity:
           requiredDuringSchedulingIgnoredDuringExecution:
             - labelSelector:
                 matchLabels:
                   app: ovn-ic-controller
               topologyKey: kubernetes.io/hostname
-        {{- include "kubeovn.masterNodeAffinity" . | nindent 8 }}      
-      priorityClassName: system-cluster-critical
+        {{- end }}
+        {{- if $affinity.nodeAffinity }}
+        nodeAffinity: {{ toJson $affinity.nodeAffinity }}
+        {{- else }}
+        {{- include "kubeovn.masterNodeAffinity" . | nindent 8 }}
+        {{- end }}
+        {{- with $affinity.podAffinity }}
+        podAffinity: {{ toJson . }}
+        {{- end }}
+      priorityClassName: {{ .Values.placement.ovnICController.priorityClassName | default "system-cluster-critical" }}
       serviceAccountName: ovn
       automountServiceAccountToken: true
       hostNetwork: true
@@ -470,7 +521,7 @@ spec:
           type: RuntimeDefault
       initContainers:
         - name: hostpath-init
-          image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+          image: {{ include "kubeovn.images.kubeovn" . }}
           imagePullPolicy: {{ .Values.imagePullPolicy }}
           command:
             - sh
@@ -492,7 +543,7 @@ spec:
               mountPath: /var/log/kube-ovn
       containers:
         - name: ovn-ic-controller
-          image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+          image: {{ include "kubeovn.images.kubeovn" . }}
           imagePullPolicy: {{ .Values.imagePullPolicy }}
           command: ["/kube-ovn/start-ic-controller.sh"]
           args:
@@ -537,6 +588,9 @@ spec:
               name: kube-ovn-log
       nodeSelector:
         kubernetes.io/os: "linux"
+        {{- range $key, $value := .Values.placement.ovnICController.nodeSelector }}
+        {{ $key | quote }}: {{ $value | quote }}
+        {{- end }}
       volumes:
         - name: host-run-ovn
           hostPath:
@@ -586,15 +640,30 @@ spec:
           operator: Exists
         - key: CriticalAddonsOnly
           operator: Exists
+        {{- range .Values.placement.kubeOvnMonitor.tolerations }}
+        - {{ toJson . }}
+        {{- end }}
       affinity:
+        {{- $affinity := .Values.placement.kubeOvnMonitor.affinity | default dict }}
+        {{- if $affinity.podAntiAffinity }}
+        podAntiAffinity: {{ toJson $affinity.podAntiAffinity }}
+        {{- else }}
         podAntiAffinity:
           requiredDuringSchedulingIgnoredDuringExecution:
             - labelSelector:
                 matchLabels:
                   app: kube-ovn-monitor
               topologyKey: kubernetes.io/hostname
-        {{- include "kubeovn.masterNodeAffinity" . | nindent 8 }}      
-      priorityClassName: system-cluster-critical
+        {{- end }}
+        {{- if $affinity.nodeAffinity }}
+        nodeAffinity: {{ toJson $affinity.nodeAffinity }}
+        {{- else }}
+        {{- include "kubeovn.masterNodeAffinity" . | nindent 8 }}
+        {{- end }}
+        {{- with $affinity.podAffinity }}
+        podAffinity: {{ toJson . }}
+        {{- end }}
+      priorityClassName: {{ .Values.placement.kubeOvnMonitor.priorityClassName | default "system-cluster-critical" }}
       serviceAccountName: kube-ovn-app
       automountServiceAccountToken: true
       hostNetwork: true
@@ -603,7 +672,7 @@ spec:
           type: RuntimeDefault
       initContainers:
         - name: hostpath-init
-          image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+          image: {{ include "kubeovn.images.kubeOvnMonitor" . }}
           imagePullPolicy: {{ .Values.imagePullPolicy }}
           command:
             - sh
@@ -621,7 +690,7 @@ spec:
               mountPath: /var/log/kube-ovn
       containers:
         - name: kube-ovn-monitor
-          image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+          image: {{ include "kubeovn.images.kubeOvnMonitor" . }}
           imagePullPolicy: {{ .Values.imagePullPolicy }}
           command: ["/kube-ovn/start-ovn-monitor.sh"]
           args:
@@ -707,6 +776,9 @@ spec:
             timeoutSeconds: 5
       nodeSelector:
         kubernetes.io/os: "linux"
+        {{- range $key, $value := .Values.placement.kubeOvnMonitor.nodeSelector }}
+        {{ $key | quote }}: {{ $value | quote }}
+        {{- end }}
       volumes:
         - name: host-run-ovn
           hostPath:
@@ -767,7 +839,7 @@ spec:
           type: RuntimeDefault
       containers:
         - name: kube-ovn-webhook
-          image: {{ .Values.global.registry.address }}/{{ .Values.global.images.kubeovn.repository }}:{{ .Values.global.images.kubeovn.tag }}
+          image: {{ include "kubeovn.images.kubeovn" . }}
           imagePullPolicy: {{ .Values.imagePullPolicy }}
           command:
             - /kube-ovn/kube-ovn-webhook
@@ -814,7 +886,11 @@ spec:
         - name: cert
           secret:
             defaultMode: 420
+            {{- if .Values.certManager.enabled }}
+            secretName: kube-ovn-webhook-certs
+            {{- else }}
             secretName: webhook-certs
+            {{- end }}
       nodeSelector:
         kubernetes.io/os: "linux"`
 
@@ -1,25 +1,113 @@
 package templates
 
 import (
+	"reflect"
+
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-var OrderedObjectList = map[client.Object][]string{
-	&apiextensionsv1.CustomResourceDefinition{}: CRDList,
-	&corev1.Secret{}:             SecretList,
-	&corev1.ServiceAccount{}:     ServiceAccountList,
-	&rbacv1.RoleBinding{}:        RoleBindingList,
-	&rbacv1.ClusterRole{}:        ClusterRoleList,
-	&rbacv1.Role{}:               RoleList,
-	&rbacv1.ClusterRoleBinding{}: ClusterRoleBindingList,
-	&corev1.ConfigMap{}:          ConfigMapList,
-	&appsv1.Deployment{}:         DeploymentList,
-	&appsv1.DaemonSet{}:          DaemonsetList,
-	&corev1.Service{}:            ServicesList,
-	&admissionregistrationv1.ValidatingWebhookConfiguration{}: ValidatingWebhookConfigurationList,
+const (
+	PhaseCustomResourceDefinitions = "CustomResourceDefinitions"
+	PhaseBaseResources             = "BaseResources"
+	PhaseOVNCentral                = "OVNCentral"
+	PhaseOVSOVN                    = "OVSOVN"
+	PhaseKubeOVN                   = "KubeOVN"
+	PhaseMonitoring                = "Monitoring"
+	// PhaseComplete is reported once all phases have been applied and are healthy
+	PhaseComplete = "Complete"
+)
+
+// ObjectTemplates pairs an object type with the templates used to render objects of that type
+type ObjectTemplates struct {
+	ObjectType client.Object
+	Templates  []string
+}
+
+// Phase is a group of objects which are applied together. Phases are applied in order
+// and all objects in a phase need to be healthy before the next phase is applied
+type Phase struct {
+	Name    string
+	Objects []ObjectTemplates
+}
+
+var OrderedPhases = []Phase{
+	{
+		Name: PhaseCustomResourceDefinitions,
+		Objects: []ObjectTemplates{
+			{ObjectType: &apiextensionsv1.CustomResourceDefinition{}, Templates: CRDList},
+		},
+	},
+	{
+		Name: PhaseBaseResources,
+		Objects: []ObjectTemplates{
+			{ObjectType: &corev1.ServiceAccount{}, Templates: ServiceAccountList},
+			{ObjectType: &rbacv1.ClusterRole{}, Templates: ClusterRoleList},
+			{ObjectType: &rbacv1.ClusterRoleBinding{}, Templates: ClusterRoleBindingList},
+			{ObjectType: &rbacv1.Role{}, Templates: RoleList},
+			{ObjectType: &rbacv1.RoleBinding{}, Templates: RoleBindingList},
+			// cert-manager types are not registered in the scheme and are rendered as unstructured objects
+			{ObjectType: &unstructured.Unstructured{}, Templates: CertificateList},
+			{ObjectType: &corev1.ConfigMap{}, Templates: ConfigMapList},
+		},
+	},
+	{
+		Name: PhaseOVNCentral,
+		Objects: []ObjectTemplates{
+			{ObjectType: &corev1.Service{}, Templates: []string{ovn_nb_service, ovn_sb_service, ovn_northd_service}},
+			{ObjectType: &appsv1.Deployment{}, Templates: []string{ovn_central_deployment}},
+		},
+	},
+	{
+		Name: PhaseOVSOVN,
+		Objects: []ObjectTemplates{
+			{ObjectType: &appsv1.DaemonSet{}, Templates: []string{ovs_ovn_daemonset, ovs_ovn_dpdk_daemonset}},
+		},
+	},
+	{
+		Name: PhaseKubeOVN,
+		Objects: []ObjectTemplates{
+			{ObjectType: &corev1.Service{}, Templates: []string{kube_ovn_controller_service, kube_ovn_cni_service, kubeovn_webhook_service}},
+			{ObjectType: &appsv1.Deployment{}, Templates: []string{kube_ovn_controller_deployment, ovn_ic_controller_deployment, kubeovn_webhook_deployment}},
+			{ObjectType: &appsv1.DaemonSet{}, Templates: []string{kube_ovn_cni_daemonset}},
+		},
+	},
+	{
+		Name: PhaseMonitoring,
+		Objects: []ObjectTemplates{
+			{ObjectType: &corev1.Service{}, Templates: []string{kube_ovn_monitor_service, kube_ovn_pinger_service}},
+			{ObjectType: &appsv1.Deployment{}, Templates: []string{kube_ovn_monitor_deployment}},
+			{ObjectType: &appsv1.DaemonSet{}, Templates: []string{kube_ovn_pinger_daemonsets}},
+			// webhook configuration is only applied once kube-ovn-webhook is healthy, as
+			// the webhook would otherwise reject requests while it is unavailable
+			{ObjectType: &admissionregistrationv1.ValidatingWebhookConfiguration{}, Templates: ValidatingWebhookConfigurationList},
+		},
+	},
+}
+
+// ObjectTypes returns the unique typed objects rendered across all phases
+func ObjectTypes() []client.Object {
+	var objectTypes []client.Object
+	seen := make(map[reflect.Type]bool)
+	for _, phase := range OrderedPhases {
+		for _, v := range phase.Objects {
+			// unstructured objects are not watched, as the cert-manager crds may not be installed. their
+			// readiness is polled while the phase is waiting
+			if _, ok := v.ObjectType.(*unstructured.Unstructured); ok {
+				continue
+			}
+			t := reflect.TypeOf(v.ObjectType)
+			if seen[t] {
+				continue
+			}
+			seen[t] = true
+			objectTypes = append(objectTypes, v.ObjectType)
+		}
+	}
+	return objectTypes
 }
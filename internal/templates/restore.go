@@ -0,0 +1,21 @@
+package templates
+
+// MoveDatabases moves database files aside, so ovn-central joins the raft cluster instead of starting the
+// databases being replaced
+var MoveDatabases = `set -e
+cd {{ .Dir }}
+for db in {{ join .Files " " }}
+do
+  if [ -f "$db" ]
+  then
+    mv "$db" "$db.{{ .Suffix }}"
+    echo "moved $db to $db.{{ .Suffix }}"
+  fi
+done`
+
+// CreateCluster converts a standalone snapshot into a single member raft database
+var CreateCluster = `set -e
+cd {{ .Dir }}
+rm -f {{ .File }}
+ovsdb-tool create-cluster {{ .File }} {{ .File }}.standalone {{ .Protocol }}:[{{ .Address }}]:{{ .Port }}
+ovsdb-tool db-is-clustered {{ .File }}`
@@ -5,6 +5,10 @@ var (
 kind: ValidatingWebhookConfiguration
 metadata:
   name: kube-ovn-webhook
+  {{- if .Values.certManager.enabled }}
+  annotations:
+    cert-manager.io/inject-ca-from: {{ .Values.namespace }}/kube-ovn-webhook
+  {{- end }}
 webhooks:
 - name: pod-ip-validating.kube-ovn.io
   rules:
@@ -63,7 +67,9 @@ webhooks:
   sideEffects: None
   timeoutSeconds: 5
   clientConfig:
+    {{- if not .Values.certManager.enabled }}
     caBundle: {{ .Values.caCert | b64enc}}
+    {{- end }}
     service:
       namespace: kube-system
       name: kube-ovn-webhook
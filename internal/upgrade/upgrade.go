@@ -0,0 +1,68 @@
+package upgrade
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+// TargetVersion returns the kube-ovn version which should be deployed for a configuration.
+// spec.version takes precedence, followed by the currently deployed version, so that upgrading the
+// operator does not implicitly upgrade kube-ovn. defaultVersion is only used for new installs
+func TargetVersion(config *ovnoperatorv1.Configuration, defaultVersion string) string {
+	if config.Spec.Version != "" {
+		return config.Spec.Version
+	}
+	if config.Status.CurrentVersion != "" {
+		return config.Status.CurrentVersion
+	}
+	return defaultVersion
+}
+
+// VersionFromImage returns the kube-ovn version from the tag of an image, or an empty string if the image is
+// referenced by digest only or the tag is not a version
+func VersionFromImage(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	idx := strings.LastIndex(image, ":")
+	if idx == -1 || strings.Contains(image[idx:], "/") {
+		return ""
+	}
+	tag := image[idx+1:]
+	if _, err := semver.NewVersion(tag); err != nil {
+		return ""
+	}
+	return tag
+}
+
+// ValidateUpgradePath checks if kube-ovn can be moved from the current to the target version.
+// Patch versions can be changed freely, minor versions can only be upgraded one at a time
+// and major version changes or minor version downgrades are not supported
+func ValidateUpgradePath(current, target string) error {
+	if current == "" || current == target {
+		return nil
+	}
+
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return fmt.Errorf("error parsing current version %s: %w", current, err)
+	}
+	targetVersion, err := semver.NewVersion(target)
+	if err != nil {
+		return fmt.Errorf("error parsing target version %s: %w", target, err)
+	}
+
+	if targetVersion.Major() != currentVersion.Major() {
+		return fmt.Errorf("changing major version from %s to %s is not supported", current, target)
+	}
+	if targetVersion.Minor() < currentVersion.Minor() {
+		return fmt.Errorf("downgrade from %s to %s is not supported", current, target)
+	}
+	if targetVersion.Minor() > currentVersion.Minor()+1 {
+		return fmt.Errorf("upgrade from %s to %s skips minor versions, upgrade to v%d.%d.x first",
+			current, target, currentVersion.Major(), currentVersion.Minor()+1)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ovnoperatorv1 "github.com/harvester/kubeovn-operator/api/v1"
+)
+
+func Test_ValidateUpgradePath(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		current       string
+		target        string
+		expectedError string
+	}{
+		{name: "new install", current: "", target: "v1.14.0"},
+		{name: "same version", current: "v1.14.0", target: "v1.14.0"},
+		{name: "patch upgrade", current: "v1.14.0", target: "v1.14.4"},
+		{name: "patch downgrade", current: "v1.14.4", target: "v1.14.0"},
+		{name: "minor upgrade", current: "v1.14.4", target: "v1.15.0"},
+		{name: "minor skip", current: "v1.14.4", target: "v1.16.2", expectedError: "upgrade to v1.15.x first"},
+		{name: "minor downgrade", current: "v1.15.0", target: "v1.14.4", expectedError: "downgrade"},
+		{name: "major upgrade", current: "v1.15.0", target: "v2.0.0", expectedError: "major version"},
+		{name: "invalid target", current: "v1.15.0", target: "latest", expectedError: "error parsing target version"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := require.New(t)
+			err := ValidateUpgradePath(tc.current, tc.target)
+			if tc.expectedError == "" {
+				assert.NoError(err)
+				return
+			}
+			assert.ErrorContains(err, tc.expectedError)
+		})
+	}
+}
+
+func Test_TargetVersion(t *testing.T) {
+	assert := require.New(t)
+	config := &ovnoperatorv1.Configuration{}
+	assert.Equal("v1.16.2", TargetVersion(config, "v1.16.2"), "expected default version for new installs")
+	config.Status.CurrentVersion = "v1.15.0"
+	assert.Equal("v1.15.0", TargetVersion(config, "v1.16.2"), "expected current version to be retained")
+	config.Spec.Version = "v1.16.0"
+	assert.Equal("v1.16.0", TargetVersion(config, "v1.16.2"), "expected spec version to take precedence")
+}
+
+func Test_VersionFromImage(t *testing.T) {
+	assert := require.New(t)
+	assert.Equal("v1.14.4", VersionFromImage("docker.io/kubeovn/kube-ovn:v1.14.4"))
+	assert.Equal("v1.14.4", VersionFromImage("registry.local:5000/kubeovn/kube-ovn:v1.14.4"))
+	assert.Equal("v1.14.4", VersionFromImage("kubeovn/kube-ovn:v1.14.4@sha256:3f2a"))
+	assert.Empty(VersionFromImage("registry.local:5000/kubeovn/kube-ovn"), "expected no version without a tag")
+	assert.Empty(VersionFromImage("kubeovn/kube-ovn@sha256:3f2a"), "expected no version for digest references")
+	assert.Empty(VersionFromImage("kubeovn/kube-ovn:latest"), "expected no version for non semver tags")
+}
@@ -19,14 +19,22 @@ package v1
 import (
 	"context"
 	"fmt"
+	"net"
+	"slices"
+	"strings"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	kubeovnv1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/upgrade"
 )
 
 // nolint:unused
@@ -99,12 +107,18 @@ var (
 			EphemeralStorage: resource.MustParse("1Gi"),
 		},
 	}
+
+	supportedNetworkTypes = []string{"geneve", "vlan"}
+	supportedTunnelTypes  = []string{"geneve", "vxlan", "stt"}
+	supportedPodNicTypes  = []string{"veth-pair", "internal-port"}
 )
 
 // SetupConfigurationWebhookWithManager registers the webhook for Configuration in the manager.
-func SetupConfigurationWebhookWithManager(mgr ctrl.Manager) error {
+// namespace is the operator namespace, which is the only namespace the controllers watch for configurations
+func SetupConfigurationWebhookWithManager(mgr ctrl.Manager, namespace string) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&kubeovnv1.Configuration{}).
 		WithDefaulter(&ConfigurationCustomDefaulter{}).
+		WithValidator(&ConfigurationCustomValidator{Namespace: namespace}).
 		Complete()
 }
 
@@ -172,3 +186,279 @@ func applyDefaults(resource, defaultValues kubeovnv1.ResourceSpec) kubeovnv1.Res
 	}
 	return resource
 }
+
+// +kubebuilder:webhook:path=/validate-kubeovn-io-v1-configuration,mutating=false,failurePolicy=fail,sideEffects=None,groups=kubeovn.io,resources=configurations,verbs=create;update,versions=v1,name=vconfiguration-v1.kb.io,admissionReviewVersions=v1
+
+// ConfigurationCustomValidator struct is responsible for validating the Configuration resource
+// when it is created or updated. Invalid specs are rejected before they are rendered and rolled out
+type ConfigurationCustomValidator struct {
+	// Namespace is the operator namespace the default configuration needs to be created in
+	Namespace string
+}
+
+var _ webhook.CustomValidator = &ConfigurationCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Configuration.
+func (v *ConfigurationCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	configuration, ok := obj.(*kubeovnv1.Configuration)
+	if !ok {
+		return nil, fmt.Errorf("expected a Configuration object but got %T", obj)
+	}
+	configurationlog.Info("Validation for Configuration upon creation", "name", configuration.GetName())
+
+	if err := validateSingleton(configuration, v.Namespace); err != nil {
+		return nil, err
+	}
+	return nil, validateConfiguration(configuration)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Configuration.
+func (v *ConfigurationCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	configuration, ok := newObj.(*kubeovnv1.Configuration)
+	if !ok {
+		return nil, fmt.Errorf("expected a Configuration object for the newObj but got %T", newObj)
+	}
+	configurationlog.Info("Validation for Configuration upon update", "name", configuration.GetName())
+
+	// objects being deleted only have finalizers removed, there is no need to block this
+	if !configuration.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	return nil, validateConfiguration(configuration)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Configuration.
+func (v *ConfigurationCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateSingleton ensures only one Configuration can exist. the node controller and bootstrapper always
+// resolve the configuration by its default name in the operator namespace, so a configuration with any other
+// name or namespace would be silently ignored
+func validateSingleton(config *kubeovnv1.Configuration, namespace string) error {
+	groupResource := kubeovnv1.GroupVersion.WithResource("configurations").GroupResource()
+	if config.GetName() != kubeovnv1.DefaultConfigurationName {
+		return apierrors.NewForbidden(groupResource, config.GetName(),
+			fmt.Errorf("only a single Configuration named %q is supported, please update the existing %q Configuration instead", kubeovnv1.DefaultConfigurationName, kubeovnv1.DefaultConfigurationName))
+	}
+	if config.GetNamespace() != namespace {
+		return apierrors.NewForbidden(groupResource, config.GetName(),
+			fmt.Errorf("the %q Configuration needs to be created in the operator namespace %q", kubeovnv1.DefaultConfigurationName, namespace))
+	}
+	return nil
+}
+
+// validateConfiguration aggregates all validation errors for a configuration into a single Invalid error
+func validateConfiguration(config *kubeovnv1.Configuration) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if _, err := labels.ConvertSelectorToLabelsMap(config.Spec.MasterNodesLabel); err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("masterNodesLabel"), config.Spec.MasterNodesLabel, err.Error()))
+	}
+
+	// status is not updated through the main resource, so currentVersion reflects the deployed version
+	if config.Spec.Version != "" {
+		if err := upgrade.ValidateUpgradePath(config.Status.CurrentVersion, config.Spec.Version); err != nil {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("version"), err.Error()))
+		}
+		// the image tag would otherwise be rolled out while status.currentVersion reports spec.version
+		tag := config.Spec.Global.Images.KubeOVNImage.Tag
+		if tag != "" && tag != config.Spec.Version {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("global", "images", "kubeovn", "tag"), tag,
+				fmt.Sprintf("must match spec.version %s, or be left empty to default to it", config.Spec.Version)))
+		}
+	}
+
+	networkingPath := specPath.Child("networking")
+	allErrs = append(allErrs, validateEnum(networkingPath.Child("networkType"), config.Spec.Networking.NetworkType, supportedNetworkTypes)...)
+	allErrs = append(allErrs, validateEnum(networkingPath.Child("tunnelType"), config.Spec.Networking.TunnelType, supportedTunnelTypes)...)
+	allErrs = append(allErrs, validateEnum(networkingPath.Child("podNicType"), config.Spec.Networking.PodNicType, supportedPodNicTypes)...)
+
+	switch config.Spec.Networking.NetStack {
+	case "ipv4", "":
+		allErrs = append(allErrs, validateNetworkStack(specPath.Child("ipv4"), config.Spec.IPv4, []bool{false})...)
+	case "ipv6":
+		allErrs = append(allErrs, validateNetworkStack(specPath.Child("ipv6"), config.Spec.IPv6, []bool{true})...)
+	case "dual_stack":
+		allErrs = append(allErrs, validateNetworkStack(specPath.Child("dualStack"), config.Spec.DualStack, []bool{false, true})...)
+	default:
+		allErrs = append(allErrs, field.NotSupported(networkingPath.Child("netStack"), config.Spec.Networking.NetStack, []string{"ipv4", "ipv6", "dual_stack"}))
+	}
+
+	resources := map[string]kubeovnv1.ResourceSpec{
+		"ovnCentral":        config.Spec.OVNCentral,
+		"ovsOVN":            config.Spec.OVSOVN,
+		"kubeOvnController": config.Spec.KubeOVNController,
+		"kubeOvnCNI":        config.Spec.KubeOVNCNI,
+		"kubeOvnPinger":     config.Spec.KubeOVNPinger,
+		"kubeOvnMonitor":    config.Spec.KubeOVNMonitor,
+	}
+	// iterate in a stable order so that error messages are deterministic
+	resourceNames := make([]string, 0, len(resources))
+	for name := range resources {
+		resourceNames = append(resourceNames, name)
+	}
+	slices.Sort(resourceNames)
+	for _, name := range resourceNames {
+		allErrs = append(allErrs, validateResourceSpec(specPath.Child(name), resources[name])...)
+	}
+
+	chassisGCPath := specPath.Child("maintenance", "chassisGC")
+	if config.Spec.Maintenance.ChassisGC.Interval.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(chassisGCPath.Child("interval"), config.Spec.Maintenance.ChassisGC.Interval.Duration.String(), "must not be negative"))
+	}
+	if config.Spec.Maintenance.ChassisGC.GracePeriod.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(chassisGCPath.Child("gracePeriod"), config.Spec.Maintenance.ChassisGC.GracePeriod.Duration.String(), "must not be negative"))
+	}
+
+	compaction := config.Spec.Maintenance.Compaction
+	compactionPath := specPath.Child("maintenance", "compaction")
+	if compaction.FileSizeThreshold != nil && compaction.FileSizeThreshold.Sign() < 0 {
+		allErrs = append(allErrs, field.Invalid(compactionPath.Child("fileSizeThreshold"), compaction.FileSizeThreshold.String(), "must not be negative"))
+	}
+	if compaction.LogLengthThreshold < 0 {
+		allErrs = append(allErrs, field.Invalid(compactionPath.Child("logLengthThreshold"), compaction.LogLengthThreshold, "must not be negative"))
+	}
+	if compaction.MinInterval.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(compactionPath.Child("minInterval"), compaction.MinInterval.Duration.String(), "must not be negative"))
+	}
+	if config.Spec.Maintenance.AutoRecoverRaftMembers.GracePeriod.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("maintenance", "autoRecoverRaftMembers", "gracePeriod"),
+			config.Spec.Maintenance.AutoRecoverRaftMembers.GracePeriod.Duration.String(), "must not be negative"))
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(kubeovnv1.GroupVersion.WithKind(kubeovnv1.Kind).GroupKind(), config.GetName(), allErrs)
+}
+
+func validateEnum(path *field.Path, value string, supported []string) field.ErrorList {
+	// empty values are defaulted by the CRD schema
+	if value == "" || slices.Contains(supported, value) {
+		return nil
+	}
+	return field.ErrorList{field.NotSupported(path, value, supported)}
+}
+
+// validateNetworkStack checks cidrs and gateway for a network stack. families contains one entry per expected
+// comma separated value, with true indicating an ipv6 value
+func validateNetworkStack(path *field.Path, stack kubeovnv1.NetworkStackSpec, families []bool) field.ErrorList {
+	var allErrs field.ErrorList
+	cidrFields := []struct {
+		name  string
+		value string
+	}{
+		{name: "podCIDR", value: stack.PodCIDR},
+		{name: "serviceCIDR", value: stack.ServiceCIDR},
+		{name: "joinCIDR", value: stack.JoinCIDR},
+	}
+
+	parsed := make(map[string][]*net.IPNet, len(cidrFields))
+	for _, cidrField := range cidrFields {
+		networks, errs := parseCIDRs(path.Child(cidrField.name), cidrField.value, families)
+		allErrs = append(allErrs, errs...)
+		parsed[cidrField.name] = networks
+	}
+
+	for i := range cidrFields {
+		for j := i + 1; j < len(cidrFields); j++ {
+			for _, a := range parsed[cidrFields[i].name] {
+				for _, b := range parsed[cidrFields[j].name] {
+					if a.Contains(b.IP) || b.Contains(a.IP) {
+						allErrs = append(allErrs, field.Invalid(path.Child(cidrFields[j].name), cidrFields[j].value,
+							fmt.Sprintf("%s overlaps with %s %s", b.String(), cidrFields[i].name, a.String())))
+					}
+				}
+			}
+		}
+	}
+
+	gatewayPath := path.Child("podGateway")
+	gateways := splitList(stack.PodGateway)
+	if len(gateways) != len(families) {
+		return append(allErrs, field.Invalid(gatewayPath, stack.PodGateway, fmt.Sprintf("expected %d comma separated address(es)", len(families))))
+	}
+	for i, gateway := range gateways {
+		ip := net.ParseIP(gateway)
+		if ip == nil {
+			allErrs = append(allErrs, field.Invalid(gatewayPath, gateway, "invalid ip address"))
+			continue
+		}
+		if (ip.To4() == nil) != families[i] {
+			allErrs = append(allErrs, field.Invalid(gatewayPath, gateway, fmt.Sprintf("expected an %s address", familyName(families[i]))))
+			continue
+		}
+		podCIDRs := parsed["podCIDR"]
+		if i < len(podCIDRs) && !podCIDRs[i].Contains(ip) {
+			allErrs = append(allErrs, field.Invalid(gatewayPath, gateway, fmt.Sprintf("gateway is not within podCIDR %s", podCIDRs[i].String())))
+		}
+	}
+	return allErrs
+}
+
+// parseCIDRs parses a comma separated cidr list and ensures it matches the expected address families
+func parseCIDRs(path *field.Path, value string, families []bool) ([]*net.IPNet, field.ErrorList) {
+	cidrs := splitList(value)
+	if len(cidrs) != len(families) {
+		return nil, field.ErrorList{field.Invalid(path, value, fmt.Sprintf("expected %d comma separated cidr(s)", len(families)))}
+	}
+
+	var allErrs field.ErrorList
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(path, cidr, err.Error()))
+			continue
+		}
+		if (network.IP.To4() == nil) != families[i] {
+			allErrs = append(allErrs, field.Invalid(path, cidr, fmt.Sprintf("expected an %s cidr", familyName(families[i]))))
+			continue
+		}
+		networks = append(networks, network)
+	}
+	return networks, allErrs
+}
+
+// validateResourceSpec ensures requests do not exceed limits when both are set
+func validateResourceSpec(path *field.Path, spec kubeovnv1.ResourceSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	quantities := []struct {
+		name    string
+		request resource.Quantity
+		limit   resource.Quantity
+	}{
+		{name: "cpu", request: spec.Requests.CPU, limit: spec.Limits.CPU},
+		{name: "memory", request: spec.Requests.Memory, limit: spec.Limits.Memory},
+		{name: "ephemeralStorage", request: spec.Requests.EphemeralStorage, limit: spec.Limits.EphemeralStorage},
+	}
+	for _, q := range quantities {
+		if q.request.IsZero() || q.limit.IsZero() {
+			continue
+		}
+		if q.request.Cmp(q.limit) > 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("requests", q.name), q.request.String(),
+				fmt.Sprintf("must be less than or equal to %s limit %s", q.name, q.limit.String())))
+		}
+	}
+	return allErrs
+}
+
+func splitList(value string) []string {
+	var result []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func familyName(ipv6 bool) string {
+	if ipv6 {
+		return "ipv6"
+	}
+	return "ipv4"
+}
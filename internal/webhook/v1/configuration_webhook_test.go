@@ -17,10 +17,14 @@ limitations under the License.
 package v1
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	kubeovnv1 "github.com/harvester/kubeovn-operator/api/v1"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func Test_ConfigurationDefaults(t *testing.T) {
@@ -31,3 +35,196 @@ func Test_ConfigurationDefaults(t *testing.T) {
 	assert.Equal(config.Spec.OVNCentral, ovnCentralDefaultResourceSpec, "defaults applied")
 
 }
+
+const testOperatorNamespace = "kube-system"
+
+func validConfiguration() *kubeovnv1.Configuration {
+	config := &kubeovnv1.Configuration{}
+	config.Name = kubeovnv1.DefaultConfigurationName
+	config.Namespace = testOperatorNamespace
+	config.Spec.MasterNodesLabel = "node-role.kubernetes.io/control-plane=true"
+	config.Spec.Networking = kubeovnv1.NetworkingSpec{
+		NetStack:    "ipv4",
+		NetworkType: "geneve",
+		TunnelType:  "vxlan",
+		PodNicType:  "veth-pair",
+	}
+	config.Spec.IPv4 = kubeovnv1.NetworkStackSpec{
+		PodCIDR:     "10.52.0.0/16",
+		PodGateway:  "10.52.0.1",
+		ServiceCIDR: "10.53.0.0/16",
+		JoinCIDR:    "100.64.0.0/16",
+	}
+	config.Spec.DualStack = kubeovnv1.NetworkStackSpec{
+		PodCIDR:     "10.16.0.0/16,fd00:10:16::/112",
+		PodGateway:  "10.16.0.1,fd00:10:16::1",
+		ServiceCIDR: "10.96.0.0/12,fd00:10:96::/112",
+		JoinCIDR:    "100.64.0.0/16,fd00:100:64::/112",
+	}
+	defaulter := &ConfigurationCustomDefaulter{}
+	defaulter.ApplyConfigurationDefaults(config)
+	return config
+}
+
+func Test_ConfigurationValidation(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		mutate        func(*kubeovnv1.Configuration)
+		expectedError string
+	}{
+		{
+			name:   "valid ipv4 configuration",
+			mutate: func(c *kubeovnv1.Configuration) {},
+		},
+		{
+			name: "valid dual stack configuration",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Spec.Networking.NetStack = "dual_stack"
+			},
+		},
+		{
+			name: "overlapping pod and service cidr",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Spec.IPv4.ServiceCIDR = "10.52.128.0/17"
+			},
+			expectedError: "overlaps with podCIDR",
+		},
+		{
+			name: "overlapping join cidr",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Spec.IPv4.JoinCIDR = "10.0.0.0/8"
+			},
+			expectedError: "spec.ipv4.joinCIDR",
+		},
+		{
+			name: "gateway outside pod cidr",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Spec.IPv4.PodGateway = "10.53.0.1"
+			},
+			expectedError: "gateway is not within podCIDR",
+		},
+		{
+			name: "ipv6 cidr for ipv4 stack",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Spec.IPv4.PodCIDR = "fd00:10:16::/112"
+			},
+			expectedError: "expected an ipv4 cidr",
+		},
+		{
+			name: "single family for dual stack",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Spec.Networking.NetStack = "dual_stack"
+				c.Spec.DualStack.PodCIDR = "10.16.0.0/16"
+			},
+			expectedError: "expected 2 comma separated cidr(s)",
+		},
+		{
+			name: "requests above limits",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Spec.KubeOVNCNI.Requests.Memory = resource.MustParse("2Gi")
+			},
+			expectedError: "spec.kubeOvnCNI.requests.memory",
+		},
+		{
+			name: "unknown tunnel type",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Spec.Networking.TunnelType = "gre"
+			},
+			expectedError: "spec.networking.tunnelType",
+		},
+		{
+			name: "unknown pod nic type",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Spec.Networking.PodNicType = "macvlan"
+			},
+			expectedError: "spec.networking.podNicType",
+		},
+		{
+			name: "invalid master nodes label",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Spec.MasterNodesLabel = "a=b=c"
+			},
+			expectedError: "spec.masterNodesLabel",
+		},
+		{
+			name: "supported minor version upgrade",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Status.CurrentVersion = "v1.14.4"
+				c.Spec.Version = "v1.15.0"
+			},
+		},
+		{
+			name: "minor version skip",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Status.CurrentVersion = "v1.14.4"
+				c.Spec.Version = "v1.16.0"
+			},
+			expectedError: "spec.version",
+		},
+		{
+			name: "image tag matching version",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Spec.Version = "v1.14.4"
+				c.Spec.Global.Images.KubeOVNImage.Tag = "v1.14.4"
+			},
+		},
+		{
+			name: "image tag conflicting with version",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Spec.Version = "v1.15.0"
+				c.Spec.Global.Images.KubeOVNImage.Tag = "v1.14.4"
+			},
+			expectedError: "spec.global.images.kubeovn.tag",
+		},
+		{
+			name: "negative chassis gc grace period",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Spec.Maintenance.ChassisGC.GracePeriod = metav1.Duration{Duration: -time.Hour}
+			},
+			expectedError: "spec.maintenance.chassisGC.gracePeriod",
+		},
+		{
+			name: "negative compaction log length threshold",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Spec.Maintenance.Compaction.LogLengthThreshold = -1
+			},
+			expectedError: "spec.maintenance.compaction.logLengthThreshold",
+		},
+		{
+			name: "negative raft member recovery grace period",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Spec.Maintenance.AutoRecoverRaftMembers.GracePeriod = metav1.Duration{Duration: -time.Minute}
+			},
+			expectedError: "spec.maintenance.autoRecoverRaftMembers.gracePeriod",
+		},
+		{
+			name: "non default configuration name",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Name = "secondary"
+			},
+			expectedError: "only a single Configuration named \"kubeovn\" is supported",
+		},
+		{
+			name: "default configuration outside the operator namespace",
+			mutate: func(c *kubeovnv1.Configuration) {
+				c.Namespace = "default"
+			},
+			expectedError: "needs to be created in the operator namespace \"kube-system\"",
+		},
+	}
+
+	validator := &ConfigurationCustomValidator{Namespace: testOperatorNamespace}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := require.New(t)
+			config := validConfiguration()
+			tc.mutate(config)
+			_, err := validator.ValidateCreate(context.TODO(), config)
+			if tc.expectedError == "" {
+				assert.NoError(err)
+				return
+			}
+			assert.ErrorContains(err, tc.expectedError)
+		})
+	}
+}
@@ -109,7 +109,7 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	err = SetupConfigurationWebhookWithManager(mgr)
+	err = SetupConfigurationWebhookWithManager(mgr, "kube-system")
 	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:webhook
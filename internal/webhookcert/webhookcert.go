@@ -0,0 +1,253 @@
+package webhookcert
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/pki"
+)
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;list;watch;update;patch
+
+const (
+	caCommonName = "kubeovn-operator-ca"
+	// caKeyKey is only present in secrets issued by the operator
+	caKeyKey = "ca.key"
+	// kubeOVNWebhookService is served using the operator webhook certificate
+	kubeOVNWebhookService = "kube-ovn-webhook"
+	// DefaultCheckInterval is how often the certificate is checked for rotation
+	DefaultCheckInterval = time.Hour
+	// maxEnsureAttempts bounds how often a secret written concurrently is re-read
+	maxEnsureAttempts = 3
+)
+
+// CertificateManager issues the webhook-certs secret used by the operator webhook and kube-ovn-webhook when no
+// valid certificate has been provided, and rotates certificates it has issued before they expire. Secrets issued
+// by cert-manager are left untouched. The caBundle of operator webhook configurations is kept in sync with the
+// ca issued by the operator
+type CertificateManager struct {
+	// Client should not be backed by the manager cache, as the certificate is needed before the manager is started
+	Client        client.Client
+	Namespace     string
+	ServiceName   string
+	CheckInterval time.Duration
+	Log           logr.Logger
+}
+
+// Start implements manager.Runnable and periodically checks the certificate for rotation
+func (m *CertificateManager) Start(ctx context.Context) error {
+	interval := m.CheckInterval
+	if interval == 0 {
+		interval = DefaultCheckInterval
+	}
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := m.Ensure(ctx); err != nil {
+			m.Log.Error(err, "error reconciling webhook certificate")
+		}
+	}, interval)
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, as only one replica should rotate certificates
+func (m *CertificateManager) NeedLeaderElection() bool {
+	return true
+}
+
+// Ensure issues a new certificate if the webhook secret is missing, invalid or about to expire. The ca is reused
+// unless it expires within the lifetime of a new certificate, in which case a new ca is generated and the previous
+// ca is retained in ca.crt until it expires, so clients trust both the old and new certificate during rotation.
+// Ensure runs on all replicas before leader election, so a secret written concurrently by another replica is
+// re-read and validated instead of failing
+func (m *CertificateManager) Ensure(ctx context.Context) error {
+	var err error
+	for range maxEnsureAttempts {
+		err = m.ensure(ctx)
+		if !apierrors.IsAlreadyExists(err) && !apierrors.IsConflict(err) {
+			return err
+		}
+		m.Log.Info("webhook certificate was written concurrently, re-reading", "secret", kubeovniov1.KubeOVNOperatorWebhookCertSecret, "reason", err.Error())
+	}
+	return err
+}
+
+func (m *CertificateManager) ensure(ctx context.Context) error {
+	now := time.Now()
+	secret := &corev1.Secret{}
+	err := m.Client.Get(ctx, types.NamespacedName{Name: kubeovniov1.KubeOVNOperatorWebhookCertSecret, Namespace: m.Namespace}, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error fetching secret %s: %w", kubeovniov1.KubeOVNOperatorWebhookCertSecret, err)
+	}
+	exists := err == nil
+	if _, ok := secret.Annotations[kubeovniov1.CertManagerCertificateAnnotation]; ok {
+		return nil
+	}
+
+	caBundle, _ := pki.ParseCertificates(secret.Data["ca.crt"])
+	if m.validServingCert(secret, caBundle, now) {
+		if _, ok := secret.Data[caKeyKey]; ok {
+			return m.syncCABundle(ctx, secret.Data["ca.crt"])
+		}
+		return nil
+	}
+
+	ca := pki.KeyPair{Cert: secret.Data["ca.crt"], Key: secret.Data[caKeyKey]}
+	caCert, _, err := pki.ParseKeyPair(ca)
+	if err != nil || now.Add(pki.LeafDuration).After(caCert.NotAfter) {
+		m.Log.Info("generating webhook ca", "secret", kubeovniov1.KubeOVNOperatorWebhookCertSecret)
+		if ca, err = pki.GenerateCA(caCommonName, now); err != nil {
+			return err
+		}
+		if caCert, err = pki.ParseCertificate(ca.Cert); err != nil {
+			return fmt.Errorf("error parsing generated ca: %w", err)
+		}
+	}
+
+	serving, err := pki.GenerateServingCert(ca, m.dnsNames(), now)
+	if err != nil {
+		return fmt.Errorf("error issuing webhook certificate: %w", err)
+	}
+
+	bundle := []*x509.Certificate{caCert}
+	for _, cert := range caBundle {
+		if cert.IsCA && now.Before(cert.NotAfter) {
+			bundle = append(bundle, cert)
+		}
+	}
+	encodedBundle := pki.EncodeCertificates(bundle...)
+
+	secret.Name = kubeovniov1.KubeOVNOperatorWebhookCertSecret
+	secret.Namespace = m.Namespace
+	secret.Data = map[string][]byte{
+		"ca.crt":                encodedBundle,
+		caKeyKey:                ca.Key,
+		corev1.TLSCertKey:       serving.Cert,
+		corev1.TLSPrivateKeyKey: serving.Key,
+	}
+	if exists {
+		err = m.Client.Update(ctx, secret)
+	} else {
+		err = m.Client.Create(ctx, secret)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing secret %s: %w", kubeovniov1.KubeOVNOperatorWebhookCertSecret, err)
+	}
+	m.Log.Info("issued webhook certificate", "secret", kubeovniov1.KubeOVNOperatorWebhookCertSecret)
+	return m.syncCABundle(ctx, encodedBundle)
+}
+
+// validServingCert checks the serving certificate is issued by the ca bundle, covers all services and is not due
+// for rotation
+func (m *CertificateManager) validServingCert(secret *corev1.Secret, caBundle []*x509.Certificate, now time.Time) bool {
+	if len(caBundle) == 0 {
+		return false
+	}
+	cert, _, err := pki.ParseKeyPair(pki.KeyPair{Cert: secret.Data[corev1.TLSCertKey], Key: secret.Data[corev1.TLSPrivateKeyKey]})
+	if err != nil || pki.Verify(cert, caBundle, now) != nil || pki.NeedsRotation(cert, now) {
+		return false
+	}
+	for _, service := range []string{m.ServiceName, kubeOVNWebhookService} {
+		if cert.VerifyHostname(fmt.Sprintf("%s.%s.svc", service, m.Namespace)) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *CertificateManager) dnsNames() []string {
+	var names []string
+	for _, service := range []string{m.ServiceName, kubeOVNWebhookService} {
+		names = append(names,
+			fmt.Sprintf("%s.%s.svc", service, m.Namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", service, m.Namespace),
+			fmt.Sprintf("%s.%s", service, m.Namespace),
+			service,
+		)
+	}
+	return names
+}
+
+// syncCABundle updates the caBundle of all operator webhooks. kube-ovn-webhook is rendered by the configuration
+// controller, which is triggered by changes to the secret
+func (m *CertificateManager) syncCABundle(ctx context.Context, caBundle []byte) error {
+	matches := func(config admissionregistrationv1.WebhookClientConfig) bool {
+		return config.Service != nil && config.Service.Namespace == m.Namespace && config.Service.Name == m.ServiceName &&
+			!bytes.Equal(config.CABundle, caBundle)
+	}
+
+	mutatingList := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	if err := m.Client.List(ctx, mutatingList); err != nil {
+		return fmt.Errorf("error listing mutating webhook configurations: %w", err)
+	}
+	for _, webhookConfig := range mutatingList.Items {
+		changed := false
+		for i := range webhookConfig.Webhooks {
+			if matches(webhookConfig.Webhooks[i].ClientConfig) {
+				webhookConfig.Webhooks[i].ClientConfig.CABundle = caBundle
+				changed = true
+			}
+		}
+		if err := m.updateWebhookConfiguration(ctx, &webhookConfig, changed); err != nil {
+			return err
+		}
+	}
+
+	validatingList := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	if err := m.Client.List(ctx, validatingList); err != nil {
+		return fmt.Errorf("error listing validating webhook configurations: %w", err)
+	}
+	for _, webhookConfig := range validatingList.Items {
+		changed := false
+		for i := range webhookConfig.Webhooks {
+			if matches(webhookConfig.Webhooks[i].ClientConfig) {
+				webhookConfig.Webhooks[i].ClientConfig.CABundle = caBundle
+				changed = true
+			}
+		}
+		if err := m.updateWebhookConfiguration(ctx, &webhookConfig, changed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *CertificateManager) updateWebhookConfiguration(ctx context.Context, obj client.Object, changed bool) error {
+	if !changed {
+		return nil
+	}
+	if err := m.Client.Update(ctx, obj); err != nil {
+		return fmt.Errorf("error updating caBundle in %s: %w", obj.GetName(), err)
+	}
+	m.Log.Info("updated webhook caBundle", "name", obj.GetName())
+	return nil
+}
+
+// WaitForCertificateFiles waits for the secret to be projected into the pod. The secret volume is optional,
+// so the files only appear once the kubelet syncs the volume after the secret has been issued
+func WaitForCertificateFiles(ctx context.Context, timeout time.Duration, paths ...string) error {
+	return wait.PollUntilContextTimeout(ctx, 2*time.Second, timeout, true, func(context.Context) (bool, error) {
+		for _, path := range paths {
+			if _, err := os.Stat(filepath.Clean(path)); err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return false, nil
+				}
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
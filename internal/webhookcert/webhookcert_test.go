@@ -0,0 +1,133 @@
+package webhookcert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	kubeovniov1 "github.com/harvester/kubeovn-operator/api/v1"
+	"github.com/harvester/kubeovn-operator/internal/pki"
+)
+
+const (
+	testNamespace   = "kube-system"
+	testServiceName = "kubeovn-operator-webhook-service"
+)
+
+func newTestManager(objs ...client.Object) *CertificateManager {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = admissionregistrationv1.AddToScheme(scheme)
+	return &CertificateManager{
+		Client:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Namespace:   testNamespace,
+		ServiceName: testServiceName,
+		Log:         zap.New(zap.UseDevMode(true)),
+	}
+}
+
+func fetchSecret(t *testing.T, m *CertificateManager) *corev1.Secret {
+	secret := &corev1.Secret{}
+	err := m.Client.Get(context.TODO(), types.NamespacedName{Name: kubeovniov1.KubeOVNOperatorWebhookCertSecret, Namespace: testNamespace}, secret)
+	require.NoError(t, err)
+	return secret
+}
+
+func Test_EnsureIssuesMissingCertificate(t *testing.T) {
+	assert := require.New(t)
+	webhookConfig := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubeovn-operator-validating-webhook-configuration"},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{
+				Name: "vconfiguration-v1.kb.io",
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{Namespace: testNamespace, Name: testServiceName},
+				},
+			},
+		},
+	}
+	m := newTestManager(webhookConfig)
+	assert.NoError(m.Ensure(context.TODO()))
+
+	secret := fetchSecret(t, m)
+	caBundle, err := pki.ParseCertificates(secret.Data["ca.crt"])
+	assert.NoError(err)
+	assert.True(m.validServingCert(secret, caBundle, time.Now()))
+	assert.Contains(secret.Data, caKeyKey)
+
+	updated := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	assert.NoError(m.Client.Get(context.TODO(), client.ObjectKeyFromObject(webhookConfig), updated))
+	assert.Equal(secret.Data["ca.crt"], updated.Webhooks[0].ClientConfig.CABundle)
+
+	// a valid certificate is not reissued
+	assert.NoError(m.Ensure(context.TODO()))
+	assert.Equal(secret.Data, fetchSecret(t, m).Data)
+}
+
+func Test_EnsureRotatesExpiringCertificate(t *testing.T) {
+	assert := require.New(t)
+	// certificates issued by a ca without a key are rotated using a new ca, and the previous ca remains trusted
+	issued := time.Now().Add(-pki.LeafDuration + pki.LeafRenewBefore/2)
+	ca, err := pki.GenerateCA("provided-ca", issued)
+	assert.NoError(err)
+	m := newTestManager()
+	serving, err := pki.GenerateServingCert(ca, m.dnsNames(), issued)
+	assert.NoError(err)
+	assert.NoError(m.Client.Create(context.TODO(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: kubeovniov1.KubeOVNOperatorWebhookCertSecret, Namespace: testNamespace},
+		Data:       map[string][]byte{"ca.crt": ca.Cert, corev1.TLSCertKey: serving.Cert, corev1.TLSPrivateKeyKey: serving.Key},
+	}))
+
+	assert.NoError(m.Ensure(context.TODO()))
+	secret := fetchSecret(t, m)
+	assert.NotEqual(serving.Cert, secret.Data[corev1.TLSCertKey])
+	caBundle, err := pki.ParseCertificates(secret.Data["ca.crt"])
+	assert.NoError(err)
+	assert.Len(caBundle, 2)
+	assert.Equal("provided-ca", caBundle[1].Subject.CommonName)
+}
+
+func Test_EnsureSkipsCertManagerSecret(t *testing.T) {
+	assert := require.New(t)
+	m := newTestManager(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        kubeovniov1.KubeOVNOperatorWebhookCertSecret,
+			Namespace:   testNamespace,
+			Annotations: map[string]string{kubeovniov1.CertManagerCertificateAnnotation: "webhook"},
+		},
+	})
+	assert.NoError(m.Ensure(context.TODO()))
+	assert.Empty(fetchSecret(t, m).Data)
+}
+
+func Test_EnsureRereadsConcurrentlyIssuedSecret(t *testing.T) {
+	assert := require.New(t)
+	// another replica issues the secret between the initial read and the create
+	racer := newTestManager()
+	m := *racer
+	m.Client = interceptor.NewClient(racer.Client.(client.WithWatch), interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if err := racer.Ensure(ctx); err != nil {
+				return err
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	})
+
+	assert.NoError(m.Ensure(context.TODO()))
+	secret := fetchSecret(t, racer)
+	caBundle, err := pki.ParseCertificates(secret.Data["ca.crt"])
+	assert.NoError(err)
+	assert.True(m.validServingCert(secret, caBundle, time.Now()))
+}